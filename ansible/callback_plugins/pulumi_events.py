@@ -0,0 +1,79 @@
+# writes the playbook events as JSON lines to PULUMI_EVENTS_FILE, pulumi reads them while the playbook runs
+from __future__ import absolute_import, division, print_function
+__metaclass__ = type
+
+DOCUMENTATION = '''
+    name: pulumi_events
+    type: notification
+    short_description: playbook events as JSON lines
+    description:
+      - Writes one JSON object per play, task, host result and the final stats to the file in PULUMI_EVENTS_FILE.
+'''
+
+import json
+import os
+import time
+
+from ansible.plugins.callback import CallbackBase
+
+
+class CallbackModule(CallbackBase):
+    CALLBACK_VERSION = 2.0
+    CALLBACK_TYPE = 'notification'
+    CALLBACK_NAME = 'pulumi_events'
+    CALLBACK_NEEDS_ENABLED = True
+
+    def __init__(self):
+        super(CallbackModule, self).__init__()
+        self.path = os.environ.get('PULUMI_EVENTS_FILE')
+        self.file = None
+        self.play = ''
+
+    def emit(self, event, **fields):
+        if self.file is None:
+            return
+        fields['event'] = event
+        fields['time'] = time.time()
+        self.file.write(json.dumps(fields) + '\n')
+        self.file.flush()
+
+    def v2_playbook_on_start(self, playbook):
+        if not self.path:
+            return
+        # a new file, the reader notices it and starts from the beginning
+        if os.path.exists(self.path):
+            os.remove(self.path)
+        self.file = open(self.path, 'w')
+        self.emit('playbook', playbook=os.path.basename(playbook._file_name))
+
+    def v2_playbook_on_play_start(self, play):
+        self.play = play.get_name()
+        self.emit('play', play=self.play)
+
+    def v2_playbook_on_task_start(self, task, is_conditional):
+        self.emit('task', play=self.play, task=task.get_name())
+
+    def result(self, event, result):
+        msg = result._result.get('msg', '') if event in ('failed', 'unreachable') else ''
+        self.emit(event, play=self.play, task=result._task.get_name(), host=result._host.get_name(),
+                  changed=bool(result._result.get('changed', False)), msg=str(msg))
+
+    def v2_runner_on_ok(self, result):
+        self.result('ok', result)
+
+    def v2_runner_on_failed(self, result, ignore_errors=False):
+        self.result('ignored' if ignore_errors else 'failed', result)
+
+    def v2_runner_on_unreachable(self, result):
+        self.result('unreachable', result)
+
+    def v2_playbook_on_stats(self, stats):
+        hosts = {}
+        for host in sorted(stats.processed.keys()):
+            s = stats.summarize(host)
+            hosts[host] = {'ok': s['ok'], 'changed': s['changed'], 'failed': s['failures'], 'unreachable': s['unreachable'], 'skipped': s['skipped'],
+                           'rescued': s.get('rescued', 0), 'ignored': s.get('ignored', 0)}
+        self.emit('stats', hosts=hosts)
+        if self.file is not None:
+            self.file.close()
+            self.file = None
@@ -0,0 +1,13 @@
+#!/bin/sh
+# prints the absolute offset of the system clock to its NTP source in milliseconds
+if command -v chronyc >/dev/null 2>&1 && chronyc -c tracking >/dev/null 2>&1; then
+  chronyc -c tracking | awk -F, '{ o = $5 * 1000; if (o < 0) o = -o; printf "%d\n", o }'
+else
+  timedatectl timesync-status | awk '/Offset:/ {
+    v = $2; sub(/^[+-]/, "", v)
+    if (v ~ /us$/) { sub(/us$/, "", v); v = v / 1000 }
+    else if (v ~ /ms$/) { sub(/ms$/, "", v) }
+    else if (v ~ /min$/) { sub(/min$/, "", v); v = v * 60000 }
+    else { sub(/s$/, "", v); v = v * 1000 }
+    printf "%d\n", v }'
+fi
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	"pulumi-hcloud-kubeadm/k8s"
+	"pulumi-hcloud-kubeadm/provider"
+)
+
+// component provider plugin, started by the engine with its address, or:
+//
+//	pulumi-resource-hcloudkubeadm schema <file>     write the package schema
+//	pulumi-resource-hcloudkubeadm check <topology>  round trip the clusters of a topology through the schema
+func main() {
+	if len(os.Args) > 2 && os.Args[1] == "schema" {
+		schema, err := provider.Schema()
+		if err == nil {
+			err = os.WriteFile(os.Args[2], append(schema, '\n'), 0644)
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("Cannot write the schema")
+		}
+		return
+	}
+	if len(os.Args) > 2 && os.Args[1] == "check" {
+		topology, err := k8s.ReadTopology(os.Args[2])
+		if err == nil {
+			err = provider.RoundTrip(topology)
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("Round trip of the topology failed")
+		}
+		log.Info().Msgf("Round trip of %s passed", os.Args[2])
+		return
+	}
+	// flags passed by the engine to every plugin, the logging ones are registered by the pulumi SDK
+	if flag.Lookup("logtostderr") == nil {
+		flag.Bool("logtostderr", false, "log to stderr")
+	}
+	if flag.Lookup("v") == nil {
+		flag.Int("v", 0, "log level")
+	}
+	flag.Bool("logflow", false, "flow the log level to child processes")
+	flag.String("tracing", "", "tracing endpoint")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal().Msg("Usage: pulumi-resource-hcloudkubeadm <engine address> | schema <file> | check <topology>")
+	}
+	if err := provider.Serve(flag.Arg(0), k8s.NewAnsibleProvisioner()); err != nil {
+		log.Fatal().Err(err).Msg("Provider stopped")
+	}
+}
@@ -0,0 +1,234 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	localPathProvisioner    = "local-path-provisioner"
+	defaultLocalPathVersion = "v0.0.26"
+)
+
+// built-in add-on of a cluster, all of them are enabled unless disabled in the topology
+type AddonDef struct {
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// chart version, or the release tag of local-path-provisioner, defaults to the latest
+	Version string `yaml:"version,omitempty"`
+	// helm values file, file name in ./vars
+	ValuesFile string                 `yaml:"values_file,omitempty"`
+	Values     map[string]interface{} `yaml:"values,omitempty"`
+}
+
+func (a AddonDef) enabled() bool {
+	return a.Enabled == nil || *a.Enabled
+}
+
+// helm chart of a built-in add-on
+type addonChart struct {
+	repoName  string
+	repoURL   string
+	chart     string
+	namespace string
+	values    func() map[interface{}]interface{}
+}
+
+// helm add-ons in the order they are installed
+var addonNames = []string{"metrics-server", "ingress-nginx", "cert-manager", "prometheus"}
+
+var addonCatalog = map[string]addonChart{
+	"metrics-server": {repoName: "metrics-server", repoURL: "https://kubernetes-sigs.github.io/metrics-server", chart: "metrics-server", namespace: "kube-system",
+		values: func() map[interface{}]interface{} {
+			return map[interface{}]interface{}{"args": []interface{}{"--kubelet-insecure-tls"}}
+		}},
+	"ingress-nginx": {repoName: "ingress-nginx", repoURL: "https://kubernetes.github.io/ingress-nginx", chart: "ingress-nginx", namespace: "ingress-nginx",
+		values: func() map[interface{}]interface{} {
+			return map[interface{}]interface{}{
+				"controller": map[interface{}]interface{}{
+					"ingressClassResource": map[interface{}]interface{}{"default": true},
+					// the load balancer targets of port 80 and 443
+					"service": map[interface{}]interface{}{
+						"type":      "NodePort",
+						"nodePorts": map[interface{}]interface{}{"http": 31394, "https": 31390},
+					},
+				},
+				"defaultBackend": map[interface{}]interface{}{"enabled": true},
+			}
+		}},
+	"cert-manager": {repoName: "jetstack", repoURL: "https://charts.jetstack.io", chart: "cert-manager", namespace: "cert-manager",
+		values: func() map[interface{}]interface{} {
+			return map[interface{}]interface{}{"installCRDs": true}
+		}},
+	"prometheus": {repoName: "prometheus-community", repoURL: "https://prometheus-community.github.io/helm-charts", chart: "prometheus", namespace: "prometheus",
+		values: func() map[interface{}]interface{} {
+			return map[interface{}]interface{}{
+				"alertmanager":           map[interface{}]interface{}{"enabled": false},
+				"prometheus-pushgateway": map[interface{}]interface{}{"enabled": false},
+				"server":                 map[interface{}]interface{}{"retention": "2d"},
+			}
+		}},
+}
+
+func validateAddons(addons map[string]AddonDef) error {
+	for name, a := range addons {
+		if name == localPathProvisioner {
+			if a.ValuesFile != "" || len(a.Values) > 0 {
+				return fmt.Errorf("addons.%s is a plain manifest and takes no values", name)
+			}
+			continue
+		}
+		if _, ok := addonCatalog[name]; !ok {
+			return fmt.Errorf("unknown add-on %s, supported are %v and %s", name, addonNames, localPathProvisioner)
+		}
+		if a.ValuesFile != "" {
+			if _, err := os.Stat(filepath.Join("./vars", a.ValuesFile)); err != nil {
+				return fmt.Errorf("addons.%s.values_file %s not found in ./vars: %w", name, a.ValuesFile, err)
+			}
+		}
+	}
+	return nil
+}
+
+// enabled helm add-ons, in install order
+func (inv Inventory) AddonCharts() []string {
+	var names []string
+	for _, name := range addonNames {
+		if inv.Addons[name].enabled() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// release tag of local-path-provisioner, empty when it is disabled
+func (inv Inventory) LocalPathVersion() string {
+	a := inv.Addons[localPathProvisioner]
+	if !a.enabled() {
+		return ""
+	}
+	if a.Version != "" {
+		return a.Version
+	}
+	return defaultLocalPathVersion
+}
+
+// helm values of an add-on, the user values are merged over the defaults
+func addonValues(name string, a AddonDef) (map[interface{}]interface{}, error) {
+	values, err := cniUserValues(CNIDef{ValuesFile: a.ValuesFile, Values: a.Values})
+	if err != nil {
+		return nil, fmt.Errorf("add-on %s: %w", name, err)
+	}
+	return mergeValues(addonCatalog[name].values(), values), nil
+}
+
+// write the values file of every enabled add-on into dir
+func writeAddonValues(dir string, inv Inventory) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range inv.AddonCharts() {
+		values, err := addonValues(name, inv.Addons[name])
+		if err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(values)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+"-values.yaml"), out, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type helmfileRepository struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+type helmfileRelease struct {
+	Name      string   `yaml:"name"`
+	Namespace string   `yaml:"namespace"`
+	Chart     string   `yaml:"chart"`
+	Version   string   `yaml:"version,omitempty"`
+	Values    []string `yaml:"values,omitempty"`
+	Wait      bool     `yaml:"wait,omitempty"`
+}
+
+type helmfile struct {
+	Repositories []helmfileRepository `yaml:"repositories,omitempty"`
+	Releases     []helmfileRelease    `yaml:"releases"`
+}
+
+// helmfile of the add-ons, installed from /tmp/addons on the first master
+func addonHelmfile(inv Inventory) helmfile {
+	var hf helmfile
+	for _, name := range inv.AddonCharts() {
+		chart := addonCatalog[name]
+		release := helmfileRelease{
+			Name:      name,
+			Namespace: chart.namespace,
+			Chart:     chart.repoName + "/" + chart.chart,
+			Version:   inv.Addons[name].Version,
+			Values:    []string{"/tmp/addons/" + name + "-values.yaml"},
+		}
+		if inv.AirGapped {
+			// the bundle holds the charts in the pulled version
+			release.Chart = "/tmp/addons/" + name + ".tgz"
+			release.Version = ""
+		} else {
+			hf.Repositories = append(hf.Repositories, helmfileRepository{Name: chart.repoName, URL: chart.repoURL})
+		}
+		hf.Releases = append(hf.Releases, release)
+	}
+	return hf
+}
+
+// helmfile, values and manifests of the add-ons, user releases and gitops in ./vars/addons-<cluster>
+func genAddonFiles(inv Inventory) error {
+	dir := fmt.Sprintf("./vars/addons-%s", inv.ClusterName)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := writeAddonValues(dir, inv); err != nil {
+		return err
+	}
+	hf := addonHelmfile(inv)
+	repos, releases, err := userReleases(dir, inv.Releases)
+	if err != nil {
+		return err
+	}
+	hf.Repositories = append(hf.Repositories, repos...)
+	hf.Releases = append(hf.Releases, releases...)
+	if err := copyManifests(dir, inv.Manifests); err != nil {
+		return err
+	}
+	if inv.Autoscaler != nil {
+		repo, release, err := autoscalerHelmRelease(dir, inv)
+		if err != nil {
+			return err
+		}
+		hf.Repositories = append(hf.Repositories, repo)
+		hf.Releases = append(hf.Releases, release)
+	}
+	if inv.GitOps != nil {
+		repo, release, err := gitopsRelease(dir, inv)
+		if err != nil {
+			return err
+		}
+		hf.Repositories = append(hf.Repositories, repo)
+		hf.Releases = append(hf.Releases, release)
+		if err := writeGitOpsManifests(dir, inv); err != nil {
+			return err
+		}
+	}
+	out, err := yaml.Marshal(hf)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "helmfile.yaml"), out, 0644)
+}
@@ -0,0 +1,193 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// upstream registries served from the private registry in air-gapped mode
+var airGapUpstreams = []string{"docker.io", "quay.io", "ghcr.io", "registry.k8s.io"}
+
+// node image a bundle is created for when none is given
+const defaultBundleImage = "ubuntu-24.04"
+
+// container the conntrack and socat packages of a node image are downloaded in, with their package format
+var airGapPackageImages = map[string]struct{ container, format string }{
+	"ubuntu-24.04":    {"ubuntu:24.04", "deb"},
+	"ubuntu-22.04":    {"ubuntu:22.04", "deb"},
+	"centos-7":        {"centos:7", "rpm"},
+	"centos-stream-8": {"quay.io/centos/centos:stream8", "rpm"},
+}
+
+func airGapArchive(clusterName string) string {
+	return fmt.Sprintf("./vars/airgap-%s.tar.gz", clusterName)
+}
+
+func validateAirGap(clusterName string, cluster Cluster) error {
+	if !cluster.AirGapped {
+		return nil
+	}
+	if cluster.PrivateRegistry == "" {
+		return fmt.Errorf("air_gapped cluster %s needs a private_registry to hold the images", clusterName)
+	}
+	if cluster.Cri != "" && cluster.Cri != "containerd" {
+		return fmt.Errorf("air_gapped cluster %s only supports the containerd runtime", clusterName)
+	}
+	if cluster.Cni.Name == "calico" {
+		return fmt.Errorf("air_gapped cluster %s does not support calico, its operator pulls images unknown to the bundle", clusterName)
+	}
+	return nil
+}
+
+// the bundle has to exist before the cluster is installed
+func checkAirGapArchive(clusterName string, cluster Cluster) error {
+	if !cluster.AirGapped {
+		return nil
+	}
+	if _, err := os.Stat(airGapArchive(clusterName)); err != nil {
+		return fmt.Errorf("air_gapped cluster %s has no bundle %s, create it with `pulumi-hcloud-kubeadm bundle <topology file> %s`", clusterName, airGapArchive(clusterName), clusterName)
+	}
+	return nil
+}
+
+// mirror the upstream registries to the private registry, unless mirrors are configured for them
+func airGapMirrors(cluster Cluster, registries map[string]RegistryDef) {
+	if !cluster.AirGapped {
+		return
+	}
+	scheme := "https://"
+	if registries[strings.SplitN(cluster.PrivateRegistry, "/", 2)[0]].Insecure {
+		scheme = "http://"
+	}
+	for _, upstream := range airGapUpstreams {
+		r := registries[upstream]
+		if len(r.Mirrors) == 0 {
+			r.Mirrors = []string{scheme + cluster.PrivateRegistry}
+		}
+		registries[upstream] = r
+	}
+}
+
+// variables of the bundle playbook
+type bundleVars struct {
+	ClusterName       string        `yaml:"clustername"`
+	KubernetesVersion string        `yaml:"kubernetes_version"`
+	Cni               string        `yaml:"cni"`
+	CniChart          string        `yaml:"cni_chart,omitempty"`
+	CniRepoURL        string        `yaml:"cni_repo_url,omitempty"`
+	CniVersion        string        `yaml:"cni_version,omitempty"`
+	BackupEnabled     bool          `yaml:"backup_enabled"`
+	AddonCharts       []bundleAddon `yaml:"addon_charts"`
+	LocalPathVersion  string        `yaml:"local_path_version"`
+	PackagesContainer string        `yaml:"packages_container"`
+	PackagesFormat    string        `yaml:"packages_format"`
+}
+
+type bundleAddon struct {
+	Name    string `yaml:"name"`
+	Repo    string `yaml:"repo"`
+	Chart   string `yaml:"chart"`
+	Version string `yaml:"version,omitempty"`
+}
+
+// download the packages, images and charts of an air-gapped cluster into ./vars/airgap-<cluster>.tar.gz
+func Bundle(args []string) error {
+	if len(args) != 2 && len(args) != 3 {
+		return errors.New("usage: pulumi-hcloud-kubeadm bundle <topology file> <cluster> [image]")
+	}
+	image := defaultBundleImage
+	if len(args) == 3 {
+		image = args[2]
+	}
+	packages, ok := airGapPackageImages[image]
+	if !ok {
+		images := make([]string, 0, len(airGapPackageImages))
+		for name := range airGapPackageImages {
+			images = append(images, name)
+		}
+		sort.Strings(images)
+		return fmt.Errorf("no packages for image %s, the bundle supports %s", image, strings.Join(images, ", "))
+	}
+	topology, err := ReadTopology(args[0])
+	if err != nil {
+		return err
+	}
+	clusterName := args[1]
+	cluster, ok := topology.Clusters[clusterName]
+	if !ok {
+		return fmt.Errorf("cluster %s is not in %s", clusterName, args[0])
+	}
+	if !cluster.AirGapped {
+		return fmt.Errorf("cluster %s is not air_gapped", clusterName)
+	}
+	if err := validateAirGap(clusterName, cluster); err != nil {
+		return err
+	}
+	if err := validateCNI(cluster.Cni, cluster.Kubeadm); err != nil {
+		return err
+	}
+	if err := validateAddons(cluster.Addons); err != nil {
+		return err
+	}
+	ictx := NewClusterInfra(&InfraConfig{}, &cluster)
+	inv := ictx.inventory
+	inv.ClusterName = clusterName
+	// the images only depend on the CNI features, not on the node addresses
+	inv.MasterIPs = []*Node{{PrivateIP: "10.0.1.2"}}
+
+	dir := fmt.Sprintf("./vars/airgap-%s", clusterName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if inv.Cni != "none" {
+		values, err := cniUserValues(inv.CniDef)
+		if err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(mergeValues(cniDefaultValues(*inv), values))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(dir+"/cni-values.yaml", out, 0644); err != nil {
+			return err
+		}
+	}
+	if err := writeAddonValues(dir+"/addons", *inv); err != nil {
+		return err
+	}
+	var addons []bundleAddon
+	for _, name := range inv.AddonCharts() {
+		chart := addonCatalog[name]
+		addons = append(addons, bundleAddon{Name: name, Repo: chart.repoURL, Chart: chart.chart, Version: inv.Addons[name].Version})
+	}
+	out, err := yaml.Marshal(bundleVars{
+		ClusterName:       clusterName,
+		KubernetesVersion: inv.K8sversion,
+		Cni:               inv.Cni,
+		CniChart:          inv.CniChart,
+		CniRepoURL:        inv.CniRepoURL,
+		CniVersion:        inv.CniVersion,
+		BackupEnabled:     inv.Backup.Enabled,
+		AddonCharts:       addons,
+		LocalPathVersion:  inv.LocalPathVersion(),
+		PackagesContainer: packages.container,
+		PackagesFormat:    packages.format,
+	})
+	if err != nil {
+		return err
+	}
+	varsFile := dir + "/bundle.yaml"
+	if err := os.WriteFile(varsFile, out, 0644); err != nil {
+		return err
+	}
+	cmd := exec.Command("ansible-playbook", "-e", "@"+varsFile, "./.ansible/bundle.yaml")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
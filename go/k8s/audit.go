@@ -0,0 +1,111 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	auditPolicyDir = "/etc/kubernetes/audit"
+	auditLogDir    = "/var/log/kubernetes/audit"
+)
+
+type AuditDef struct {
+	// none, metadata, request, request-response or custom
+	Profile string `yaml:"profile,omitempty"`
+	// audit policy for the custom profile, file name in ./vars
+	PolicyFile string `yaml:"policy_file,omitempty"`
+	MaxAge     int    `yaml:"max_age,omitempty"`
+	MaxBackup  int    `yaml:"max_backup,omitempty"`
+	MaxSize    int    `yaml:"max_size,omitempty"`
+}
+
+func (a AuditDef) Enabled() bool {
+	return a.Profile != "" && a.Profile != "none"
+}
+
+// common rules of the built-in profiles: drop noisy read-only traffic and keep secret payloads out of the log
+const auditPolicyHeader = `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: None
+  users: ["system:kube-proxy"]
+  verbs: ["watch"]
+  resources:
+  - group: ""
+    resources: ["endpoints", "services", "services/status"]
+- level: None
+  nonResourceURLs: ["/healthz*", "/livez*", "/readyz*", "/version"]
+- level: None
+  resources:
+  - group: ""
+    resources: ["events"]
+  - group: "events.k8s.io"
+    resources: ["events"]
+- level: Metadata
+  resources:
+  - group: ""
+    resources: ["secrets", "configmaps", "serviceaccounts/token"]
+  - group: "authentication.k8s.io"
+    resources: ["tokenreviews"]
+`
+
+var auditProfiles = map[string]string{
+	"metadata":         "Metadata",
+	"request":          "Request",
+	"request-response": "RequestResponse",
+}
+
+func validateAudit(audit AuditDef) error {
+	if !audit.Enabled() {
+		return nil
+	}
+	if audit.Profile == "custom" {
+		if audit.PolicyFile == "" {
+			return fmt.Errorf("audit.policy_file is required with the custom audit profile")
+		}
+		if _, err := os.Stat(filepath.Join("./vars", audit.PolicyFile)); err != nil {
+			return fmt.Errorf("audit.policy_file %s not found in ./vars: %w", audit.PolicyFile, err)
+		}
+		return nil
+	}
+	if _, ok := auditProfiles[audit.Profile]; !ok {
+		return fmt.Errorf("unknown audit profile %s, must be one of none, metadata, request, request-response, custom", audit.Profile)
+	}
+	return nil
+}
+
+// audit policy of the cluster, either a built-in profile or the user supplied file
+func auditPolicy(audit AuditDef) ([]byte, error) {
+	if audit.Profile == "custom" {
+		return os.ReadFile(filepath.Join("./vars", audit.PolicyFile))
+	}
+	return []byte(auditPolicyHeader + "- level: " + auditProfiles[audit.Profile] + "\n"), nil
+}
+
+func auditAPIServerArgs(audit AuditDef) map[string]string {
+	args := make(map[string]string)
+	if !audit.Enabled() {
+		return args
+	}
+	args["audit-policy-file"] = auditPolicyDir + "/policy.yaml"
+	args["audit-log-path"] = auditLogDir + "/audit.log"
+	args["audit-log-maxage"] = strconv.Itoa(audit.MaxAge)
+	args["audit-log-maxbackup"] = strconv.Itoa(audit.MaxBackup)
+	args["audit-log-maxsize"] = strconv.Itoa(audit.MaxSize)
+	return args
+}
+
+func auditAPIServerVolumes(audit AuditDef) []kubeadmVolume {
+	if !audit.Enabled() {
+		return nil
+	}
+	return []kubeadmVolume{
+		{Name: "audit-policy", HostPath: auditPolicyDir, MountPath: auditPolicyDir, ReadOnly: true, PathType: "DirectoryOrCreate"},
+		{Name: "audit-log", HostPath: auditLogDir, MountPath: auditLogDir, PathType: "DirectoryOrCreate"},
+	}
+}
@@ -0,0 +1,289 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	autoscalerRelease = "cluster-autoscaler"
+	// secret of the autoscaler with the hcloud token and the node configuration
+	autoscalerSecret = "hcloud-autoscaler"
+	// lifetime of the bootstrap token of the autoscaled nodes, a new one is generated after half of it
+	autoscalerTokenTTL = 7 * 24 * time.Hour
+)
+
+type WorkerDef struct {
+	NodeCount int `yaml:"node_count"`
+	// autoscaled nodes on top of node_count, autoscaling is off while max is 0
+	Min int `yaml:"min,omitempty"`
+	Max int `yaml:"max,omitempty"`
+	// node labels and taints (key=value:Effect or key:Effect) of every worker
+	Labels map[string]string `yaml:"labels,omitempty"`
+	Taints []string          `yaml:"taints,omitempty"`
+}
+
+func (w WorkerDef) autoscaled() bool {
+	return w.Max > 0
+}
+
+// hetzner resources of the autoscaled nodes, resolved from the pulumi outputs
+type AutoscalerConfig struct {
+	NodeGroup  string
+	Network    string
+	Firewall   string
+	SSHKey     string
+	Image      string
+	ServerType string
+	Region     string
+	// bootstrap token of the autoscaled nodes, it expires after autoscalerTokenTTL
+	Token string
+}
+
+// rotation period of the autoscaler token at now, the token is replaced by the first update of a new period.
+// The token is in the user data of the autoscaled nodes, any pod on them can read it from the metadata service
+func autoscalerTokenPeriod(now time.Time) string {
+	return strconv.FormatInt(now.Unix()/int64((autoscalerTokenTTL/2)/time.Second), 10)
+}
+
+// ttl of the autoscaler token, for the templates
+func (inv Inventory) AutoscalerTokenTTL() string {
+	return autoscalerTokenTTL.String()
+}
+
+func parseTaint(taint string) (kubeadmTaint, error) {
+	keyValue, effect, ok := strings.Cut(taint, ":")
+	if !ok || (effect != "NoSchedule" && effect != "PreferNoSchedule" && effect != "NoExecute") {
+		return kubeadmTaint{}, fmt.Errorf("taint %q must be key=value:Effect or key:Effect with NoSchedule, PreferNoSchedule or NoExecute", taint)
+	}
+	key, value, _ := strings.Cut(keyValue, "=")
+	return kubeadmTaint{Key: key, Value: value, Effect: effect}, nil
+}
+
+func workerTaints(w WorkerDef) []kubeadmTaint {
+	taints := make([]kubeadmTaint, 0, len(w.Taints))
+	for _, t := range w.Taints {
+		// checked by validateWorker
+		taint, _ := parseTaint(t)
+		taints = append(taints, taint)
+	}
+	return taints
+}
+
+// the worker labels and taints are set when the node registers
+func workerNodeRegistration(apiVersion string, base kubeadmNodeRegistration, kadm KubeadmDef, w WorkerDef) kubeadmNodeRegistration {
+	nr := base
+	if len(w.Labels) > 0 {
+		labels := make([]string, 0, len(w.Labels))
+		for k, v := range w.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		nr.KubeletExtraArgs = kubeadmExtraArgs(apiVersion, mergeArgs(map[string]string{"node-labels": strings.Join(labels, ",")}, kadm.Kubelet.ExtraArgs))
+	}
+	nr.Taints = workerTaints(w)
+	return nr
+}
+
+func validateWorker(clusterName string, cluster Cluster) error {
+	w := cluster.Worker
+	for k := range w.Labels {
+		// the NodeRestriction admission plugin rejects these from the kubelet
+		if strings.HasPrefix(k, "node-role.kubernetes.io/") {
+			return fmt.Errorf("worker label %s of cluster %s cannot be set by the kubelet", k, clusterName)
+		}
+	}
+	for _, t := range w.Taints {
+		if _, err := parseTaint(t); err != nil {
+			return err
+		}
+	}
+	if !w.autoscaled() {
+		if w.Min > 0 {
+			return fmt.Errorf("worker.min of cluster %s needs worker.max", clusterName)
+		}
+		return nil
+	}
+	if w.Min < 0 || w.Min > w.Max {
+		return fmt.Errorf("worker.min of cluster %s must be between 0 and worker.max", clusterName)
+	}
+	if cluster.Cri != "" && cluster.Cri != "containerd" {
+		return fmt.Errorf("autoscaled workers of cluster %s only support the containerd runtime", clusterName)
+	}
+	if cluster.AirGapped {
+		return fmt.Errorf("air_gapped cluster %s does not support autoscaled workers", clusterName)
+	}
+	if nativeRouting(cluster.Cilium) {
+		return fmt.Errorf("cluster %s uses cilium native routing, which needs an hcloud route per node and does not support autoscaled workers", clusterName)
+	}
+	return nil
+}
+
+// hcloud location of a datacenter, e.g. fsn1 of fsn1-dc14
+func hcloudLocation(dataCenter string) string {
+	return strings.SplitN(dataCenter, "-", 2)[0]
+}
+
+// resolve the hetzner resources and the join token of the autoscaled nodes
+func setupAutoscaler(ctx *pulumi.Context, infraCfg *InfraConfig, ictx *infra, clusterName string, pulumik8sCluster *K8sCluster) (err error) {
+	w := ictx.cluster.Worker
+	if !w.autoscaled() {
+		return nil
+	}
+	if infraCfg.HcloudToken == "" {
+		return fmt.Errorf("the autoscaler of cluster %s needs the hcloud token in the hcloud:token config or HCLOUD_TOKEN", clusterName)
+	}
+	// a new token is generated in every period, install.yaml creates it and deletes the previous one
+	period := pulumi.StringMap{"period": pulumi.String(autoscalerTokenPeriod(time.Now()))}
+	tokenID, err := random.NewRandomString(ctx, fmt.Sprintf("autoscaler-token-id-%s", clusterName), &random.RandomStringArgs{
+		Length:  pulumi.Int(6),
+		Upper:   pulumi.Bool(false),
+		Special: pulumi.Bool(false),
+		Keepers: period,
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	tokenSecret, err := random.NewRandomString(ctx, fmt.Sprintf("autoscaler-token-secret-%s", clusterName), &random.RandomStringArgs{
+		Length:  pulumi.Int(16),
+		Upper:   pulumi.Bool(false),
+		Special: pulumi.Bool(false),
+		Keepers: period,
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	a := pulumi.All(ictx.core.network.ID(), ictx.workerFirewall.ID(), ictx.core.sshKey.ID(),
+		tokenID.Result, tokenSecret.Result).ApplyT(func(v []interface{}) []string {
+		ictx.inventory.Autoscaler = &AutoscalerConfig{
+			NodeGroup:  clusterName + "-worker",
+			Network:    string(v[0].(pulumi.ID)),
+			Firewall:   string(v[1].(pulumi.ID)),
+			SSHKey:     string(v[2].(pulumi.ID)),
+			Image:      infraCfg.Image,
+			ServerType: infraCfg.WorkerFlavor,
+			Region:     hcloudLocation(infraCfg.DataCenter),
+			Token:      v[3].(string) + "." + v[4].(string),
+		}
+		ictx.inventory.HcloudToken = infraCfg.HcloudToken
+		return make([]string, 0)
+	})
+	ictx.waitFor = append(ictx.waitFor, a)
+	return
+}
+
+func autoscalerReleases(w WorkerDef) []string {
+	if !w.autoscaled() {
+		return nil
+	}
+	return []string{autoscalerRelease}
+}
+
+type cloudConfigFile struct {
+	Path        string `yaml:"path"`
+	Permissions string `yaml:"permissions"`
+	Encoding    string `yaml:"encoding"`
+	Content     string `yaml:"content"`
+}
+
+type cloudConfig struct {
+	WriteFiles []cloudConfigFile `yaml:"write_files"`
+	RunCmd     [][]string        `yaml:"runcmd"`
+}
+
+func b64File(path, permissions string, content []byte) cloudConfigFile {
+	return cloudConfigFile{Path: path, Permissions: permissions, Encoding: "b64", Content: base64.StdEncoding.EncodeToString(content)}
+}
+
+// cloud-init of the autoscaled nodes, they join with the autoscaler token
+// nothing can upload files to them, their registry credentials are in the user data
+func autoscalerCloudInit(inv Inventory) (string, error) {
+	pki := *inv.Pki
+	pki.BootstrapToken = inv.Autoscaler.Token
+	inv.Pki = &pki
+	return nodeCloudInit(inv, roleWorker, true, true)
+}
+
+// HCLOUD_CLUSTER_CONFIG of the hetzner provider, base64 encoded
+func autoscalerClusterConfig(inv Inventory) (string, error) {
+	cloudInit, err := autoscalerCloudInit(inv)
+	if err != nil {
+		return "", err
+	}
+	labels := inv.Worker.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	out, err := json.Marshal(map[string]interface{}{
+		"imagesForArch": map[string]string{"amd64": inv.Autoscaler.Image, "arm64": inv.Autoscaler.Image},
+		"nodeConfigs": map[string]interface{}{
+			inv.Autoscaler.NodeGroup: map[string]interface{}{
+				"cloudInit": cloudInit,
+				"labels":    labels,
+				"taints":    workerTaints(inv.Worker),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// helmfile release of the cluster-autoscaler, the secret is created by install.yaml
+func autoscalerHelmRelease(dir string, inv Inventory) (helmfileRepository, helmfileRelease, error) {
+	a := inv.Autoscaler
+	values := map[string]interface{}{
+		"cloudProvider": "hetzner",
+		"autoscalingGroups": []map[string]interface{}{{
+			"name":         a.NodeGroup,
+			"minSize":      inv.Worker.Min,
+			"maxSize":      inv.Worker.Max,
+			"instanceType": a.ServerType,
+			"region":       a.Region,
+		}},
+		"extraEnv": map[string]string{
+			"HCLOUD_NETWORK":     a.Network,
+			"HCLOUD_FIREWALL":    a.Firewall,
+			"HCLOUD_SSH_KEY":     a.SSHKey,
+			"HCLOUD_PUBLIC_IPV4": "false",
+			"HCLOUD_PUBLIC_IPV6": "false",
+		},
+		"extraEnvSecrets": map[string]interface{}{
+			"HCLOUD_TOKEN":          map[string]string{"name": autoscalerSecret, "key": "token"},
+			"HCLOUD_CLUSTER_CONFIG": map[string]string{"name": autoscalerSecret, "key": "cluster-config"},
+		},
+		// the environment is read at start, a rotated token restarts the autoscaler
+		"podAnnotations": map[string]string{
+			"checksum/token": fmt.Sprintf("%x", sha256.Sum256([]byte(a.Token))),
+		},
+	}
+	out, err := yaml.Marshal(values)
+	if err != nil {
+		return helmfileRepository{}, helmfileRelease{}, err
+	}
+	valuesFile := autoscalerRelease + "-values.yaml"
+	if err := os.WriteFile(filepath.Join(dir, valuesFile), out, 0644); err != nil {
+		return helmfileRepository{}, helmfileRelease{}, err
+	}
+	return helmfileRepository{Name: "autoscaler", URL: "https://kubernetes.github.io/autoscaler"}, helmfileRelease{
+		Name:      autoscalerRelease,
+		Namespace: "kube-system",
+		Chart:     "autoscaler/cluster-autoscaler",
+		Values:    []string{"/tmp/addons/" + valuesFile},
+	}, nil
+}
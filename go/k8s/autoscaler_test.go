@@ -0,0 +1,250 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseTaint(t *testing.T) {
+	tests := []struct {
+		taint string
+		want  kubeadmTaint
+		err   bool
+	}{
+		{taint: "dedicated=gpu:NoSchedule", want: kubeadmTaint{Key: "dedicated", Value: "gpu", Effect: "NoSchedule"}},
+		{taint: "spot:PreferNoSchedule", want: kubeadmTaint{Key: "spot", Effect: "PreferNoSchedule"}},
+		{taint: "example.com/drain=true:NoExecute", want: kubeadmTaint{Key: "example.com/drain", Value: "true", Effect: "NoExecute"}},
+		{taint: "dedicated=gpu", err: true},
+		{taint: "dedicated=gpu:NoEvict", err: true},
+		{taint: "dedicated=gpu:noschedule", err: true},
+		{taint: "", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.taint, func(t *testing.T) {
+			got, err := parseTaint(tt.taint)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWorker(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster Cluster
+		err     string
+	}{
+		{name: "static", cluster: Cluster{Worker: WorkerDef{NodeCount: 2, Labels: map[string]string{"pool": "a"}, Taints: []string{"spot:NoSchedule"}}}},
+		{name: "autoscaled", cluster: Cluster{Cri: "containerd", Worker: WorkerDef{Min: 1, Max: 3}}},
+		{name: "autoscaled from zero", cluster: Cluster{Worker: WorkerDef{Max: 3}}},
+		{name: "role label", cluster: Cluster{Worker: WorkerDef{Labels: map[string]string{"node-role.kubernetes.io/worker": ""}}}, err: "cannot be set by the kubelet"},
+		{name: "bad taint", cluster: Cluster{Worker: WorkerDef{Taints: []string{"spot"}}}, err: "must be key=value:Effect"},
+		{name: "min without max", cluster: Cluster{Worker: WorkerDef{Min: 1}}, err: "needs worker.max"},
+		{name: "min above max", cluster: Cluster{Worker: WorkerDef{Min: 4, Max: 3}}, err: "between 0 and worker.max"},
+		{name: "negative min", cluster: Cluster{Worker: WorkerDef{Min: -1, Max: 3}}, err: "between 0 and worker.max"},
+		{name: "docker", cluster: Cluster{Cri: "docker", Worker: WorkerDef{Max: 3}}, err: "only support the containerd runtime"},
+		{name: "air gapped", cluster: Cluster{AirGapped: true, Worker: WorkerDef{Max: 3}}, err: "does not support autoscaled workers"},
+		{name: "native routing", cluster: Cluster{Cilium: &CiliumDef{NativeRouting: true}, Worker: WorkerDef{Max: 3}}, err: "cilium native routing"},
+		{name: "native routing without autoscaling", cluster: Cluster{Cilium: &CiliumDef{NativeRouting: true}, Worker: WorkerDef{NodeCount: 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWorker("c1", tt.cluster)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// inventory of c1 with autoscaled workers
+func autoscalerInventory(t *testing.T) Inventory {
+	t.Helper()
+	inv := bootstrapInventory(t, "containerd", "flannel")
+	inv.Worker = WorkerDef{Min: 1, Max: 3, Labels: map[string]string{"pool": "autoscaled"}, Taints: []string{"dedicated=batch:NoSchedule"}}
+	inv.Autoscaler = &AutoscalerConfig{
+		NodeGroup: "c1-worker", Network: "1", Firewall: "2", SSHKey: "3", Image: "ubuntu-24.04",
+		ServerType: "cx22", Region: "fsn1", Token: "ghijkl.0123456789abcdef",
+	}
+	return inv
+}
+
+func TestAutoscalerClusterConfig(t *testing.T) {
+	chdirVars(t)
+	inv := autoscalerInventory(t)
+	encoded, err := autoscalerClusterConfig(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config struct {
+		ImagesForArch map[string]string `json:"imagesForArch"`
+		NodeConfigs   map[string]struct {
+			CloudInit string            `json:"cloudInit"`
+			Labels    map[string]string `json:"labels"`
+			Taints    []kubeadmTaint    `json:"taints"`
+		} `json:"nodeConfigs"`
+	}
+	if err := json.Unmarshal(decoded, &config); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"amd64": "ubuntu-24.04", "arm64": "ubuntu-24.04"}; !reflect.DeepEqual(config.ImagesForArch, want) {
+		t.Errorf("imagesForArch = %v, want %v", config.ImagesForArch, want)
+	}
+	group, ok := config.NodeConfigs["c1-worker"]
+	if !ok || len(config.NodeConfigs) != 1 {
+		t.Fatalf("node configs %v, want only c1-worker", config.NodeConfigs)
+	}
+	if !reflect.DeepEqual(group.Labels, inv.Worker.Labels) {
+		t.Errorf("labels = %v, want %v", group.Labels, inv.Worker.Labels)
+	}
+	if want := []kubeadmTaint{{Key: "dedicated", Value: "batch", Effect: "NoSchedule"}}; !reflect.DeepEqual(group.Taints, want) {
+		t.Errorf("taints = %+v, want %+v", group.Taints, want)
+	}
+	// the nodes join with the autoscaler token, not with the one of the static nodes
+	var cloudInit struct {
+		WriteFiles []cloudConfigFile `yaml:"write_files"`
+	}
+	if err := yaml.Unmarshal([]byte(group.CloudInit), &cloudInit); err != nil {
+		t.Fatal(err)
+	}
+	var files strings.Builder
+	for _, f := range cloudInit.WriteFiles {
+		content, err := base64.StdEncoding.DecodeString(f.Content)
+		if err != nil {
+			t.Fatalf("%s: %v", f.Path, err)
+		}
+		files.Write(content)
+	}
+	if !strings.Contains(files.String(), inv.Autoscaler.Token) || strings.Contains(files.String(), inv.Pki.BootstrapToken) {
+		t.Errorf("the cloud-init does not join with the autoscaler token:\n%s", files.String())
+	}
+	if inv.Pki.BootstrapToken != "abcdef.0123456789abcdef" {
+		t.Error("the cluster config changed the bootstrap token of the inventory")
+	}
+
+	// without labels the provider still gets an object
+	inv.Worker.Labels = nil
+	encoded, err = autoscalerClusterConfig(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, _ = base64.StdEncoding.DecodeString(encoded)
+	if !strings.Contains(string(decoded), `"labels":{}`) {
+		t.Errorf("labels of a worker without labels: %s", decoded)
+	}
+}
+
+func TestAutoscalerHelmRelease(t *testing.T) {
+	dir := t.TempDir()
+	inv := autoscalerInventory(t)
+	annotation := func() string {
+		t.Helper()
+		_, release, err := autoscalerHelmRelease(dir, inv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if release.Values[0] != "/tmp/addons/"+autoscalerRelease+"-values.yaml" {
+			t.Errorf("values %v", release.Values)
+		}
+		out, err := os.ReadFile(filepath.Join(dir, autoscalerRelease+"-values.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(out), inv.Autoscaler.Token) {
+			t.Errorf("the values hold the token:\n%s", out)
+		}
+		var values struct {
+			PodAnnotations  map[string]string            `yaml:"podAnnotations"`
+			ExtraEnvSecrets map[string]map[string]string `yaml:"extraEnvSecrets"`
+			Groups          []map[string]interface{}     `yaml:"autoscalingGroups"`
+		}
+		if err := yaml.Unmarshal(out, &values); err != nil {
+			t.Fatal(err)
+		}
+		if values.ExtraEnvSecrets["HCLOUD_TOKEN"]["name"] != autoscalerSecret {
+			t.Errorf("HCLOUD_TOKEN from %v", values.ExtraEnvSecrets["HCLOUD_TOKEN"])
+		}
+		if len(values.Groups) != 1 || values.Groups[0]["name"] != "c1-worker" || values.Groups[0]["minSize"] != 1 || values.Groups[0]["maxSize"] != 3 {
+			t.Errorf("autoscaling groups %v", values.Groups)
+		}
+		return values.PodAnnotations["checksum/token"]
+	}
+	first := annotation()
+	if first == "" {
+		t.Fatal("no token checksum annotation")
+	}
+	if annotation() != first {
+		t.Error("the checksum changed without a new token")
+	}
+	// a rotated token restarts the autoscaler
+	inv.Autoscaler = &AutoscalerConfig{NodeGroup: "c1-worker", Token: "mnopqr.0123456789abcdef"}
+	if annotation() == first {
+		t.Error("the checksum did not change with the token")
+	}
+}
+
+func TestAutoscalerTokenPeriod(t *testing.T) {
+	start := time.Unix(0, 0).Add(10 * autoscalerTokenTTL)
+	period := autoscalerTokenPeriod(start)
+	if got := autoscalerTokenPeriod(start.Add(autoscalerTokenTTL/2 - time.Second)); got != period {
+		t.Errorf("period changed within half of the ttl: %s, want %s", got, period)
+	}
+	next := autoscalerTokenPeriod(start.Add(autoscalerTokenTTL / 2))
+	if next == period {
+		t.Error("period did not change after half of the ttl")
+	}
+}
+
+// install.yaml creates the token with the ttl of the variables
+func TestAutoscalerTokenTTLVariable(t *testing.T) {
+	tmpl, err := template.New("variables").Parse(string(variablesTmpl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, autoscaler := range []*AutoscalerConfig{nil, {Token: "ghijkl.0123456789abcdef"}} {
+		ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: "1.30.2"})
+		ictx.inventory.Autoscaler = autoscaler
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, *ictx.inventory); err != nil {
+			t.Fatal(err)
+		}
+		var vars map[string]interface{}
+		if err := yaml.Unmarshal(buf.Bytes(), &vars); err != nil {
+			t.Fatalf("%v\n%s", err, buf.String())
+		}
+		ttl, ok := vars["autoscaler_token_ttl"]
+		if autoscaler == nil {
+			if ok {
+				t.Errorf("autoscaler_token_ttl %v without the autoscaler", ttl)
+			}
+			continue
+		}
+		if ttl != "168h0m0s" {
+			t.Errorf("autoscaler_token_ttl = %v", ttl)
+		}
+	}
+}
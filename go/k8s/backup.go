@@ -0,0 +1,41 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// look up the object store credentials of a cluster with backup or restore configured
+func setupBackup(infraCfg *InfraConfig, ictx *infra, clusterName string) error {
+	backup := ictx.cluster.Backup
+	if !backup.Enabled && backup.Restore == "" {
+		return nil
+	}
+	if backup.Endpoint == "" {
+		return fmt.Errorf("backup.endpoint is not set for cluster %s", clusterName)
+	}
+	creds, ok := infraCfg.Backups[clusterName]
+	if !ok || creds.Bucket == "" || creds.AccessKey == "" || creds.SecretKey == "" {
+		return fmt.Errorf("backup is configured for cluster %s but the pulumi config has no backup.%s bucket, accessKey and secretKey", clusterName, clusterName)
+	}
+	ictx.inventory.BackupCredentials = creds
+	return nil
+}
+
+// restore the control plane from backup.restore, every time its value changes
+func setupEtcdRestore(ctx *pulumi.Context, clusterName string, ictx *infra, installer []pulumi.Resource, pulumik8sCluster *K8sCluster) ([]pulumi.Resource, error) {
+	restore := ictx.cluster.Backup.Restore
+	if restore == "" {
+		return installer, nil
+	}
+	r, err := local.NewCommand(ctx, fmt.Sprintf("ansible-etcd-restore-%s", clusterName), &local.CommandArgs{
+		Create:   pulumi.String(fmt.Sprintf("ansible-playbook -i ./vars/inventory-%s.ini -e \"@./vars/variables-%s.yaml\" -e \"@./vars/secrets-%s.yaml\" ./.ansible/restore-etcd.yaml", clusterName, clusterName, clusterName)),
+		Triggers: pulumi.Array{pulumi.String(restore)},
+	}, dependsOnSteps(installer), pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return nil, err
+	}
+	return []pulumi.Resource{r}, nil
+}
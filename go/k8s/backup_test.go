@@ -0,0 +1,170 @@
+package k8s
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"gopkg.in/yaml.v3"
+)
+
+func backupCluster(backup BackupDef) *infra {
+	return testCluster(&Cluster{Cri: "containerd", KubernetesVersion: "1.30.2", Backup: backup})
+}
+
+func renderVariables(t *testing.T, inv Inventory) map[string]interface{} {
+	t.Helper()
+	tmpl := template.Must(template.New("variables").Parse(string(variablesTmpl)))
+	var buff bytes.Buffer
+	if err := tmpl.Execute(&buff, inv); err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]interface{}{}
+	if err := yaml.Unmarshal(buff.Bytes(), &vars); err != nil {
+		t.Fatalf("variables are not valid yaml: %v\n%s", err, buff.String())
+	}
+	return vars
+}
+
+func TestSetupBackup(t *testing.T) {
+	creds := BackupCredentials{Bucket: "etcd", AccessKey: "minio", SecretKey: "minio123"}
+	tests := []struct {
+		name    string
+		backup  BackupDef
+		creds   map[string]BackupCredentials
+		wantErr string
+	}{
+		{name: "disabled", backup: BackupDef{}},
+		{name: "no endpoint", backup: BackupDef{Enabled: true}, wantErr: "backup.endpoint is not set"},
+		{name: "no credentials", backup: BackupDef{Enabled: true, Endpoint: "http://minio:9000"}, wantErr: "no backup.c1 bucket"},
+		{name: "partial credentials", backup: BackupDef{Enabled: true, Endpoint: "http://minio:9000"},
+			creds: map[string]BackupCredentials{"c1": {Bucket: "etcd"}}, wantErr: "no backup.c1 bucket"},
+		{name: "enabled", backup: BackupDef{Enabled: true, Endpoint: "http://minio:9000"},
+			creds: map[string]BackupCredentials{"c1": creds}},
+		{name: "restore only", backup: BackupDef{Endpoint: "http://minio:9000", Restore: "snapshot.db"},
+			creds: map[string]BackupCredentials{"c1": creds}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ictx := backupCluster(tt.backup)
+			err := setupBackup(&InfraConfig{Backups: tt.creds}, ictx, "c1")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.creds["c1"]; ictx.inventory.BackupCredentials != want {
+				t.Errorf("got credentials %+v, want %+v", ictx.inventory.BackupCredentials, want)
+			}
+		})
+	}
+}
+
+func TestBackupVariables(t *testing.T) {
+	vars := renderVariables(t, *backupCluster(BackupDef{Enabled: true, Endpoint: "http://minio:9000"}).inventory)
+	want := map[string]interface{}{
+		"backup_enabled":        true,
+		"backup_schedule":       "0 2 * * *",
+		"backup_retention_days": 7,
+		"backup_endpoint":       "http://minio:9000",
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s = %v, want %v", k, vars[k], v)
+		}
+	}
+	if _, ok := vars["backup_restore"]; ok {
+		t.Error("backup_restore is set without a restore")
+	}
+
+	// restore-etcd.yaml needs the endpoint when the schedule is not enabled
+	vars = renderVariables(t, *backupCluster(BackupDef{Endpoint: "http://minio:9000", Restore: "snapshot.db"}).inventory)
+	if vars["backup_enabled"] != false || vars["backup_endpoint"] != "http://minio:9000" || vars["backup_restore"] != "snapshot.db" {
+		t.Errorf("restore variables: %v", vars)
+	}
+	if _, ok := vars["backup_schedule"]; ok {
+		t.Error("backup_schedule is set without backups enabled")
+	}
+}
+
+func TestBackupSecrets(t *testing.T) {
+	dir := chdirVars(t)
+
+	ictx := backupCluster(BackupDef{Enabled: true, Endpoint: "http://minio:9000"})
+	ictx.inventory.BackupCredentials = BackupCredentials{Bucket: "etcd", AccessKey: "minio", SecretKey: "minio123"}
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		genSecretsFile(ctx, *ictx.inventory)
+		return nil
+	}, pulumi.WithMocks("project", "stack", newMocks()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "vars", "secrets-c1.yaml")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("secrets file mode is %v", info.Mode().Perm())
+	}
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := map[string]interface{}{}
+	if err := yaml.Unmarshal(out, &secrets); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"backup_bucket": "etcd", "backup_access_key": "minio", "backup_secret_key": "minio123"}
+	for k, v := range want {
+		if secrets[k] != v {
+			t.Errorf("%s = %v, want %v", k, secrets[k], v)
+		}
+	}
+}
+
+func TestEtcdRestoreCommand(t *testing.T) {
+	for _, restore := range []string{"", "snapshot.db"} {
+		m := newMocks()
+		var steps []pulumi.Resource
+		err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+			component := &K8sCluster{}
+			if err := ctx.RegisterComponentResource("pkg:k8s:K8sCluster", "c1", component); err != nil {
+				return err
+			}
+			ictx := backupCluster(BackupDef{Endpoint: "http://minio:9000", Restore: restore})
+			var err error
+			steps, err = setupEtcdRestore(ctx, "c1", ictx, nil, component)
+			return err
+		}, pulumi.WithMocks("project", "stack", m))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmd := m.resource("ansible-etcd-restore-c1")
+		if restore == "" {
+			if cmd != nil || len(steps) != 0 {
+				t.Error("a restore command is created without a restore")
+			}
+			continue
+		}
+		if cmd == nil || len(steps) != 1 {
+			t.Fatal("no restore command")
+		}
+		want := `ansible-playbook -i ./vars/inventory-c1.ini -e "@./vars/variables-c1.yaml" -e "@./vars/secrets-c1.yaml" ./.ansible/restore-etcd.yaml`
+		if got := cmd["create"].StringValue(); got != want {
+			t.Errorf("create = %s, want %s", got, want)
+		}
+		triggers := cmd["triggers"].ArrayValue()
+		if len(triggers) != 1 || triggers[0].StringValue() != restore {
+			t.Errorf("triggers = %v, want [%s]", triggers, restore)
+		}
+	}
+}
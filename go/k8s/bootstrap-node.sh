@@ -0,0 +1,258 @@
+#!/bin/bash
+# prepares a node the way install.yaml does, then runs kubeadm with /root/bootstrap/kubeadm.yaml
+# usage: bootstrap-node.sh <init|control-plane|worker> <kubernetes minor version> <containerd|docker|cri-o> <nat: true|false> [phase...]
+# the phases are network, system, packages, runtime and kubeadm, all of them by default, the CNI is installed by install.yaml
+set -euo pipefail
+role="$1"
+version="$2"
+cri="${3:-containerd}"
+nat="${4:-true}"
+shift 4 || true
+phases="${*:-network system packages runtime kubeadm}"
+config=/root/bootstrap/kubeadm.yaml
+
+network() {
+if [ "${nat}" = "true" ]; then
+  # nodes without public IP, the bastion routes the private network to the internet
+  cat > /etc/systemd/system/bastion-route.service <<EOF
+[Unit]
+Description=Default route through the bastion
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart=/sbin/ip route replace default via 10.0.0.1
+RemainAfterExit=true
+
+[Install]
+WantedBy=multi-user.target
+EOF
+  systemctl daemon-reload
+  systemctl enable --now bastion-route.service
+  if [ -f /etc/systemd/resolved.conf ]; then
+    sed -i 's/^#\?DNS=.*/DNS=185.12.64.2 185.12.64.1/' /etc/systemd/resolved.conf
+    systemctl restart systemd-resolved
+  elif command -v nmcli >/dev/null 2>&1; then
+    uuid=$(nmcli -t -f UUID con show --active | head -n 1)
+    nmcli con mod "${uuid}" ipv4.dns "185.12.64.2 185.12.64.1"
+    systemctl restart NetworkManager
+    systemctl restart bastion-route.service
+  fi
+fi
+if [ -d /etc/systemd/timesyncd.conf.d ]; then
+  systemctl restart systemd-timesyncd || true
+fi
+}
+
+system() {
+cat > /etc/modules-load.d/kubernetes.conf <<EOF
+overlay
+br_netfilter
+EOF
+modprobe overlay
+modprobe br_netfilter
+cat > /etc/sysctl.d/90-kubernetes.conf <<EOF
+net.ipv4.ip_forward = 1
+net.bridge.bridge-nf-call-ip6tables = 1
+net.bridge.bridge-nf-call-iptables = 1
+kernel.pid_max = 4194303
+fs.inotify.max_user_instances = 8192
+fs.file-max = 1024000
+net.ipv4.ip_local_port_range = 11000 65535
+net.ipv4.tcp_max_tw_buckets = 2000000
+net.ipv4.tcp_tw_reuse = 1
+net.ipv4.tcp_fin_timeout = 10
+net.ipv4.tcp_slow_start_after_idle = 0
+net.ipv4.tcp_low_latency = 1
+net.core.somaxconn = 40960
+net.netfilter.nf_conntrack_tcp_be_liberal = 1
+EOF
+sysctl --system || true
+swapoff -a
+sed -i 's/^\([^#].*\sswap\s\+sw\s.*\)$/# \1/' /etc/fstab
+}
+
+# the distribution family, debian or redhat, as ansible_os_family in install.yaml
+os_family() {
+. /etc/os-release
+case " ${ID} ${ID_LIKE:-} " in
+*" debian "*) echo debian ;;
+*" rhel "* | *" fedora "*) echo redhat ;;
+*) echo "unsupported distribution ${ID}" >&2; return 1 ;;
+esac
+}
+
+packages() {
+family=$(os_family)
+if [ "${family}" = "debian" ]; then
+  export DEBIAN_FRONTEND=noninteractive
+  apt-get update
+  apt-get install -y curl apt-transport-https ca-certificates gpg jq
+  mkdir -p /etc/apt/keyrings
+  curl -fsSL "https://pkgs.k8s.io/core:/stable:/v${version}/deb/Release.key" | gpg --batch --yes --dearmor -o /etc/apt/keyrings/kubernetes-apt-keyring.gpg
+  echo "deb [signed-by=/etc/apt/keyrings/kubernetes-apt-keyring.gpg] https://pkgs.k8s.io/core:/stable:/v${version}/deb/ /" > /etc/apt/sources.list.d/kubernetes.list
+  case "${cri}" in
+  docker)
+    distro=$(. /etc/os-release && echo "${ID}")
+    codename=$(. /etc/os-release && echo "${VERSION_CODENAME}")
+    curl -fsSL "https://download.docker.com/linux/${distro}/gpg" | gpg --batch --yes --dearmor -o /etc/apt/keyrings/docker.gpg
+    echo "deb [signed-by=/etc/apt/keyrings/docker.gpg] https://download.docker.com/linux/${distro} ${codename} stable" > /etc/apt/sources.list.d/docker.list
+    apt-get update
+    apt-get install -y docker-ce docker-ce-cli containerd.io
+    # the ubuntu package is the jammy one, as in install.yaml
+    if [ "${distro}" = "debian" ]; then
+      cri_dockerd="debian-${codename}"
+    else
+      cri_dockerd="ubuntu-jammy"
+    fi
+    curl -fsSL -o /tmp/cri-dockerd.deb "https://github.com/Mirantis/cri-dockerd/releases/download/v0.3.4/cri-dockerd_0.3.4.3-0.${cri_dockerd}_amd64.deb"
+    apt-get install -y /tmp/cri-dockerd.deb
+    rm -f /tmp/cri-dockerd.deb
+    ;;
+  cri-o)
+    curl -fsSL "https://pkgs.k8s.io/addons:/cri-o:/stable:/v${version}/deb/Release.key" | gpg --batch --yes --dearmor -o /etc/apt/keyrings/cri-o-apt-keyring.gpg
+    echo "deb [signed-by=/etc/apt/keyrings/cri-o-apt-keyring.gpg] https://pkgs.k8s.io/addons:/cri-o:/stable:/v${version}/deb/ /" > /etc/apt/sources.list.d/cri-o.list
+    apt-get update
+    apt-get install -y cri-o
+    ;;
+  *)
+    apt-get update
+    apt-get install -y containerd
+    ;;
+  esac
+  pkg=$(apt-cache show kubelet | grep "Version: ${version}" | head -n 1 | awk '{print $NF}')
+  apt-get install -y "kubeadm=${pkg}" "kubelet=${pkg}" "kubectl=${pkg}"
+else
+  cat > /etc/yum.repos.d/kubernetes.repo <<EOF
+[kubernetes]
+name=kubernetes repository
+baseurl=https://pkgs.k8s.io/core:/stable:/v${version}/rpm/
+gpgkey=https://pkgs.k8s.io/core:/stable:/v${version}/rpm/repodata/repomd.xml.key
+enabled=1
+exclude=kubelet kubeadm kubectl cri-tools kubernetes-cni
+EOF
+  setenforce 0 || true
+  sed -i 's/^SELINUX=enforcing$/SELINUX=permissive/' /etc/selinux/config || true
+  systemctl disable --now firewalld || true
+  yum remove -y hc-utils || true
+  yum install -y curl ca-certificates jq tar iscsi-initiator-utils
+  systemctl enable --now iscsid || true
+  case "${cri}" in
+  docker)
+    curl -fsSL https://download.docker.com/linux/centos/docker-ce.repo -o /etc/yum.repos.d/docker-ce.repo
+    yum install -y docker-ce docker-ce-cli containerd.io
+    major=$(. /etc/os-release && echo "${VERSION_ID%%.*}")
+    yum install -y --nogpgcheck "https://github.com/Mirantis/cri-dockerd/releases/download/v0.3.4/cri-dockerd-0.3.4-3.el${major}.x86_64.rpm"
+    ;;
+  cri-o)
+    cat > /etc/yum.repos.d/cri-o.repo <<EOF
+[cri-o]
+name=cri-o repository
+baseurl=https://pkgs.k8s.io/addons:/cri-o:/stable:/v${version}/rpm/
+gpgkey=https://pkgs.k8s.io/addons:/cri-o:/stable:/v${version}/rpm/repodata/repomd.xml.key
+enabled=1
+EOF
+    yum install -y cri-o
+    ;;
+  *)
+    curl -fsSL https://download.docker.com/linux/centos/docker-ce.repo -o /etc/yum.repos.d/docker-ce.repo
+    yum install -y containerd.io
+    ;;
+  esac
+  pkg=$(yum --showduplicates list kubeadm --disableexcludes=kubernetes | grep "${version}" | tail -n 1 | awk '{print $2}')
+  yum install -y "kubeadm-${pkg}" "kubelet-${pkg}" "kubectl-${pkg}" --disableexcludes=kubernetes
+fi
+}
+
+runtime() {
+case "${cri}" in
+docker)
+  sed -i 's|^ExecStart=.*|ExecStart=/usr/bin/dockerd -H fd:// --containerd=/run/containerd/containerd.sock --exec-opt native.cgroupdriver=systemd|' /usr/lib/systemd/system/docker.service
+  systemctl daemon-reload
+  systemctl enable docker cri-docker
+  systemctl restart docker cri-docker
+  ;;
+cri-o)
+  systemctl enable crio
+  systemctl restart crio
+  ;;
+*)
+  mkdir -p /etc/containerd
+  containerd config default > /etc/containerd/config.toml
+  sed -i -e 's/SystemdCgroup = false/SystemdCgroup = true/' \
+    -e 's|config_path = ""|config_path = "/etc/containerd/certs.d"|' \
+    -e '/disabled_plugins/d' /etc/containerd/config.toml
+  systemctl enable containerd
+  systemctl restart containerd
+  ;;
+esac
+systemctl enable kubelet
+}
+
+kubeadm_run() {
+if [ -f /etc/kubernetes/kubelet.conf ]; then
+  # already part of the cluster
+  rm -rf "${config}" /root/bootstrap/pki /root/bootstrap/secrets.*
+  return
+fi
+if [ -f /root/bootstrap/secrets.wait ]; then
+  # the keys and credentials are uploaded over ssh, they are not in the user data
+  for attempt in $(seq 180); do
+    if [ -f /root/bootstrap/secrets.ready ]; then
+      break
+    fi
+    if [ "${attempt}" = "180" ]; then
+      echo "the secret files were not uploaded" >&2
+      exit 1
+    fi
+    sleep 10
+  done
+fi
+if grep -q '@@PRIVATE_IP@@\|@@PUBLIC_IP@@' "${config}"; then
+  # single master without load balancer, the control plane endpoint is the node itself
+  metadata=http://169.254.169.254/hetzner/v1/metadata
+  private_ip=$(curl -fsSL "${metadata}/private-networks" | awk '/ip:/ {print $NF; exit}')
+  public_ip=$(curl -fsSL "${metadata}/public-ipv4")
+  sed -i -e "s/@@PRIVATE_IP@@/${private_ip}/g" -e "s/@@PUBLIC_IP@@/${public_ip}/g" "${config}"
+fi
+
+# kubeadm refuses to start with a clock too far off
+for _ in $(seq 30); do
+  if [ "$(timedatectl show -p NTPSynchronized --value 2>/dev/null)" = "yes" ]; then
+    break
+  fi
+  sleep 10
+done
+
+# joining nodes retry until the first master serves the API
+if [ "${role}" = "init" ]; then
+  cmd=(kubeadm init --config "${config}")
+else
+  cmd=(kubeadm join --config "${config}")
+fi
+for attempt in $(seq 60); do
+  if [ -d /root/bootstrap/pki ]; then
+    # kubeadm reset empties the pki directory
+    mkdir -p /etc/kubernetes/pki
+    cp -a /root/bootstrap/pki/. /etc/kubernetes/pki/
+  fi
+  if "${cmd[@]}"; then
+    break
+  fi
+  if [ "${attempt}" = "60" ]; then
+    exit 1
+  fi
+  kubeadm reset -f || true
+  sleep 20
+done
+rm -rf "${config}" /root/bootstrap/pki /root/bootstrap/secrets.*
+}
+
+for phase in ${phases}; do
+  case "${phase}" in
+  kubeadm) kubeadm_run ;;
+  network | system | packages | runtime) "${phase}" ;;
+  *) echo "unknown phase ${phase}" >&2; exit 1 ;;
+  esac
+done
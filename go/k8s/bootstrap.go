@@ -0,0 +1,292 @@
+package k8s
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed bootstrap-node.sh
+var bootstrapNodeScript []byte
+
+const (
+	bootstrapAnsible   = "ansible"
+	bootstrapCloudInit = "cloud-init"
+)
+
+// install.yaml tags of the init step after a cloud-init bootstrap, the cloudinit plays are skipped otherwise
+// the CNI is only installed by install.yaml
+const cloudInitTags = "cloudinit,cni,pullsecret"
+
+// kubeadm role of a node bootstrapped by cloud-init or ssh
+const (
+	roleInit         = "init"
+	roleControlPlane = "control-plane"
+	roleWorker       = "worker"
+)
+
+// validity of the bootstrap token of a cloud-init cluster, the user data keeps it readable for the lifetime of the server
+const cloudInitTokenTTL = "1h0m0s"
+
+// bootstrap-node.sh waits for the ready marker before kubeadm when the wait marker exists, the secret files are uploaded over ssh
+const (
+	secretsWaitFile  = "/root/bootstrap/secrets.wait"
+	secretsReadyFile = "/root/bootstrap/secrets.ready"
+)
+
+// addresses of a single master without load balancer, bootstrap-node.sh reads them from the metadata service
+const (
+	privateIPPlaceholder = "@@PRIVATE_IP@@"
+	publicIPPlaceholder  = "@@PUBLIC_IP@@"
+)
+
+func cloudInitBootstrap(cluster Cluster) bool {
+	return cluster.Bootstrap == bootstrapCloudInit
+}
+
+func validateBootstrap(clusterName string, cluster Cluster) error {
+	switch cluster.Bootstrap {
+	case "", bootstrapAnsible:
+		return nil
+	case bootstrapCloudInit, bootstrapSSH:
+	default:
+		return fmt.Errorf("bootstrap of cluster %s must be ansible, cloud-init or ssh", clusterName)
+	}
+	if cluster.AirGapped {
+		return fmt.Errorf("air_gapped cluster %s needs the ansible bootstrap to stage the bundle", clusterName)
+	}
+	if nativeRouting(cluster.Cilium) {
+		return fmt.Errorf("cluster %s uses cilium native routing, the node pod CIDRs are assigned by the ansible bootstrap", clusterName)
+	}
+	return nil
+}
+
+// docker daemon.json with the insecure registries and the docker.io mirrors
+func dockerDaemonJSON(inv Inventory) ([]byte, error) {
+	insecure := inv.InsecureRegistries
+	if insecure == nil {
+		insecure = []string{}
+	}
+	mirrors := inv.Registries["docker.io"].Mirrors
+	if mirrors == nil {
+		mirrors = []string{}
+	}
+	return json.MarshalIndent(map[string]interface{}{
+		"insecure-registries": insecure,
+		"registry-mirrors":    mirrors,
+	}, "", "    ")
+}
+
+// registries.conf.d drop-in of cri-o
+func crioRegistriesConf(inv Inventory) []byte {
+	var sb strings.Builder
+	sb.WriteString("# managed by cloud-init\n")
+	for _, e := range inv.RegistryEntries() {
+		fmt.Fprintf(&sb, "[[registry]]\nlocation = %q\ninsecure = %t\n", e.Host, e.Insecure)
+		for _, m := range e.Mirrors {
+			location := strings.TrimPrefix(strings.TrimPrefix(m, "https://"), "http://")
+			fmt.Fprintf(&sb, "\n[[registry.mirror]]\nlocation = %q\ninsecure = %t\n", location, strings.HasPrefix(m, "http://"))
+		}
+		sb.WriteString("\n")
+	}
+	return []byte(sb.String())
+}
+
+// registry configuration of the container runtime
+func runtimeFiles(inv Inventory) ([]cloudConfigFile, error) {
+	var files []cloudConfigFile
+	certsDir := "/etc/containerd/certs.d"
+	switch inv.Cri {
+	case "docker":
+		certsDir = "/etc/docker/certs.d"
+		daemon, err := dockerDaemonJSON(inv)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, b64File("/etc/docker/daemon.json", "0644", daemon))
+	case "cri-o":
+		certsDir = "/etc/containers/certs.d"
+		files = append(files, b64File("/etc/containers/registries.conf.d/10-kubeadm.conf", "0644", crioRegistriesConf(inv)))
+		if len(inv.RegistryCredentials) > 0 {
+			files = append(files, b64File("/etc/crio/crio.conf.d/10-auth.conf", "0644", []byte("[crio.image]\nglobal_auth_file = \"/var/lib/kubelet/config.json\"\n")))
+		}
+	}
+	for _, e := range inv.RegistryEntries() {
+		host, r := e.Host, inv.Registries[e.Host]
+		if inv.Cri == "" || inv.Cri == "containerd" {
+			files = append(files, b64File(fmt.Sprintf("%s/%s/hosts.toml", certsDir, host), "0644", []byte(hostsToml(host, r, inv.Registries))))
+		}
+		if r.CAFile != "" {
+			ca, err := os.ReadFile(filepath.Join("./vars", r.CAFile))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, b64File(fmt.Sprintf("%s/%s/ca.crt", certsDir, host), "0644", ca))
+		}
+	}
+	return files, nil
+}
+
+// files of the api server configuration read by kubeadm
+func controlPlaneFiles(inv Inventory) ([]cloudConfigFile, error) {
+	var files []cloudConfigFile
+	if inv.OIDC != nil && inv.OIDC.CAFile != "" {
+		ca, err := os.ReadFile(filepath.Join("./vars", inv.OIDC.CAFile))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, b64File("/root/bootstrap/pki/oidc-ca.crt", "0644", ca))
+	}
+	if inv.Audit.Enabled() {
+		policy, err := auditPolicy(inv.Audit)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, b64File(auditPolicyDir+"/policy.yaml", "0644", policy))
+	}
+	return files, nil
+}
+
+// keys and credentials of a node, never part of the user data, the ready marker comes last
+// the cluster CAs are kept in /root/bootstrap/pki because kubeadm reset empties /etc/kubernetes/pki
+func nodeSecretFiles(inv Inventory, role string) ([]cloudConfigFile, error) {
+	var files []cloudConfigFile
+	if role != roleWorker {
+		p := inv.Pki
+		files = append(files,
+			b64File("/root/bootstrap/pki/ca.crt", "0644", []byte(p.CACert)),
+			b64File("/root/bootstrap/pki/ca.key", "0600", []byte(p.CAKey)),
+			b64File("/root/bootstrap/pki/front-proxy-ca.crt", "0644", []byte(p.FrontProxyCACert)),
+			b64File("/root/bootstrap/pki/front-proxy-ca.key", "0600", []byte(p.FrontProxyCAKey)),
+			b64File("/root/bootstrap/pki/etcd/ca.crt", "0644", []byte(p.EtcdCACert)),
+			b64File("/root/bootstrap/pki/etcd/ca.key", "0600", []byte(p.EtcdCAKey)),
+			b64File("/root/bootstrap/pki/sa.key", "0600", []byte(p.SAKey)),
+			b64File("/root/bootstrap/pki/sa.pub", "0644", []byte(p.SAPub)),
+		)
+		if inv.Encryption.Enabled {
+			enc, err := encryptionConfig(inv.Encryption, inv.EncryptionKey)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, b64File(encryptionConfigDir+"/config.yaml", "0600", enc))
+		}
+	}
+	if len(inv.RegistryCredentials) > 0 {
+		dockerConfig, err := dockerConfigJSON(inv.RegistryCredentials)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, b64File("/var/lib/kubelet/config.json", "0600", []byte(dockerConfig)))
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	return append(files, b64File(secretsReadyFile, "0600", nil)), nil
+}
+
+// files of a node, written before bootstrap-node.sh runs
+func nodeFiles(inv Inventory, role string) ([]cloudConfigFile, error) {
+	initConfig, joinCP, joinWorker, err := kubeadmConfig(inv)
+	if err != nil {
+		return nil, err
+	}
+	kubeadmFile := map[string][]byte{roleInit: initConfig, roleControlPlane: joinCP, roleWorker: joinWorker}[role]
+	files := []cloudConfigFile{
+		b64File("/root/bootstrap/kubeadm.yaml", "0600", kubeadmFile),
+		b64File("/usr/local/sbin/bootstrap-node.sh", "0755", bootstrapNodeScript),
+	}
+	rf, err := runtimeFiles(inv)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, rf...)
+	// sorted, the rendered files are compared between runs
+	targets := make([]string, 0, len(inv.Kubeadm.Patches))
+	for target := range inv.Kubeadm.Patches {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		out, err := yaml.Marshal(inv.Kubeadm.Patches[target])
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, b64File("/etc/kubernetes/patches/"+target+"+strategic.yaml", "0600", out))
+	}
+	if servers := inv.Ntp.Servers(); len(servers) > 0 {
+		ntp := fmt.Sprintf("[Time]\nNTP=%s\nFallbackNTP=%s\n", servers[0], strings.Join(servers[1:], " "))
+		files = append(files, b64File("/etc/systemd/timesyncd.conf.d/ntp.conf", "0644", []byte(ntp)))
+	}
+	if role != roleWorker {
+		cf, err := controlPlaneFiles(inv)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, cf...)
+	}
+	secrets, err := nodeSecretFiles(inv, role)
+	if err != nil {
+		return nil, err
+	}
+	if len(secrets) > 0 {
+		files = append(files, b64File(secretsWaitFile, "0600", nil))
+	}
+	return files, nil
+}
+
+// arguments of bootstrap-node.sh, all phases run when none is given
+func bootstrapArgs(inv Inventory, role string, nat bool, phases ...string) []string {
+	cri := inv.Cri
+	if cri == "" {
+		cri = "containerd"
+	}
+	return append([]string{"/usr/local/sbin/bootstrap-node.sh", role, inv.K8sversion, cri, strconv.FormatBool(nat)}, phases...)
+}
+
+// cloud-init of a node, it prepares the node and runs kubeadm init or join
+// the secret files are uploaded over ssh unless they are inlined, for nodes pulumi does not create
+func nodeCloudInit(inv Inventory, role string, nat bool, inlineSecrets bool) (string, error) {
+	files, err := nodeFiles(inv, role)
+	if err != nil {
+		return "", err
+	}
+	if inlineSecrets {
+		secrets, err := nodeSecretFiles(inv, role)
+		if err != nil {
+			return "", err
+		}
+		files = append(files, secrets...)
+	}
+	out, err := yaml.Marshal(cloudConfig{WriteFiles: files, RunCmd: [][]string{bootstrapArgs(inv, role, nat)}})
+	if err != nil {
+		return "", err
+	}
+	return "#cloud-config\n" + string(out), nil
+}
+
+// user data of a server of a cloud-init cluster, rendered once the PKI and the load balancer are known
+func nodeUserData(ictx *infra, role string, nat bool) pulumi.StringPtrInput {
+	if !cloudInitBootstrap(*ictx.cluster) {
+		return nil
+	}
+	return ictx.ready().ApplyT(func(notUsed []interface{}) (*string, error) {
+		inv := *ictx.inventory
+		if inv.LoadBalancer == nil {
+			inv.MasterIPs = []*Node{{PrivateIP: privateIPPlaceholder, PublicIP: publicIPPlaceholder}}
+		}
+		userData, err := nodeCloudInit(inv, role, nat, false)
+		if err != nil {
+			return nil, err
+		}
+		return &userData, nil
+	}).(pulumi.StringPtrOutput)
+}
@@ -0,0 +1,318 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// inventory of c1 with keys, an encryption key and registry credentials that must stay out of the user data
+func bootstrapInventory(t *testing.T, cri, cni string) Inventory {
+	t.Helper()
+	ictx := testCluster(&Cluster{Cri: cri, KubernetesVersion: "1.30.2", Cni: CNIDef{Name: cni}, Bootstrap: bootstrapCloudInit})
+	inv := *ictx.inventory
+	inv.Pki = &PKI{
+		CACert: "ca cert", CAKey: "ca private key", FrontProxyCACert: "front proxy cert", FrontProxyCAKey: "front proxy private key",
+		EtcdCACert: "etcd cert", EtcdCAKey: "etcd private key", SAKey: "sa private key", SAPub: "sa public key",
+		CACertHash: "sha256:0000", BootstrapToken: "abcdef.0123456789abcdef",
+	}
+	inv.Encryption = EncryptionDef{Enabled: true}
+	inv.EncryptionKey = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	inv.Registries = map[string]RegistryDef{"registry.example.com": {Mirrors: []string{"https://mirror.example.com"}}}
+	inv.RegistryCredentials = map[string]RegistryCredentials{"registry.example.com": {Username: "user", Password: "registry password"}}
+	return inv
+}
+
+// rendered user data of a node, its files decoded by path
+func renderUserData(t *testing.T, inv Inventory, role string, inlineSecrets bool) (map[string]string, [][]string, string) {
+	t.Helper()
+	userData, err := nodeCloudInit(inv, role, true, inlineSecrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(userData, "#cloud-config\n") {
+		t.Fatalf("user data without the cloud-config header:\n%s", userData)
+	}
+	var config struct {
+		WriteFiles []cloudConfigFile `yaml:"write_files"`
+		RunCmd     [][]string        `yaml:"runcmd"`
+	}
+	if err := yaml.Unmarshal([]byte(userData), &config); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range config.WriteFiles {
+		content, err := base64.StdEncoding.DecodeString(f.Content)
+		if err != nil {
+			t.Fatalf("%s: %v", f.Path, err)
+		}
+		files[f.Path] = string(content)
+	}
+	return files, config.RunCmd, userData
+}
+
+func TestNodeCloudInit(t *testing.T) {
+	runtimeFile := map[string]string{
+		"containerd": "/etc/containerd/certs.d/registry.example.com/hosts.toml",
+		"docker":     "/etc/docker/daemon.json",
+		"cri-o":      "/etc/containers/registries.conf.d/10-kubeadm.conf",
+	}
+	secrets := []string{"ca private key", "front proxy private key", "etcd private key", "sa private key", "registry password",
+		base64.StdEncoding.EncodeToString([]byte("registry password")), base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))}
+	for cri, file := range runtimeFile {
+		for cni := range cniCatalog {
+			for _, role := range []string{roleInit, roleControlPlane, roleWorker} {
+				t.Run(cri+"/"+cni+"/"+role, func(t *testing.T) {
+					chdirVars(t)
+					inv := bootstrapInventory(t, cri, cni)
+					files, runCmd, userData := renderUserData(t, inv, role, false)
+					decoded := strings.Join(mapValues(files), "\n")
+					for _, secret := range secrets {
+						if strings.Contains(userData, secret) || strings.Contains(decoded, secret) {
+							t.Errorf("the user data holds %q", secret)
+						}
+					}
+					if _, ok := files[secretsWaitFile]; !ok {
+						t.Error("the node does not wait for its secret files")
+					}
+					if _, ok := files[secretsReadyFile]; ok {
+						t.Error("the ready marker is in the user data")
+					}
+					if _, ok := files[file]; !ok {
+						t.Errorf("no %s", file)
+					}
+					for path := range files {
+						if strings.HasPrefix(path, "/root/bootstrap/pki/") || strings.Contains(path, "cni") {
+							t.Errorf("unexpected file %s", path)
+						}
+					}
+					// the token read from the metadata service expires, the first control plane node creates it with the short ttl
+					kubeadmFile := files["/root/bootstrap/kubeadm.yaml"]
+					if !strings.Contains(kubeadmFile, inv.Pki.BootstrapToken) {
+						t.Error("the kubeadm configuration has no token")
+					}
+					if role == roleInit && !strings.Contains(kubeadmFile, "ttl: "+cloudInitTokenTTL) {
+						t.Errorf("the token does not expire:\n%s", kubeadmFile)
+					}
+					if len(runCmd) != 1 || strings.Join(runCmd[0], " ") != "/usr/local/sbin/bootstrap-node.sh "+role+" 1.30.2 "+cri+" true" {
+						t.Errorf("runcmd %v", runCmd)
+					}
+				})
+			}
+		}
+	}
+	// the same user data runs on every image, the script picks the package manager from /etc/os-release
+	for image, release := range nodeImages {
+		for cri := range runtimeFile {
+			t.Run(image+"/"+cri, func(t *testing.T) {
+				chdirVars(t)
+				files, runCmd, _ := renderUserData(t, bootstrapInventory(t, cri, "flannel"), roleWorker, false)
+				root, log, err := runBootstrapPackages(t, files["/usr/local/sbin/bootstrap-node.sh"], runCmd[0][1:], release.osRelease)
+				if err != nil {
+					t.Fatalf("%v\n%s", err, log)
+				}
+				if release.family == "debian" {
+					if !strings.Contains(log, "apt-get install -y kubeadm=1.30.2-1.1 kubelet=1.30.2-1.1 kubectl=1.30.2-1.1") || strings.Contains(log, "yum ") {
+						t.Errorf("the kubernetes packages are not installed with apt-get:\n%s", log)
+					}
+					if _, err := os.Stat(filepath.Join(root, "etc/apt/sources.list.d/kubernetes.list")); err != nil {
+						t.Error(err)
+					}
+				} else {
+					if !strings.Contains(log, "yum install -y kubeadm-1.30.2-150500.1.1 kubelet-1.30.2-150500.1.1 kubectl-1.30.2-150500.1.1") || strings.Contains(log, "apt-get ") {
+						t.Errorf("the kubernetes packages are not installed with yum:\n%s", log)
+					}
+					if _, err := os.Stat(filepath.Join(root, "etc/yum.repos.d/kubernetes.repo")); err != nil {
+						t.Error(err)
+					}
+				}
+				if cri != "docker" {
+					return
+				}
+				if release.family == "debian" {
+					docker, err := os.ReadFile(filepath.Join(root, "etc/apt/sources.list.d/docker.list"))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !strings.Contains(string(docker), release.dockerRepo) {
+						t.Errorf("docker repository %q, want %q", docker, release.dockerRepo)
+					}
+				}
+				if !strings.Contains(log, release.criDockerd) {
+					t.Errorf("cri-dockerd is not %s:\n%s", release.criDockerd, log)
+				}
+			})
+		}
+	}
+}
+
+// /etc/os-release of the images and the packages bootstrap-node.sh installs on them
+var nodeImages = map[string]struct {
+	osRelease  string
+	family     string
+	dockerRepo string
+	criDockerd string
+}{
+	"ubuntu-22.04": {"ID=ubuntu\nID_LIKE=debian\nVERSION_ID=\"22.04\"\nVERSION_CODENAME=jammy\n", "debian",
+		"https://download.docker.com/linux/ubuntu jammy stable", "cri-dockerd_0.3.4.3-0.ubuntu-jammy_amd64.deb"},
+	"ubuntu-24.04": {"ID=ubuntu\nID_LIKE=debian\nVERSION_ID=\"24.04\"\nVERSION_CODENAME=noble\n", "debian",
+		"https://download.docker.com/linux/ubuntu noble stable", "cri-dockerd_0.3.4.3-0.ubuntu-jammy_amd64.deb"},
+	"debian-12": {"ID=debian\nVERSION_ID=\"12\"\nVERSION_CODENAME=bookworm\n", "debian",
+		"https://download.docker.com/linux/debian bookworm stable", "cri-dockerd_0.3.4.3-0.debian-bookworm_amd64.deb"},
+	"centos-stream-9": {"ID=\"centos\"\nID_LIKE=\"rhel fedora\"\nVERSION_ID=\"9\"\n", "redhat",
+		"", "cri-dockerd-0.3.4-3.el9.x86_64.rpm"},
+	"rocky-9": {"ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\nVERSION_ID=\"9.4\"\n", "redhat",
+		"", "cri-dockerd-0.3.4-3.el9.x86_64.rpm"},
+}
+
+// runs the packages phase of bootstrap-node.sh in a temporary root with os-release, the package managers and
+// the network tools are stubs that log their arguments
+func runBootstrapPackages(t *testing.T, script string, args []string, osRelease string) (root, log string, err error) {
+	t.Helper()
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+	root = t.TempDir()
+	for _, dir := range []string{"bin", "etc/apt/sources.list.d", "etc/yum.repos.d", "etc/selinux", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "etc/os-release"), []byte(osRelease), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range []string{"awk", "cat", "grep", "head", "mkdir", "rm", "sed", "tail"} {
+		path, err := exec.LookPath(tool)
+		if err != nil {
+			t.Skipf("%s is not installed", tool)
+		}
+		if err := os.Symlink(path, filepath.Join(root, "bin", tool)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	logFile := filepath.Join(root, "commands.log")
+	stubs := map[string]string{
+		"apt-cache": `echo "Version: 1.30.2-1.1"`,
+		"yum":       `case "$*" in *--showduplicates*) echo "kubeadm.x86_64 1.30.2-150500.1.1 kubernetes" ;; esac`,
+	}
+	for _, tool := range []string{"apt-get", "apt-cache", "yum", "curl", "gpg", "systemctl", "setenforce"} {
+		stub := fmt.Sprintf("#!/bin/bash\necho \"%s $*\" >> %s\n%s\n", tool, logFile, stubs[tool])
+		if err := os.WriteFile(filepath.Join(root, "bin", tool), []byte(stub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scriptFile := filepath.Join(root, "bootstrap-node.sh")
+	script = strings.NewReplacer("/etc/", root+"/etc/", "/tmp/", root+"/tmp/").Replace(script)
+	if err := os.WriteFile(scriptFile, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("bash", append(append([]string{scriptFile}, args...), "packages")...)
+	cmd.Env = []string{"PATH=" + filepath.Join(root, "bin")}
+	out, err := cmd.CombinedOutput()
+	commands, _ := os.ReadFile(logFile)
+	return root, string(commands) + string(out), err
+}
+
+func TestBootstrapUnsupportedDistribution(t *testing.T) {
+	chdirVars(t)
+	files, runCmd, _ := renderUserData(t, bootstrapInventory(t, "containerd", "flannel"), roleWorker, false)
+	_, log, err := runBootstrapPackages(t, files["/usr/local/sbin/bootstrap-node.sh"], runCmd[0][1:], "ID=arch\n")
+	if err == nil || !strings.Contains(log, "unsupported distribution arch") {
+		t.Errorf("err = %v\n%s", err, log)
+	}
+	if strings.Contains(log, "install") {
+		t.Errorf("packages installed on an unsupported distribution:\n%s", log)
+	}
+}
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestNodeCloudInitInlineSecrets(t *testing.T) {
+	chdirVars(t)
+	inv := bootstrapInventory(t, "containerd", "cilium")
+	files, _, _ := renderUserData(t, inv, roleWorker, true)
+	if !strings.Contains(files["/var/lib/kubelet/config.json"], base64.StdEncoding.EncodeToString([]byte("user:registry password"))) {
+		t.Errorf("no registry credentials: %q", files["/var/lib/kubelet/config.json"])
+	}
+	if _, ok := files[secretsReadyFile]; !ok {
+		t.Error("the inlined secrets are not marked ready")
+	}
+	for path := range files {
+		if strings.HasPrefix(path, "/root/bootstrap/pki/") {
+			t.Errorf("a worker gets %s", path)
+		}
+	}
+}
+
+func TestNodeSecretFiles(t *testing.T) {
+	chdirVars(t)
+	inv := bootstrapInventory(t, "containerd", "flannel")
+	cp, err := nodeSecretFiles(inv, roleControlPlane)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]string{}
+	for _, f := range cp {
+		paths[f.Path] = f.Permissions
+	}
+	for _, path := range []string{"/root/bootstrap/pki/ca.key", "/root/bootstrap/pki/sa.key", encryptionConfigDir + "/config.yaml", "/var/lib/kubelet/config.json"} {
+		if paths[path] != "0600" {
+			t.Errorf("%s: permissions %q", path, paths[path])
+		}
+	}
+	if cp[len(cp)-1].Path != secretsReadyFile {
+		t.Errorf("the ready marker is not written last: %s", cp[len(cp)-1].Path)
+	}
+	// a worker without registry credentials has nothing to wait for
+	inv.RegistryCredentials = nil
+	worker, err := nodeSecretFiles(inv, roleWorker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if worker != nil {
+		t.Errorf("worker secret files %v", worker)
+	}
+	files, _, _ := renderUserData(t, inv, roleWorker, false)
+	if _, ok := files[secretsWaitFile]; ok {
+		t.Error("a worker without secrets waits for them")
+	}
+}
+
+// the script runs on the Debian and the RedHat family images
+func TestBootstrapNodeScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+	f, err := os.CreateTemp(t.TempDir(), "bootstrap-node-*.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(bootstrapNodeScript); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if out, err := exec.Command("bash", "-n", f.Name()).CombinedOutput(); err != nil {
+		t.Fatalf("bash -n: %v\n%s", err, out)
+	}
+	script := string(bootstrapNodeScript)
+	for _, s := range []string{"apt-get install", "yum install", "/etc/systemd/resolved.conf", "nmcli", "secrets.ready"} {
+		if !strings.Contains(script, s) {
+			t.Errorf("the script has no %q", s)
+		}
+	}
+	if strings.Contains(script, "cni()") {
+		t.Error("the script installs the CNI, install.yaml does")
+	}
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// renew the kubeadm certificates when certificates.rotate changes and collect the expiry dates of all certificates.
+// The returned steps are the installer and the rotation, the kubeconfig is current once they are done
+func setupCertificates(ctx *pulumi.Context, clusterName string, ictx *infra, installer []pulumi.Resource, pulumik8sCluster *K8sCluster) (pulumi.MapOutput, []pulumi.Resource, error) {
+	certs := ictx.cluster.Certificates
+	steps := append([]pulumi.Resource{}, installer...)
+	if certs.Rotate != "" {
+		rotation, err := local.NewCommand(ctx, fmt.Sprintf("ansible-certs-rotate-%s", clusterName), &local.CommandArgs{
+			Create:   pulumi.String(fmt.Sprintf("ansible-playbook -i ./vars/inventory-%s.ini -e \"@./vars/variables-%s.yaml\" ./.ansible/rotate-certs.yaml", clusterName, clusterName)),
+			Triggers: pulumi.Array{pulumi.String(certs.Rotate)},
+		}, dependsOnSteps(installer), pulumi.Parent(pulumik8sCluster))
+		if err != nil {
+			return pulumi.MapOutput{}, nil, err
+		}
+		steps = append(steps, rotation)
+	}
+	// the expiry dates are read again when the certificates are renewed, the control plane nodes change or certificates.refresh changes,
+	// the changed environment runs Update. Triggers would replace the command, and the Delete of the old one would remove the new status
+	statusCmd := pulumi.String(fmt.Sprintf("ansible-playbook -i ./vars/inventory-%s.ini -e \"@./vars/variables-%s.yaml\" ./.ansible/certs.yaml", clusterName, clusterName))
+	certStatus, err := local.NewCommand(ctx, fmt.Sprintf("ansible-certs-status-%s", clusterName), &local.CommandArgs{
+		Create: statusCmd,
+		Update: statusCmd,
+		Delete: pulumi.String("rm -rf ./vars/certs-" + clusterName + ".json"),
+		Environment: pulumi.StringMap{
+			"CERTS_ROTATE":  pulumi.String(certs.Rotate),
+			"CERTS_REFRESH": pulumi.String(certs.Refresh),
+			"CERTS_NODES": nodeIPs(ictx.cpNodes).ApplyT(func(ips []string) string {
+				return strings.Join(ips, ",")
+			}).(pulumi.StringOutput),
+		},
+	}, dependsOnSteps(steps), pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return pulumi.MapOutput{}, nil, err
+	}
+	status := pulumi.All(certStatus.Stdout, ictx.pki.ca.cert.ValidityEndTime,
+		ictx.pki.frontProxyCA.cert.ValidityEndTime, ictx.pki.etcdCA.cert.ValidityEndTime).ApplyT(
+		func(v []interface{}) (map[string]interface{}, error) {
+			nodes := make(map[string]interface{})
+			status, err := os.ReadFile("./vars/certs-" + clusterName + ".json")
+			if err == nil {
+				if err := json.Unmarshal(status, &nodes); err != nil {
+					ctx.Log.Warn("Cannot parse certificate status "+err.Error(), nil)
+				}
+			}
+			return map[string]interface{}{
+				"ca": map[string]interface{}{
+					"ca":             v[1].(string),
+					"front-proxy-ca": v[2].(string),
+					"etcd-ca":        v[3].(string),
+				},
+				"nodes": nodes,
+			}, nil
+		}).(pulumi.MapOutput)
+	return status, steps, nil
+}
@@ -0,0 +1,73 @@
+package k8s
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// the kubeconfig fetched by install.yaml, and the one rotate-certs.yaml fetches again
+const rotatedKubeconfig = "apiVersion: v1\nkind: Config\nclusters:\n- cluster:\n    server: https://127.0.0.1:6443\n  name: rotated\n"
+
+func certsCluster(rotate, refresh string) Cluster {
+	c1 := Cluster{Cri: "containerd", KubernetesVersion: "1.30.2", Cni: CNIDef{Name: "flannel"}}
+	c1.ControlPlane.NodeCount = 1
+	c1.Certificates = CertificatesDef{Rotate: rotate, Refresh: refresh}
+	return c1
+}
+
+// the playbooks of the ansible provisioner write their files when their commands are created
+func certsMocks() *mocks {
+	m := newMocks()
+	m.onCreate = map[string]func(){
+		"ansible-k8s-installer-c1": func() {
+			os.WriteFile("./vars/cluster-c1.kubeconfig", []byte(strings.Replace(rotatedKubeconfig, "rotated", "installed", 1)), 0600)
+		},
+		"ansible-certs-rotate-c1": func() {
+			os.WriteFile("./vars/cluster-c1.kubeconfig", []byte(rotatedKubeconfig), 0600)
+		},
+		"ansible-certs-status-c1": func() {
+			os.WriteFile("./vars/certs-c1.json", []byte(`{"10.0.1.3": {"apiserver": "2026-01-01T00:00:00Z"}}`), 0600)
+		},
+	}
+	return m
+}
+
+func TestCertificateRotationKubeconfig(t *testing.T) {
+	m := certsMocks()
+	config, err := deployCluster(t, NewAnsibleProvisioner(), m, certsCluster("2025-01", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.resource("ansible-certs-rotate-c1") == nil {
+		t.Fatal("no rotation")
+	}
+	// the kubeconfig is read once the rotation fetched the renewed one
+	if kc, _ := config["kubeconfig"].(string); !strings.Contains(kc, "name: rotated") {
+		t.Errorf("kubeconfig of the installer in the output:\n%s", kc)
+	}
+	certificates, _ := config["certificates"].(map[string]interface{})
+	if nodes, _ := certificates["nodes"].(map[string]interface{}); len(nodes) != 1 {
+		t.Errorf("certificate status %v", certificates)
+	}
+}
+
+func TestCertificateStatusEnvironment(t *testing.T) {
+	m := certsMocks()
+	config, err := deployCluster(t, NewAnsibleProvisioner(), m, certsCluster("", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.resource("ansible-certs-rotate-c1") != nil {
+		t.Error("the certificates are rotated without certificates.rotate")
+	}
+	if kc, _ := config["kubeconfig"].(string); !strings.Contains(kc, "name: installed") {
+		t.Errorf("kubeconfig %s", kc)
+	}
+	// the status is read again only when these change, an unchanged cluster has no diff
+	env := m.resource("ansible-certs-status-c1")["environment"].ObjectValue().Mappable()
+	nodes, _ := env["CERTS_NODES"].(string)
+	if len(env) != 3 || env["CERTS_ROTATE"] != "" || env["CERTS_REFRESH"] != "1" || !strings.HasPrefix(nodes, "10.0.1.") || strings.Contains(nodes, ",") {
+		t.Errorf("environment %v", env)
+	}
+}
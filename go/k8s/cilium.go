@@ -0,0 +1,341 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ip range of the hetzner private network and of its node subnet
+const (
+	networkRange = "10.0.0.0/16"
+	nodeSubnet   = "10.0.1.0/24"
+)
+
+// node port of the clustermesh-apiserver, reached by the other clusters over the private network
+const clusterMeshPort = 32379
+
+// cilium features on top of the chart defaults, only valid with cni cilium
+type CiliumDef struct {
+	Hubble HubbleDef `yaml:"hubble,omitempty"`
+	// transparent encryption of the pod traffic with WireGuard
+	WireGuard bool `yaml:"wireguard,omitempty"`
+	// route the pod traffic over the private network with hcloud routes instead of a tunnel
+	NativeRouting bool `yaml:"native_routing,omitempty"`
+	// pod CIDR of the cluster, split in one /24 per node, must be inside 10.0.0.0/16 with native routing
+	PodCIDR     string          `yaml:"pod_cidr,omitempty"`
+	ClusterMesh *ClusterMeshDef `yaml:"cluster_mesh,omitempty"`
+}
+
+type HubbleDef struct {
+	Relay bool `yaml:"relay,omitempty"`
+	UI    bool `yaml:"ui,omitempty"`
+}
+
+// membership in the mesh of all the clusters of the topology which have it set
+type ClusterMeshDef struct {
+	// unique across the mesh, 1-255
+	ID int `yaml:"cluster_id"`
+}
+
+// CA shared by the cilium installations of the mesh
+type CiliumCA struct {
+	Cert string
+	Key  string
+}
+
+// pod CIDR of a cluster
+func clusterPodSubnet(cluster Cluster) string {
+	if cluster.Cilium != nil && cluster.Cilium.PodCIDR != "" {
+		return cluster.Cilium.PodCIDR
+	}
+	return podSubnet
+}
+
+// index-th /24 of the cluster pod CIDR, control plane nodes come first then the workers
+func nodePodCIDR(subnet string, index int) (string, error) {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return "", err
+	}
+	ones, _ := ipNet.Mask.Size()
+	if ones > 24 || index >= 1<<(24-ones) {
+		return "", fmt.Errorf("pod CIDR %s has no room for node %d", subnet, index)
+	}
+	base := binary.BigEndian.Uint32(ipNet.IP.To4()) + uint32(index)<<8
+	ip := make(net.IP, 4)
+	binary.BigEndian.PutUint32(ip, base)
+	return fmt.Sprintf("%s/24", ip), nil
+}
+
+func nativeRouting(cilium *CiliumDef) bool {
+	return cilium != nil && cilium.NativeRouting
+}
+
+func cidrsOverlap(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// clusters whose pod CIDRs share the private network or the mesh, they must not overlap
+func routedClusters(clusters map[string]Cluster) []string {
+	names := make([]string, 0)
+	for name, c := range clusters {
+		if c.Cilium != nil && (c.Cilium.NativeRouting || c.Cilium.ClusterMesh != nil) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// others are the other clusters on the network
+func validateCilium(clusterName string, cluster Cluster, others map[string]Cluster) error {
+	cilium := cluster.Cilium
+	if cilium == nil {
+		return nil
+	}
+	if cluster.Cni.Name != "cilium" {
+		return fmt.Errorf("cilium is configured for cluster %s but its cni is %s", clusterName, cluster.Cni.Name)
+	}
+	_, podNet, err := net.ParseCIDR(clusterPodSubnet(cluster))
+	if err != nil {
+		return fmt.Errorf("cilium.pod_cidr of cluster %s: %w", clusterName, err)
+	}
+	if ones, _ := podNet.Mask.Size(); ones > 24 || cluster.ControlPlane.NodeCount+cluster.Worker.NodeCount > 1<<(24-ones) {
+		return fmt.Errorf("cilium.pod_cidr %s of cluster %s is too small for one /24 per node", podNet, clusterName)
+	}
+	if cilium.NativeRouting {
+		_, network, _ := net.ParseCIDR(networkRange)
+		_, nodes, _ := net.ParseCIDR(nodeSubnet)
+		_, gateway, _ := net.ParseCIDR("10.0.0.0/24")
+		netOnes, _ := network.Mask.Size()
+		podOnes, _ := podNet.Mask.Size()
+		if !network.Contains(podNet.IP) || podOnes < netOnes {
+			return fmt.Errorf("native routing needs cilium.pod_cidr of cluster %s inside the private network %s", clusterName, networkRange)
+		}
+		if cidrsOverlap(podNet, nodes) || cidrsOverlap(podNet, gateway) {
+			return fmt.Errorf("cilium.pod_cidr %s of cluster %s overlaps the node subnet", podNet, clusterName)
+		}
+	}
+	if mesh := cilium.ClusterMesh; mesh != nil && (mesh.ID < 1 || mesh.ID > 255) {
+		return fmt.Errorf("cilium.cluster_mesh.cluster_id of cluster %s must be between 1 and 255", clusterName)
+	}
+	if !cilium.NativeRouting && cilium.ClusterMesh == nil {
+		return nil
+	}
+	for _, other := range routedClusters(others) {
+		oc := others[other]
+		_, otherNet, err := net.ParseCIDR(clusterPodSubnet(oc))
+		if err == nil && cidrsOverlap(podNet, otherNet) {
+			return fmt.Errorf("pod CIDR %s of cluster %s overlaps the one of cluster %s, set distinct cilium.pod_cidr", podNet, clusterName, other)
+		}
+		if cilium.ClusterMesh != nil && oc.Cilium.ClusterMesh != nil && oc.Cilium.ClusterMesh.ID == cilium.ClusterMesh.ID {
+			return fmt.Errorf("clusters %s and %s have the same cilium.cluster_mesh.cluster_id", clusterName, other)
+		}
+	}
+	return nil
+}
+
+// the mesh CA is created with the core and the mesh is connected once the mesh clusters of the core are created
+func validateClusterMesh(clusterName string, cluster Cluster, mesh []string) error {
+	if cluster.Cilium == nil || cluster.Cilium.ClusterMesh == nil {
+		return nil
+	}
+	for _, name := range mesh {
+		if name == clusterName {
+			return nil
+		}
+	}
+	return fmt.Errorf("cluster %s joins the cilium cluster mesh, list it in the mesh clusters of the core", clusterName)
+}
+
+// helm values for the cilium block, merged over the defaults of the chart
+func ciliumValues(inv Inventory) map[interface{}]interface{} {
+	values := make(map[interface{}]interface{})
+	cilium := inv.Cilium
+	if cilium == nil {
+		return values
+	}
+	if cilium.Hubble.Relay || cilium.Hubble.UI {
+		values["hubble"] = map[interface{}]interface{}{
+			"enabled": true,
+			"relay":   map[interface{}]interface{}{"enabled": cilium.Hubble.Relay},
+			"ui":      map[interface{}]interface{}{"enabled": cilium.Hubble.UI},
+		}
+	}
+	if cilium.WireGuard {
+		values["encryption"] = map[interface{}]interface{}{
+			"enabled": true,
+			"type":    "wireguard",
+		}
+	}
+	if cilium.NativeRouting {
+		// the node pod CIDRs are set by ansible, hcloud routes point them to the nodes
+		values["routingMode"] = "native"
+		values["ipv4NativeRoutingCIDR"] = networkRange
+		values["autoDirectNodeRoutes"] = false
+		values["ipam"] = map[interface{}]interface{}{"mode": "kubernetes"}
+		values["k8s"] = map[interface{}]interface{}{"requireIPv4PodCIDR": true}
+	}
+	if cilium.ClusterMesh != nil {
+		values["cluster"] = map[interface{}]interface{}{
+			"name": inv.ClusterName,
+			"id":   cilium.ClusterMesh.ID,
+		}
+		values["clustermesh"] = map[interface{}]interface{}{
+			"useAPIServer": true,
+			"apiserver": map[interface{}]interface{}{
+				"service": map[interface{}]interface{}{
+					"type":     "NodePort",
+					"nodePort": clusterMeshPort,
+				},
+				"tls": map[interface{}]interface{}{
+					"auto": map[interface{}]interface{}{"enabled": true, "method": "helm"},
+				},
+			},
+		}
+	}
+	if inv.CiliumCA != nil {
+		values["tls"] = map[interface{}]interface{}{
+			"ca": map[interface{}]interface{}{
+				"cert": base64.StdEncoding.EncodeToString([]byte(inv.CiliumCA.Cert)),
+				"key":  base64.StdEncoding.EncodeToString([]byte(inv.CiliumCA.Key)),
+			},
+		}
+	}
+	return values
+}
+
+// node to node ports of the cilium block
+func ciliumNodeRules(cilium *CiliumDef) []nodeRule {
+	rules := make([]nodeRule, 0)
+	if cilium == nil {
+		return rules
+	}
+	if cilium.WireGuard {
+		rules = append(rules, nodeRule{"cilium WireGuard", "udp", "51871"})
+	}
+	if cilium.ClusterMesh != nil {
+		rules = append(rules, nodeRule{"cilium clustermesh-apiserver", "tcp", strconv.Itoa(clusterMeshPort)})
+	}
+	return rules
+}
+
+// CA shared by all the clusters of the mesh, nil without any
+func setupClusterMeshCA(ctx *pulumi.Context, mesh []string, opts ...pulumi.ResourceOption) (*certAuthority, error) {
+	if len(mesh) == 0 {
+		return nil, nil
+	}
+	return newCertAuthority(ctx, "cilium-clustermesh-ca", "Cilium CA", opts...)
+}
+
+// clusters of the topology in the cilium cluster mesh
+func meshClusters(topology *Topology) []string {
+	names := make([]string, 0)
+	for name, c := range topology.Clusters {
+		if c.Cilium != nil && c.Cilium.ClusterMesh != nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func setupCiliumCA(ictx *infra, ca *certAuthority) {
+	if ca == nil || ictx.cluster.Cilium == nil || ictx.cluster.Cilium.ClusterMesh == nil {
+		return
+	}
+	c := pulumi.All(ca.cert.CertPem, ca.key.PrivateKeyPem).ApplyT(func(v []interface{}) []string {
+		ictx.inventory.CiliumCA = &CiliumCA{Cert: v[0].(string), Key: v[1].(string)}
+		return make([]string, 0)
+	})
+	ictx.waitFor = append(ictx.waitFor, c)
+}
+
+// hcloud route sending the pod CIDR of a node to its private IP
+func setupPodRoute(ctx *pulumi.Context, ictx *infra, name string, server *hcloud.Server, index int, pulumik8sCluster *K8sCluster) (string, error) {
+	if !nativeRouting(ictx.cluster.Cilium) {
+		return "", nil
+	}
+	podCIDR, err := nodePodCIDR(ictx.inventory.PodSubnet, index)
+	if err != nil {
+		return "", err
+	}
+	_, err = hcloud.NewNetworkRoute(ctx, "pod-route-"+name, &hcloud.NetworkRouteArgs{
+		NetworkId:   ictx.core.network.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+		Destination: pulumi.String(podCIDR),
+		Gateway:     server.Networks.Index(pulumi.Int(0)).Ip().Elem(),
+	}, pulumi.Parent(pulumik8sCluster))
+	return podCIDR, err
+}
+
+// connect every cluster of the mesh to the others once all of them are installed
+func setupClusterMesh(ctx *pulumi.Context, members []*infra) error {
+	installers := make([]pulumi.Resource, 0, len(members))
+	// the mesh configuration holds the inventories of all members
+	ready := make([]interface{}, 0, len(members))
+	for _, m := range members {
+		installers = append(installers, m.installer)
+		ready = append(ready, m.ready())
+	}
+	for _, m := range members {
+		member := m
+		clusterName := member.inventory.ClusterName
+		values := pulumi.All(ready...).ApplyT(func(notUsed []interface{}) (string, error) {
+			out, err := clusterMeshValues(member, members)
+			return string(out), err
+		}).(pulumi.StringOutput)
+		// the values file is written by the command, it is removed with the other rendered files of the cluster
+		_, err := local.NewCommand(ctx, fmt.Sprintf("ansible-cilium-clustermesh-%s", clusterName), &local.CommandArgs{
+			Create:      pulumi.String(fmt.Sprintf("printf '%%s' \"$CLUSTERMESH_VALUES\" > ./vars/cilium-clustermesh-%s.yaml && ansible-playbook -i ./vars/inventory-%s.ini -e \"@./vars/variables-%s.yaml\" ./.ansible/clustermesh.yaml", clusterName, clusterName, clusterName)),
+			Environment: pulumi.StringMap{"CLUSTERMESH_VALUES": values},
+			// the mesh is connected again whenever a peer or its control plane nodes change
+			Triggers: pulumi.Array{values},
+		}, dependsOnSteps(installers), pulumi.Parent(member.component))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// helm values pointing a cluster at the clustermesh-apiservers of the other members
+func clusterMeshValues(member *infra, members []*infra) ([]byte, error) {
+	// sorted by name, the values do not change with the order the clusters are created in
+	peers := make([]*infra, 0, len(members)-1)
+	for _, peer := range members {
+		if peer != member {
+			peers = append(peers, peer)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].inventory.ClusterName < peers[j].inventory.ClusterName })
+	clusters := make([]interface{}, 0, len(peers))
+	for _, peer := range peers {
+		ips := make([]string, 0, len(peer.inventory.MasterIPs))
+		for _, n := range peer.inventory.MasterIPs {
+			ips = append(ips, n.PrivateIP)
+		}
+		sort.Strings(ips)
+		clusters = append(clusters, map[interface{}]interface{}{
+			"name": peer.inventory.ClusterName,
+			"port": clusterMeshPort,
+			"ips":  ips,
+		})
+	}
+	return yaml.Marshal(map[interface{}]interface{}{
+		"clustermesh": map[interface{}]interface{}{
+			"config": map[interface{}]interface{}{
+				"enabled":  true,
+				"clusters": clusters,
+			},
+		},
+	})
+}
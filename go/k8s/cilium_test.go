@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"gopkg.in/yaml.v3"
+)
+
+// mesh member with the control plane nodes ips
+func meshMember(t *testing.T, ctx *pulumi.Context, name string, ips ...string) *infra {
+	t.Helper()
+	ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: "1.30.2"})
+	ictx.core = &Core{}
+	ictx.inventory.ClusterName = name
+	ictx.inventory.MasterIPs = nil
+	for _, ip := range ips {
+		ictx.inventory.MasterIPs = append(ictx.inventory.MasterIPs, &Node{PrivateIP: ip})
+	}
+	if ctx != nil {
+		ictx.component = &K8sCluster{}
+		if err := ctx.RegisterComponentResource("pkg:k8s:K8sCluster", name, ictx.component); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ictx
+}
+
+func TestClusterMeshValues(t *testing.T) {
+	a := meshMember(t, nil, "a", "10.0.1.3", "10.0.1.2")
+	b := meshMember(t, nil, "b", "10.0.2.2")
+	c := meshMember(t, nil, "c", "10.0.3.2")
+	out, err := clusterMeshValues(a, []*infra{c, a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values struct {
+		ClusterMesh struct {
+			Config struct {
+				Enabled  bool
+				Clusters []struct {
+					Name string
+					Port int
+					IPs  []string `yaml:"ips"`
+				}
+			}
+		} `yaml:"clustermesh"`
+	}
+	if err := yaml.Unmarshal(out, &values); err != nil {
+		t.Fatal(err)
+	}
+	config := values.ClusterMesh.Config
+	if !config.Enabled || len(config.Clusters) != 2 {
+		t.Fatalf("values:\n%s", out)
+	}
+	if config.Clusters[0].Name != "b" || config.Clusters[1].Name != "c" || config.Clusters[0].Port != clusterMeshPort ||
+		strings.Join(config.Clusters[0].IPs, ",") != "10.0.2.2" {
+		t.Errorf("values:\n%s", out)
+	}
+	// the peers are sorted, the order of the members does not change the values
+	again, err := clusterMeshValues(a, []*infra{b, c, a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(out) {
+		t.Errorf("values depend on the member order:\n%s\n%s", out, again)
+	}
+	b.inventory.MasterIPs = append(b.inventory.MasterIPs, &Node{PrivateIP: "10.0.2.3"})
+	changed, err := clusterMeshValues(a, []*infra{a, b, c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(changed) == string(out) {
+		t.Error("a new control plane node of a peer does not change the values")
+	}
+}
+
+func TestClusterMeshCommand(t *testing.T) {
+	m := newMocks()
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		members := []*infra{meshMember(t, ctx, "a", "10.0.1.2"), meshMember(t, ctx, "b", "10.0.2.2")}
+		return setupClusterMesh(ctx, members)
+	}, pulumi.WithMocks("project", "stack", m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := m.resource("ansible-cilium-clustermesh-a")
+	if cmd == nil {
+		t.Fatal("no clustermesh command")
+	}
+	create := cmd["create"].StringValue()
+	if !strings.HasPrefix(create, `printf '%s' "$CLUSTERMESH_VALUES" > ./vars/cilium-clustermesh-a.yaml && ansible-playbook`) {
+		t.Errorf("the command does not write the values file: %s", create)
+	}
+	values := cmd["environment"].ObjectValue()["CLUSTERMESH_VALUES"].StringValue()
+	if !strings.Contains(values, "name: b") || strings.Contains(values, "name: a") {
+		t.Errorf("values of a:\n%s", values)
+	}
+	triggers := cmd["triggers"].ArrayValue()
+	if len(triggers) != 1 || triggers[0].StringValue() != values {
+		t.Errorf("triggers %v, want the rendered values", triggers)
+	}
+	if cmd.HasValue("delete") {
+		t.Error("the replaced command would remove the values file of its replacement")
+	}
+}
@@ -0,0 +1,1005 @@
+package k8s
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"text/template"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
+	"github.com/pulumi/pulumi-tls/sdk/v5/go/tls"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"gopkg.in/yaml.v2"
+)
+
+//go:embed inventory.tmpl
+var inventoryTmpl []byte
+
+//go:embed variables.tmpl
+var variablesTmpl []byte
+
+// read the cluster topology
+func ReadTopology(filename string) (*Topology, error) {
+	topology := &Topology{}
+	topo, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open topology file %s: %w", filename, err)
+	}
+	err = yaml.Unmarshal(topo, &topology)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal topology file %s, is it in correct format? %w", filename, err)
+	}
+	return topology, nil
+}
+
+// create the ssh key, the network, the firewalls and the bastion shared by the clusters of the topology
+func NewCore(ctx *pulumi.Context, args *CoreArgs) (core *Core, err error) {
+	if args == nil || args.Infra == nil || args.Topology == nil {
+		return nil, errors.New("the core needs the infrastructure configuration and the topology")
+	}
+	core = &Core{infraCfg: args.Infra, topology: args.Topology, mesh: meshClusters(args.Topology), provisioner: args.Provisioner, clusters: make(map[string]Cluster)}
+	if core.provisioner == nil {
+		core.provisioner = NewAnsibleProvisioner()
+	}
+	// generate a key pair
+	err = setupKeys(ctx, core)
+	if err != nil {
+		return
+	}
+	// network and subnet
+	err = setupNetwork(ctx, args.Infra, core)
+	if err != nil {
+		return
+	}
+	// jump server
+	err = setupNATAndBastionHost(ctx, args.Infra, core)
+	if err != nil {
+		return
+	}
+	core.bastionSetup, err = core.provisioner.PrepareBastion(ctx, args.Infra, core)
+	if err != nil {
+		return
+	}
+	core.meshCA, err = setupClusterMeshCA(ctx, core.mesh)
+	return
+}
+
+// private key of the ssh user of the servers
+func (core *Core) PrivateKey() pulumi.StringOutput {
+	return core.privateKey.PrivateKeyOpenssh
+}
+
+// public IP of the bastion
+func (core *Core) JumpServerIP() pulumi.StringOutput {
+	return core.jumpServer.Ipv4Address
+}
+
+// create a cluster of the topology of the core, with its servers, load balancer and PKI, and install kubernetes on it
+func NewK8sCluster(ctx *pulumi.Context, name string, args *K8sClusterArgs, opts ...pulumi.ResourceOption) (*K8sCluster, error) {
+	return NewK8sClusterOfType(ctx, "pkg:k8s:K8sCluster", name, args, opts...)
+}
+
+// same as NewK8sCluster, the component is registered with the given type token, see ClusterType
+func NewK8sClusterOfType(ctx *pulumi.Context, typ string, name string, args *K8sClusterArgs, opts ...pulumi.ResourceOption) (*K8sCluster, error) {
+	if args == nil || args.Core == nil {
+		return nil, fmt.Errorf("cluster %s needs a core, create it with NewCore or NewBastion", name)
+	}
+	core := args.Core
+	if core.jumpServer == nil {
+		return nil, fmt.Errorf("cluster %s needs a bastion, create it with NewBastion", name)
+	}
+	if _, ok := core.clusters[name]; ok {
+		return nil, fmt.Errorf("cluster %s is already created on this core", name)
+	}
+	infraCfg := args.Infra
+	if infraCfg == nil {
+		infraCfg = core.infraCfg
+	}
+	p := core.provisioner
+	clusterName := name
+	cluster := args.Cluster
+	c := cluster
+	pulumik8sCluster := &K8sCluster{}
+	err := ctx.RegisterComponentResource(typ, name, pulumik8sCluster, opts...)
+	if err != nil {
+		return nil, err
+	}
+	infra := NewClusterInfra(infraCfg, &c)
+	infra.inventory.ClusterName = clusterName
+	infra.core = core
+	infra.component = pulumik8sCluster
+	err = validateCri(cluster.Cri, cluster.KubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	err = validateKubeadm(cluster.Kubeadm)
+	if err != nil {
+		return nil, err
+	}
+	err = validateCNI(cluster.Cni, cluster.Kubeadm)
+	if err != nil {
+		return nil, err
+	}
+	err = validateCilium(clusterName, cluster, core.otherClusters(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	err = validateClusterMesh(clusterName, cluster, core.mesh)
+	if err != nil {
+		return nil, err
+	}
+	err = validateNtp(cluster.Ntp)
+	if err != nil {
+		return nil, err
+	}
+	err = validateOIDC(cluster.OIDC)
+	if err != nil {
+		return nil, err
+	}
+	err = validateAudit(cluster.Audit)
+	if err != nil {
+		return nil, err
+	}
+	err = validateEncryption(cluster.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	err = validateProvisioning(clusterName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	err = setupBackup(infraCfg, infra, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	err = validateRegistries(cluster.Registries)
+	if err != nil {
+		return nil, err
+	}
+	err = validateAddons(cluster.Addons)
+	if err != nil {
+		return nil, err
+	}
+	err = validateWorker(clusterName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	err = validateBootstrap(clusterName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	err = validateGitOps(clusterName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	err = validateReleases(clusterName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	err = validateAirGap(clusterName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAirGapArchive(clusterName, cluster)
+	if err != nil {
+		return nil, err
+	}
+	core.clusters[clusterName] = cluster
+	err = setupFirewalls(ctx, infra, clusterName, pulumik8sCluster)
+	if err != nil {
+		return nil, err
+	}
+	err = setupRegistries(infraCfg, infra, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	err = setupGitOps(infraCfg, infra, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	// cluster CAs and bootstrap token
+	err = setupPKI(ctx, infra, clusterName, pulumik8sCluster)
+	if err != nil {
+		return nil, err
+	}
+	err = setupEncryption(ctx, infra, clusterName, pulumik8sCluster)
+	if err != nil {
+		return nil, err
+	}
+	setupCiliumCA(infra, core.meshCA)
+	err = setupAutoscaler(ctx, infraCfg, infra, clusterName, pulumik8sCluster)
+	if err != nil {
+		return nil, err
+	}
+	// create load balancer condition
+	createLoadBal := (cluster.LoadBalancer.Create) || (cluster.ControlPlane.NodeCount+cluster.Worker.NodeCount > 1)
+	if createLoadBal {
+		// create loadbalancer, the cloud-init of the nodes needs its private IP
+		err = setupLoadBalancer(ctx, infraCfg, infra, cluster, clusterName, pulumik8sCluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for instanceIndex := 0; instanceIndex < cluster.ControlPlane.NodeCount; instanceIndex++ {
+		// control plane nodes
+		masterWorker := cluster.ControlPlane.NodeCount+cluster.Worker.NodeCount <= 1
+		err = setupCtrlPlaneNodes(ctx, infraCfg, infra, instanceIndex, clusterName, masterWorker, createLoadBal, pulumik8sCluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for instanceIndex := 0; instanceIndex < cluster.Worker.NodeCount; instanceIndex++ {
+		// worker nodes
+		err = setupWorkerNodes(ctx, infraCfg, infra, instanceIndex, clusterName, pulumik8sCluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if createLoadBal {
+		err = setupLoadBalancerTargets(ctx, infra, clusterName, pulumik8sCluster)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = setupSSHBootstrap(ctx, infraCfg, infra, clusterName, pulumik8sCluster)
+	if err != nil {
+		return nil, err
+	}
+	err = setupCloudInitSecrets(ctx, infraCfg, infra, clusterName, pulumik8sCluster)
+	if err != nil {
+		return nil, err
+	}
+	// create inventory and run ansible playbooks
+	config, err := installK8s(ctx, p, clusterName, infra, pulumik8sCluster)
+	if err != nil {
+		return nil, err
+	}
+	pulumik8sCluster.Config = pulumi.ToSecret(config).(pulumi.MapOutput)
+	pulumik8sCluster.Kubeconfig = pulumi.ToSecret(config.ApplyT(func(v map[string]interface{}) string {
+		kc, _ := v["kubeconfig"].(string)
+		return kc
+	})).(pulumi.StringOutput)
+	pulumik8sCluster.Endpoints = config.ApplyT(func(v map[string]interface{}) map[string]string {
+		endpoints := make(map[string]string)
+		e, _ := v["endpoints"].(map[string]interface{})
+		for k, ep := range e {
+			endpoints[k], _ = ep.(string)
+		}
+		return endpoints
+	}).(pulumi.StringMapOutput)
+	pulumik8sCluster.APIServer = pulumik8sCluster.Endpoints.ApplyT(func(e map[string]string) string {
+		if e["cluster-api"] == "" {
+			return ""
+		}
+		return "https://" + e["cluster-api"] + ":6443"
+	}).(pulumi.StringOutput)
+	pulumik8sCluster.ControlPlaneNodes = nodeIPs(infra.cpNodes)
+	pulumik8sCluster.WorkerNodes = nodeIPs(infra.workerNodes)
+	pulumik8sCluster.Provisioning = p.Summary(ctx, infra.provisionContext())
+	err = ctx.RegisterResourceOutputs(pulumik8sCluster, pulumi.Map{
+		"clusterName":       pulumi.String(clusterName),
+		"kubeconfig":        pulumik8sCluster.Kubeconfig,
+		"apiServer":         pulumik8sCluster.APIServer,
+		"endpoints":         pulumik8sCluster.Endpoints,
+		"controlPlaneNodes": pulumik8sCluster.ControlPlaneNodes,
+		"workerNodes":       pulumik8sCluster.WorkerNodes,
+		"provisioning":      pulumik8sCluster.Provisioning,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cluster.Cilium != nil && cluster.Cilium.ClusterMesh != nil {
+		core.meshMembers = append(core.meshMembers, infra)
+		// the mesh is connected once its last member is created
+		if len(core.meshMembers) > 1 && len(core.meshMembers) == len(core.mesh) {
+			err = setupClusterMesh(ctx, core.meshMembers)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pulumik8sCluster, nil
+}
+
+// private IPs of servers
+func nodeIPs(servers []*hcloud.Server) pulumi.StringArrayOutput {
+	ips := pulumi.StringArray{}
+	for _, s := range servers {
+		ips = append(ips, s.Networks.Index(pulumi.Int(0)).Ip().Elem())
+	}
+	return ips.ToStringArrayOutput()
+}
+
+func installK8s(ctx *pulumi.Context, p Provisioner, clusterName string, ictx *infra, pulumik8sCluster *K8sCluster) (config pulumi.MapOutput, err error) {
+	pctx := ictx.provisionContext()
+	nodes, err := p.PrepareNodes(ctx, pctx, append([]pulumi.Resource{ictx.core.bastionSetup}, ictx.bootstrapSteps...))
+	if err != nil {
+		return
+	}
+	initStep, err := p.Init(ctx, pctx, []pulumi.Resource{nodes})
+	if err != nil {
+		return
+	}
+	ictx.installer = initStep
+	join, err := p.Join(ctx, pctx, []pulumi.Resource{initStep})
+	if err != nil {
+		return
+	}
+	addons, err := p.InstallAddons(ctx, pctx, []pulumi.Resource{initStep, join})
+	if err != nil {
+		return
+	}
+	restore, err := setupEtcdRestore(ctx, clusterName, ictx, []pulumi.Resource{initStep, join, addons}, pulumik8sCluster)
+	if err != nil {
+		return
+	}
+	certificates, renewed, err := setupCertificates(ctx, clusterName, ictx, restore, pulumik8sCluster)
+	if err != nil {
+		return
+	}
+	kc, err := p.FetchKubeconfig(ctx, pctx, renewed)
+	if err != nil {
+		return
+	}
+	// the endpoints are read from the inventory
+	config = pulumi.All(kc, certificates, ictx.core.privateKey.PrivateKeyOpenssh, ictx.ready(), p.Summary(ctx, pctx)).ApplyT(func(v []interface{}) (map[string]interface{}, error) {
+		kc := v[0].(string)
+		cConfig := make(map[string]interface{})
+		endPointConfig := make(map[string]interface{})
+		var kubeConfig string
+		if kc == "" {
+			return nil, nil
+		}
+		m1 := regexp.MustCompile(`server:.*`)
+		if ictx.inventory.LoadBalancer != nil {
+			kubeConfig = m1.ReplaceAllString(kc, "server: https://"+ictx.inventory.LoadBalancer.PublicIP+":6443")
+			endPointConfig["app"] = ictx.inventory.LoadBalancer.PublicIP
+			endPointConfig["cluster-api"] = ictx.inventory.LoadBalancer.PublicIP
+			endPointConfig["type"] = "LoadBalancer"
+		} else {
+			if len(ictx.inventory.WorkerIPs) > 0 {
+				endPointConfig["app"] = ictx.inventory.WorkerIPs[0].PublicIP
+			} else {
+				endPointConfig["app"] = ictx.inventory.MasterIPs[0].PublicIP
+			}
+			endPointConfig["cluster-api"] = ictx.inventory.MasterIPs[0].PublicIP
+			kubeConfig = m1.ReplaceAllString(kc, "server: https://"+ictx.inventory.MasterIPs[0].PublicIP+":6443")
+			endPointConfig["type"] = "NodePort"
+		}
+		cConfig["endpoints"] = endPointConfig
+		cConfig["kubeconfig"] = kubeConfig
+		if ictx.inventory.OIDC != nil {
+			oidcConfig, err := oidcKubeconfig(clusterName, "https://"+endPointConfig["cluster-api"].(string)+":6443", ictx.inventory.Pki.CACert, ictx.inventory.OIDC)
+			if err != nil {
+				return nil, err
+			}
+			cConfig["oidcKubeconfig"] = oidcConfig
+		}
+		// the inventory is only rendered by the ansible provisioner
+		if inv, err := os.ReadFile("./vars/inventory-" + clusterName + ".ini"); err == nil {
+			cConfig["inventory"] = string(inv)
+		}
+		cConfig["privateKey"] = v[2].(string)
+		cConfig["pki"] = map[string]interface{}{
+			"caCert":           ictx.inventory.Pki.CACert,
+			"caKey":            ictx.inventory.Pki.CAKey,
+			"frontProxyCACert": ictx.inventory.Pki.FrontProxyCACert,
+			"frontProxyCAKey":  ictx.inventory.Pki.FrontProxyCAKey,
+			"etcdCACert":       ictx.inventory.Pki.EtcdCACert,
+			"etcdCAKey":        ictx.inventory.Pki.EtcdCAKey,
+			"saKey":            ictx.inventory.Pki.SAKey,
+			"saPub":            ictx.inventory.Pki.SAPub,
+		}
+		cConfig["certificates"] = v[1]
+		// changed, failed and unreachable hosts of the last run of each step
+		cConfig["provisioning"] = v[4]
+		if releases, err := readReleaseStatus(clusterName); err == nil {
+			cConfig["releases"] = releases
+		}
+		return cConfig, nil
+	}).(pulumi.MapOutput)
+
+	return config, nil
+}
+
+func setupLoadBalancer(ctx *pulumi.Context, infraCfg *InfraConfig, ictx *infra, c Cluster, clusterName string, pulumik8sCluster *K8sCluster) (err error) {
+	ictx.loadBal, err = hcloud.NewLoadBalancer(ctx, fmt.Sprintf("loadBalancer-%s", clusterName), &hcloud.LoadBalancerArgs{
+		LoadBalancerType: pulumi.String(infraCfg.LbType),
+		NetworkZone:      pulumi.String(infraCfg.NetworkZone),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	lbNetwork, err := hcloud.NewLoadBalancerNetwork(ctx, fmt.Sprintf("srvnetwork-%s", clusterName), &hcloud.LoadBalancerNetworkArgs{
+		LoadBalancerId: ictx.loadBal.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+		SubnetId:       ictx.core.subnet.ID(),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	_, err = hcloud.NewLoadBalancerService(ctx, fmt.Sprintf("lbService-%s-kube-api-6443", clusterName), &hcloud.LoadBalancerServiceArgs{
+		LoadBalancerId:  ictx.loadBal.ID(),
+		Protocol:        pulumi.String("tcp"),
+		DestinationPort: pulumi.Int(6443),
+		ListenPort:      pulumi.Int(6443),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	// always create for http and https
+	_, err = hcloud.NewLoadBalancerService(ctx, fmt.Sprintf("lbService-%s-%s-%d", clusterName, "ingress-http", 80), &hcloud.LoadBalancerServiceArgs{
+		LoadBalancerId:  ictx.loadBal.ID(),
+		Protocol:        pulumi.String("tcp"),
+		DestinationPort: pulumi.Int(31394),
+		ListenPort:      pulumi.Int(80),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	_, err = hcloud.NewLoadBalancerService(ctx, fmt.Sprintf("lbService-%s-%s-%d", clusterName, "ingress-https", 443), &hcloud.LoadBalancerServiceArgs{
+		LoadBalancerId:  ictx.loadBal.ID(),
+		Protocol:        pulumi.String("tcp"),
+		DestinationPort: pulumi.Int(31390),
+		ListenPort:      pulumi.Int(443),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	for name, mapping := range c.LoadBalancer.PortMappings {
+		if mapping.Source == 80 || mapping.Source == 443 {
+			return errors.New("ports 80 and 443 cannot be defined for loadbalancer. They default to 80:31394 443:31390")
+		}
+		_, err = hcloud.NewLoadBalancerService(ctx, fmt.Sprintf("lbService-%s-%s-%d", clusterName, name, mapping.Source), &hcloud.LoadBalancerServiceArgs{
+			LoadBalancerId:  ictx.loadBal.ID(),
+			Protocol:        pulumi.String("tcp"),
+			DestinationPort: pulumi.Int(mapping.Target),
+			ListenPort:      pulumi.Int(mapping.Source),
+		}, pulumi.Parent(pulumik8sCluster))
+		if err != nil {
+			return
+		}
+	}
+	lb := pulumi.All(lbNetwork.Ip, ictx.loadBal.Ipv4).ApplyT(func(ips []interface{}) []string {
+		node := &Node{}
+		node.PrivateIP = ips[0].(string)
+		node.PublicIP = ips[1].(string)
+		ictx.inventory.LoadBalancer = node
+		return make([]string, 0)
+	}).(pulumi.StringArrayOutput)
+	ictx.waitFor = append(ictx.waitFor, lb)
+
+	return
+}
+
+// send the load balancer traffic to the private IPs of the nodes
+func setupLoadBalancerTargets(ctx *pulumi.Context, ictx *infra, clusterName string, pulumik8sCluster *K8sCluster) error {
+	ictx.loadBalTargets = make([]*hcloud.LoadBalancerTarget, 0)
+	for i, cpNode := range ictx.cpNodes {
+		lbT, err := hcloud.NewLoadBalancerTarget(ctx, fmt.Sprintf("lbtarget-%s-cp-%d", clusterName, i), &hcloud.LoadBalancerTargetArgs{
+			Type:           pulumi.String("server"),
+			LoadBalancerId: ictx.loadBal.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+			ServerId:       cpNode.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+			UsePrivateIp:   pulumi.Bool(true),
+		}, pulumi.Parent(pulumik8sCluster))
+		if err != nil {
+			return err
+		}
+		ictx.loadBalTargets = append(ictx.loadBalTargets, lbT)
+	}
+	for i, worker := range ictx.workerNodes {
+		lbT, err := hcloud.NewLoadBalancerTarget(ctx, fmt.Sprintf("lbtarget-%s-wrk-%d", clusterName, i), &hcloud.LoadBalancerTargetArgs{
+			Type:           pulumi.String("server"),
+			LoadBalancerId: ictx.loadBal.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+			ServerId:       worker.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+			UsePrivateIp:   pulumi.Bool(true),
+		}, pulumi.Parent(pulumik8sCluster))
+		if err != nil {
+			return err
+		}
+		ictx.loadBalTargets = append(ictx.loadBalTargets, lbT)
+	}
+	return nil
+}
+
+func setupWorkerNodes(ctx *pulumi.Context, infraCfg *InfraConfig, ictx *infra, index int, clusterName string, pulumik8sCluster *K8sCluster) (err error) {
+	if ictx.workerNodes == nil {
+		ictx.workerNodes = make([]*hcloud.Server, 0)
+	}
+	workerNode, err := hcloud.NewServer(ctx, fmt.Sprintf("worker-%s-%d", clusterName, index), &hcloud.ServerArgs{
+		Image:                 pulumi.String(infraCfg.Image),
+		Datacenter:            pulumi.String(infraCfg.DataCenter),
+		ServerType:            pulumi.String(infraCfg.WorkerFlavor),
+		SshKeys:               pulumi.StringArray{ictx.core.sshKey.ID()},
+		AllowDeprecatedImages: pulumi.Bool(true),
+		PublicNets: hcloud.ServerPublicNetArray{hcloud.ServerPublicNetArgs{
+			Ipv4Enabled: pulumi.Bool(false),
+			Ipv6Enabled: pulumi.Bool(false),
+		}},
+		Networks: hcloud.ServerNetworkTypeArray{
+			hcloud.ServerNetworkTypeArgs{
+				NetworkId: ictx.core.subnet.NetworkId,
+			}},
+		FirewallIds: pulumi.IntArray{
+			ictx.workerFirewall.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+		},
+		// cloud-init only runs on the first boot, a change does not replace the server
+		UserData: nodeUserData(ictx, roleWorker, true),
+	}, pulumi.Parent(pulumik8sCluster), pulumi.IgnoreChanges([]string{"userData"}))
+	if err != nil {
+		return
+	}
+	ictx.workerNodes = append(ictx.workerNodes, workerNode)
+	// node pod CIDRs follow the control plane ones
+	podCIDR, err := setupPodRoute(ctx, ictx, fmt.Sprintf("worker-%s-%d", clusterName, index), workerNode, ictx.cluster.ControlPlane.NodeCount+index, pulumik8sCluster)
+	if err != nil {
+		return
+	}
+	wn := workerNode.Networks.Index(pulumi.Int(0)).Ip().ApplyT(func(ip *string) string {
+		node := &Node{}
+		node.PrivateIP = *ip
+		node.PodCIDR = podCIDR
+		// workers will never have public IP
+		ictx.inventory.WorkerIPs = append(ictx.inventory.WorkerIPs, node)
+		return ""
+	})
+	ictx.waitFor = append(ictx.waitFor, wn)
+	return
+}
+
+func setupCtrlPlaneNodes(ctx *pulumi.Context, infraCfg *InfraConfig, ictx *infra, index int, clusterName string, masterWorker bool, createLoadBal bool, pulumik8sCluster *K8sCluster) (err error) {
+	if ictx.cpNodes == nil {
+		ictx.cpNodes = make([]*hcloud.Server, 0)
+	}
+	role := roleControlPlane
+	if index == 0 {
+		role = roleInit
+	}
+	var flavor string
+	if masterWorker {
+		flavor = infraCfg.WorkerFlavor
+	} else {
+		flavor = infraCfg.MasterFlavor
+	}
+	cpNode, err := hcloud.NewServer(ctx, fmt.Sprintf("control-plane-%s-%d", clusterName, index), &hcloud.ServerArgs{
+		Image:                 pulumi.String(infraCfg.Image),
+		Datacenter:            pulumi.String(infraCfg.DataCenter),
+		ServerType:            pulumi.String(flavor),
+		SshKeys:               pulumi.StringArray{ictx.core.sshKey.ID()},
+		AllowDeprecatedImages: pulumi.Bool(true),
+		PublicNets: hcloud.ServerPublicNetArray{hcloud.ServerPublicNetArgs{
+			Ipv4Enabled: pulumi.Bool(!createLoadBal),
+			Ipv6Enabled: pulumi.Bool(false),
+		}},
+		Networks: hcloud.ServerNetworkTypeArray{
+			hcloud.ServerNetworkTypeArgs{
+				NetworkId: ictx.core.subnet.NetworkId,
+			}},
+		FirewallIds: pulumi.IntArray{
+			ictx.ctrlPlaneFirewall.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+		},
+		// cloud-init only runs on the first boot, a change does not replace the server
+		UserData: nodeUserData(ictx, role, createLoadBal),
+	}, pulumi.Parent(pulumik8sCluster), pulumi.IgnoreChanges([]string{"userData"}))
+	if err != nil {
+		return
+	}
+	ictx.cpNodes = append(ictx.cpNodes, cpNode)
+	podCIDR, err := setupPodRoute(ctx, ictx, fmt.Sprintf("control-plane-%s-%d", clusterName, index), cpNode, index, pulumik8sCluster)
+	if err != nil {
+		return
+	}
+
+	cp := pulumi.All(cpNode.Ipv4Address, cpNode.Networks.Index(pulumi.Int(0)).Ip()).ApplyT(
+		func(ips []interface{}) []string {
+			node := &Node{}
+			node.PrivateIP = *ips[1].(*string)
+			node.PublicIP = ips[0].(string)
+			node.PodCIDR = podCIDR
+			ictx.inventory.MasterIPs = append(ictx.inventory.MasterIPs, node)
+			return make([]string, 0)
+		})
+
+	ictx.waitFor = append(ictx.waitFor, cp)
+	return
+}
+
+func setupNATAndBastionHost(ctx *pulumi.Context, infraCfg *InfraConfig, coreinfra *Core, opts ...pulumi.ResourceOption) (err error) {
+	if err = validateNtp(infraCfg.Ntp); err != nil {
+		return fmt.Errorf("ntp of the bastion: %w", err)
+	}
+	coreinfra.jumpServer, err = hcloud.NewServer(ctx, "jump-server", &hcloud.ServerArgs{
+		Image:                 pulumi.String("ubuntu-24.04"),
+		Datacenter:            pulumi.String(infraCfg.DataCenter),
+		ServerType:            pulumi.String("cpx11"),
+		SshKeys:               pulumi.StringArray{coreinfra.sshKey.ID()},
+		AllowDeprecatedImages: pulumi.Bool(true),
+		PublicNets: hcloud.ServerPublicNetArray{hcloud.ServerPublicNetArgs{
+			Ipv4Enabled: pulumi.Bool(true),
+			Ipv6Enabled: pulumi.Bool(false),
+		}},
+		Networks: hcloud.ServerNetworkTypeArray{
+			hcloud.ServerNetworkTypeArgs{
+				NetworkId: coreinfra.subnet.NetworkId,
+			}},
+		FirewallIds: pulumi.IntArray{
+			coreinfra.jumpServerFirewall.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+		},
+	}, opts...)
+	if err != nil {
+		return
+	}
+	bas := pulumi.All(coreinfra.jumpServer.Networks.Index(pulumi.Int(0)).Ip(), coreinfra.jumpServer.Ipv4Address).ApplyT(
+		func(ips []interface{}) []string {
+			node := &Node{}
+			node.PrivateIP = *ips[0].(*string)
+			node.PublicIP = ips[1].(string)
+			coreinfra.bastion = node
+			return make([]string, 0)
+		},
+	)
+
+	coreinfra.waitFor = append(coreinfra.waitFor, bas)
+
+	bastionNet, err := hcloud.NewServerNetwork(ctx, "bastion-private-net", &hcloud.ServerNetworkArgs{
+		ServerId:  coreinfra.jumpServer.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+		NetworkId: coreinfra.network.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+	}, opts...)
+	if err != nil {
+		return
+	}
+	_, err = hcloud.NewNetworkRoute(ctx, "nat-route", &hcloud.NetworkRouteArgs{
+		NetworkId:   coreinfra.network.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+		Destination: pulumi.String("0.0.0.0/0"),
+		Gateway:     bastionNet.Ip,
+	}, opts...)
+	return
+}
+
+func setupKeys(ctx *pulumi.Context, ictx *Core, opts ...pulumi.ResourceOption) (err error) {
+	ictx.privateKey, err = tls.NewPrivateKey(ctx, "pulumi-hcloud-kubeadm", &tls.PrivateKeyArgs{
+		Algorithm: pulumi.String("RSA"),
+	}, opts...)
+	if err != nil {
+		return
+	}
+	pkey, err := local.NewCommand(ctx, "gen-privatekey", &local.CommandArgs{
+		Create: ictx.privateKey.PrivateKeyOpenssh.ApplyT(func(privateKey string) string {
+			er := os.WriteFile("./vars/id_rsa", []byte(privateKey), 0600)
+			if er != nil {
+				return "false"
+			}
+			return "echo \"./vars/id_rsa created\""
+		}).(pulumi.StringInput),
+		Delete: pulumi.String("rm -rf ./vars/id_rsa"),
+	}, opts...)
+	if err != nil {
+		return
+	}
+	ictx.sshKey, err = hcloud.NewSshKey(ctx, "pulumi-hcloud-kubeadm", &hcloud.SshKeyArgs{
+		PublicKey: ictx.privateKey.PublicKeyOpenssh,
+	}, append(opts, pulumi.DependsOn([]pulumi.Resource{pkey}))...)
+
+	ictx.sshKey.ToSshKeyOutput()
+	return
+}
+
+func setupNetwork(ctx *pulumi.Context, infraCfg *InfraConfig, ictx *Core, opts ...pulumi.ResourceOption) (err error) {
+	ictx.network, err = hcloud.NewNetwork(ctx, "kubeadm-network", &hcloud.NetworkArgs{
+		IpRange: pulumi.String(networkRange),
+	}, opts...)
+	if err != nil {
+		return
+	}
+	ictx.subnet, err = hcloud.NewNetworkSubnet(ctx, "kubeadm-network-subnet", &hcloud.NetworkSubnetArgs{
+		NetworkId:   ictx.network.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
+		Type:        pulumi.String("cloud"),
+		NetworkZone: pulumi.String(infraCfg.NetworkZone),
+		IpRange:     pulumi.String(nodeSubnet),
+	}, opts...)
+	if err != nil {
+		return
+	}
+	ictx.jumpServerFirewall, err = hcloud.NewFirewall(ctx, "jump-server-firewall", &hcloud.FirewallArgs{
+		Rules: hcloud.FirewallRuleArray{
+			&hcloud.FirewallRuleArgs{
+				Direction: pulumi.String("in"),
+				Protocol:  pulumi.String("tcp"),
+				Port:      pulumi.String("22"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("0.0.0.0/0"),
+				},
+			},
+		},
+	}, opts...)
+	if err != nil {
+		return
+	}
+	return
+}
+
+// stacks created before the firewalls were per cluster have worker-firewall and control-plane-firewall without parent,
+// shared by the clusters. The first cluster of the topology takes them over, the others get new ones
+func legacyFirewallAlias(ictx *infra, clusterName string, name string) pulumi.ResourceOption {
+	names := make([]string, 0, len(ictx.core.topology.Clusters))
+	for n := range ictx.core.topology.Clusters {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if len(names) == 0 || names[0] != clusterName {
+		return pulumi.Aliases(nil)
+	}
+	return pulumi.Aliases([]pulumi.Alias{{Name: pulumi.String(name), NoParent: pulumi.Bool(true)}})
+}
+
+// firewalls of the nodes of a cluster, they open the ports of its own CNI
+func setupFirewalls(ctx *pulumi.Context, ictx *infra, clusterName string, pulumik8sCluster *K8sCluster) (err error) {
+	ictx.workerFirewall, err = hcloud.NewFirewall(ctx, fmt.Sprintf("worker-firewall-%s", clusterName), &hcloud.FirewallArgs{
+		Rules: append(hcloud.FirewallRuleArray{
+			&hcloud.FirewallRuleArgs{
+				Description: pulumi.String("Kubelet API"),
+				Direction:   pulumi.String("in"),
+				Protocol:    pulumi.String("tcp"),
+				Port:        pulumi.String("10250"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+			// nodeports only from loadbalancer
+			&hcloud.FirewallRuleArgs{
+				Description: pulumi.String("worker-Nodeports"),
+				Direction:   pulumi.String("in"),
+				Protocol:    pulumi.String("tcp"),
+				Port:        pulumi.String("30000-32767"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+			// workers can only be ssh'ed from bastion host
+			&hcloud.FirewallRuleArgs{
+				Direction: pulumi.String("in"),
+				Protocol:  pulumi.String("tcp"),
+				Port:      pulumi.String("22"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+		}, cniFirewallRules(*ictx.cluster)...),
+	}, pulumi.Parent(pulumik8sCluster), legacyFirewallAlias(ictx, clusterName, "worker-firewall"))
+	if err != nil {
+		return
+	}
+	ictx.ctrlPlaneFirewall, err = hcloud.NewFirewall(ctx, fmt.Sprintf("control-plane-firewall-%s", clusterName), &hcloud.FirewallArgs{
+		Rules: append(hcloud.FirewallRuleArray{
+			&hcloud.FirewallRuleArgs{
+				Direction: pulumi.String("in"),
+				Protocol:  pulumi.String("tcp"),
+				Port:      pulumi.String("22"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+			// nodeports only from loadbalancer
+			&hcloud.FirewallRuleArgs{
+				Description: pulumi.String("Ncp-odeports"),
+				Direction:   pulumi.String("in"),
+				Protocol:    pulumi.String("tcp"),
+				Port:        pulumi.String("30000-32767"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+			&hcloud.FirewallRuleArgs{
+				Description: pulumi.String("Kubernetes API server"),
+				Direction:   pulumi.String("in"),
+				Protocol:    pulumi.String("tcp"),
+				Port:        pulumi.String("6443"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("0.0.0.0/0"),
+				},
+			},
+			&hcloud.FirewallRuleArgs{
+				Description: pulumi.String("etcd server client API"),
+				Direction:   pulumi.String("in"),
+				Protocol:    pulumi.String("tcp"),
+				Port:        pulumi.String("2379-2380"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+			&hcloud.FirewallRuleArgs{
+				Description: pulumi.String("Kubelet API"),
+				Direction:   pulumi.String("in"),
+				Protocol:    pulumi.String("tcp"),
+				Port:        pulumi.String("10250"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+			&hcloud.FirewallRuleArgs{
+				Description: pulumi.String("kube-scheduler"),
+				Direction:   pulumi.String("in"),
+				Protocol:    pulumi.String("tcp"),
+				Port:        pulumi.String("10259"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+			&hcloud.FirewallRuleArgs{
+				Description: pulumi.String("kube-controller-manager"),
+				Direction:   pulumi.String("in"),
+				Protocol:    pulumi.String("tcp"),
+				Port:        pulumi.String("10257"),
+				SourceIps: pulumi.StringArray{
+					pulumi.String("10.0.1.0/24"),
+				},
+			},
+		}, cniFirewallRules(*ictx.cluster)...),
+	}, pulumi.Parent(pulumik8sCluster), legacyFirewallAlias(ictx, clusterName, "control-plane-firewall"))
+	if err != nil {
+		return
+	}
+	return
+}
+
+func genInventoryFile(ctx *pulumi.Context, clusterInventory Inventory) {
+	renderedTemplate, parseErr := template.New("invtpl").Parse(string(inventoryTmpl))
+	if parseErr != nil {
+		ctx.Log.Error("Error parsing template file", nil)
+	}
+	var buff bytes.Buffer
+	if err := renderedTemplate.Execute(&buff, clusterInventory); err != nil {
+		ctx.Log.Error("Failed to render inventory template "+err.Error(), nil)
+	}
+
+	outFileLoc := fmt.Sprintf("/tmp/inventory-%s.ini", clusterInventory.ClusterName)
+
+	if err := os.WriteFile(outFileLoc, buff.Bytes(), 0655); err != nil {
+		ctx.Log.Error("Failed to write inventory files ", nil)
+	}
+
+	buff.Reset()
+	renderedTemplate, parseErr = template.New("invtpl").Parse(string(variablesTmpl))
+	if parseErr != nil {
+		ctx.Log.Error("Error parsing template file ", nil)
+	}
+	if err := renderedTemplate.Execute(&buff, clusterInventory); err != nil {
+		ctx.Log.Error("Failed to render inventory template "+err.Error(), nil)
+	}
+
+	outFileLoc = fmt.Sprintf("/tmp/variables-%s.yaml", clusterInventory.ClusterName)
+
+	if err := os.WriteFile(outFileLoc, buff.Bytes(), 0655); err != nil {
+		ctx.Log.Error("Failed to write inventory files "+err.Error(), nil)
+	}
+
+}
+
+// the secrets file is written straight to ./vars and is only readable by the owner
+func genSecretsFile(ctx *pulumi.Context, clusterInventory Inventory) {
+	dockerConfig := ""
+	if len(clusterInventory.RegistryCredentials) > 0 {
+		var err error
+		dockerConfig, err = dockerConfigJSON(clusterInventory.RegistryCredentials)
+		if err != nil {
+			ctx.Log.Error("Failed to render registry credentials "+err.Error(), nil)
+			return
+		}
+	}
+	autoscalerToken, autoscalerClusterCfg := "", ""
+	if clusterInventory.Autoscaler != nil {
+		var err error
+		autoscalerClusterCfg, err = autoscalerClusterConfig(clusterInventory)
+		if err != nil {
+			ctx.Log.Error("Failed to render autoscaler node configuration "+err.Error(), nil)
+			return
+		}
+		autoscalerToken = clusterInventory.Autoscaler.Token
+	}
+	secrets := struct {
+		PKI                  `yaml:",inline"`
+		BackupCredentials    `yaml:",inline"`
+		RegistryCredentials  map[string]RegistryCredentials `yaml:"registry_credentials,omitempty"`
+		DockerConfig         string                         `yaml:"registry_docker_config,omitempty"`
+		HcloudToken          string                         `yaml:"hcloud_token,omitempty"`
+		AutoscalerToken      string                         `yaml:"autoscaler_token,omitempty"`
+		AutoscalerClusterCfg string                         `yaml:"autoscaler_cluster_config,omitempty"`
+	}{*clusterInventory.Pki, clusterInventory.BackupCredentials, clusterInventory.RegistryCredentials, dockerConfig,
+		clusterInventory.HcloudToken, autoscalerToken, autoscalerClusterCfg}
+	out, err := yaml.Marshal(secrets)
+	if err != nil {
+		ctx.Log.Error("Failed to render secrets file "+err.Error(), nil)
+		return
+	}
+	outFileLoc := fmt.Sprintf("./vars/secrets-%s.yaml", clusterInventory.ClusterName)
+	if err := os.WriteFile(outFileLoc, out, 0600); err != nil {
+		ctx.Log.Error("Failed to write secrets file "+err.Error(), nil)
+	}
+}
+
+func NewClusterInfra(infracfg *InfraConfig, cluster *Cluster) *infra {
+	workerIps := make([]*Node, 0)
+	cpIps := make([]*Node, 0)
+
+	registries := clusterRegistries(*cluster)
+	insecureRegistries := make([]string, 0)
+	for host, r := range registries {
+		if r.Insecure {
+			insecureRegistries = append(insecureRegistries, host)
+		}
+	}
+	sort.Strings(insecureRegistries)
+	pullSecretNamespaces := cluster.Registries.PullSecretNamespaces
+	if len(pullSecretNamespaces) == 0 {
+		pullSecretNamespaces = []string{"default"}
+	}
+	bootstrap := cluster.Bootstrap
+	if bootstrap == "" {
+		bootstrap = bootstrapAnsible
+	}
+	ntp := cluster.Ntp
+	if ntp.MaxOffsetMs == 0 {
+		ntp.MaxOffsetMs = defaultNtpMaxOffset
+	}
+
+	chart := cniCatalog[cluster.Cni.Name]
+	cniVersion := cluster.Cni.Version
+	if cniVersion == "" {
+		cniVersion = chart.version
+	}
+	inv := &Inventory{Cni: cluster.Cni.Name,
+		CniDef:               cluster.Cni,
+		CniVersion:           cniVersion,
+		CniRepoName:          chart.repoName,
+		CniRepoURL:           chart.repoURL,
+		CniChart:             chart.chart,
+		CniNamespace:         chart.namespace,
+		Cilium:               cluster.Cilium,
+		PodSubnet:            clusterPodSubnet(*cluster),
+		Cri:                  cluster.Cri,
+		K8sversion:           cluster.KubernetesVersion,
+		User:                 infracfg.SSHUser,
+		WorkerIPs:            workerIps,
+		MasterIPs:            cpIps,
+		Bastion:              &Node{},
+		PrivateRegistry:      cluster.PrivateRegistry,
+		InsecureRegistries:   insecureRegistries,
+		Registries:           registries,
+		PullSecretNamespaces: pullSecretNamespaces,
+		AirGapped:            cluster.AirGapped,
+		Bootstrap:            bootstrap,
+		Ntp:                  ntp,
+		Addons:               cluster.Addons,
+		Releases:             cluster.Releases,
+		Manifests:            cluster.Manifests,
+		GitOps:               cluster.GitOps,
+		Worker:               cluster.Worker,
+		Backup:               cluster.Backup,
+		Kubeadm:              cluster.Kubeadm,
+		OIDC:                 cluster.OIDC,
+		Audit:                cluster.Audit,
+		Encryption:           cluster.Encryption,
+		MaxFailPercentage:    cluster.Provisioning.MaxFailPercentage}
+	if inv.Backup.Schedule == "" {
+		inv.Backup.Schedule = "0 2 * * *"
+	}
+	if inv.Backup.RetentionDays == 0 {
+		inv.Backup.RetentionDays = 7
+	}
+	if inv.Audit.MaxAge == 0 {
+		inv.Audit.MaxAge = 30
+	}
+	if inv.Audit.MaxBackup == 0 {
+		inv.Audit.MaxBackup = 10
+	}
+	if inv.Audit.MaxSize == 0 {
+		inv.Audit.MaxSize = 100
+	}
+	i := &infra{inventory: inv, cluster: cluster}
+	return i
+}
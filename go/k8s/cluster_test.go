@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+func TestReadTopology(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "topology.yaml")
+	if err := os.WriteFile(file, []byte("clusters:\n  c1:\n    cri: containerd\n    kubernetes_version: \"1.30\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	topology, err := ReadTopology(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topology.Clusters["c1"].Cri != "containerd" {
+		t.Errorf("topology %+v", topology)
+	}
+	if _, err := ReadTopology(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("a missing topology file is read")
+	}
+	if err := os.WriteFile(file, []byte("clusters: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadTopology(file); err == nil || !strings.Contains(err.Error(), file) {
+		t.Errorf("error %v, want the file name", err)
+	}
+}
+
+// the clusters are created from their args, the topology of the core only lists the other clusters on the network
+func TestNewK8sClusterArgs(t *testing.T) {
+	chdirVars(t)
+	m := newMocks()
+	flannel := Cluster{Cri: "containerd", KubernetesVersion: "1.30.2", Cni: CNIDef{Name: "flannel"}}
+	flannel.ControlPlane.NodeCount = 1
+	calico := flannel
+	calico.Cni = CNIDef{Name: "calico"}
+	infraCfg := &InfraConfig{WorkerFlavor: "cpx41", MasterFlavor: "cpx31", LbType: "lb11", Image: "ubuntu-22.04",
+		NetworkZone: "eu-central", DataCenter: "fsn1-dc14", SSHUser: "root"}
+	var again error
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		core, err := NewCore(ctx, &CoreArgs{Infra: infraCfg, Topology: &Topology{Clusters: map[string]Cluster{"a": flannel}},
+			Provisioner: NewFakeProvisioner()})
+		if err != nil {
+			return err
+		}
+		if _, err := NewK8sCluster(ctx, "a", &K8sClusterArgs{Cluster: flannel, Core: core}); err != nil {
+			return err
+		}
+		// not in the topology
+		if _, err := NewK8sCluster(ctx, "b", &K8sClusterArgs{Cluster: calico, Core: core}); err != nil {
+			return err
+		}
+		_, again = NewK8sCluster(ctx, "b", &K8sClusterArgs{Cluster: calico, Core: core})
+		return nil
+	}, pulumi.WithMocks("project", "stack", m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again == nil {
+		t.Error("a cluster is created twice on the same core")
+	}
+	// the firewalls of a cluster open the ports of its own CNI
+	for name, port := range map[string]string{"worker-firewall-a": "8472", "control-plane-firewall-b": "179"} {
+		fw := m.resource(name)
+		if fw == nil {
+			t.Fatalf("no %s", name)
+		}
+		ports := []string{}
+		for _, r := range fw["rules"].ArrayValue() {
+			ports = append(ports, r.ObjectValue()["port"].StringValue())
+		}
+		if !strings.Contains(strings.Join(ports, ","), port) {
+			t.Errorf("%s opens %v, want %s", name, ports, port)
+		}
+	}
+	if m.resource("worker-firewall") != nil || m.resource("control-plane-firewall") != nil {
+		t.Error("the core creates node firewalls")
+	}
+	// the cluster of the topology takes over the firewalls of the stacks created before they were per cluster
+	for name, want := range map[string]string{
+		"worker-firewall-a":        "name=worker-firewall noParent=true",
+		"control-plane-firewall-a": "name=control-plane-firewall noParent=true",
+		"worker-firewall-b":        "",
+		"control-plane-firewall-b": "",
+	} {
+		if aliases := strings.Join(m.aliases[name], ","); aliases != want {
+			t.Errorf("%s aliases %q, want %q", name, aliases, want)
+		}
+	}
+}
+
+func TestValidateClusterMesh(t *testing.T) {
+	mesh := Cluster{Cni: CNIDef{Name: "cilium"}, Cilium: &CiliumDef{ClusterMesh: &ClusterMeshDef{ID: 1}}}
+	if err := validateClusterMesh("a", mesh, meshClusters(&Topology{Clusters: map[string]Cluster{"a": mesh}})); err != nil {
+		t.Error(err)
+	}
+	if err := validateClusterMesh("a", mesh, []string{"b"}); err == nil {
+		t.Error("a mesh cluster outside the mesh clusters of the core is accepted")
+	}
+	if err := validateClusterMesh("a", Cluster{Cni: CNIDef{Name: "cilium"}}, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// the shared firewalls of an earlier stack are taken over by one cluster only, a URN cannot be aliased twice
+func TestLegacyFirewallAliases(t *testing.T) {
+	chdirVars(t)
+	m := newMocks()
+	c := Cluster{Cri: "containerd", KubernetesVersion: "1.30.2", Cni: CNIDef{Name: "flannel"}}
+	c.ControlPlane.NodeCount = 1
+	infraCfg := &InfraConfig{WorkerFlavor: "cpx41", MasterFlavor: "cpx31", LbType: "lb11", Image: "ubuntu-22.04",
+		NetworkZone: "eu-central", DataCenter: "fsn1-dc14", SSHUser: "root"}
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		core, err := NewCore(ctx, &CoreArgs{Infra: infraCfg, Topology: &Topology{Clusters: map[string]Cluster{"west": c, "east": c}},
+			Provisioner: NewFakeProvisioner()})
+		if err != nil {
+			return err
+		}
+		for _, name := range []string{"west", "east"} {
+			if _, err := NewK8sCluster(ctx, name, &K8sClusterArgs{Cluster: c, Core: core}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, pulumi.WithMocks("project", "stack", m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aliases := m.aliases["worker-firewall-east"]; len(aliases) != 1 {
+		t.Errorf("worker-firewall-east aliases %v", aliases)
+	}
+	if aliases := m.aliases["worker-firewall-west"]; len(aliases) != 0 {
+		t.Errorf("worker-firewall-west aliases %v", aliases)
+	}
+}
@@ -0,0 +1,247 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CNI of a cluster, either just the name or a mapping with version and helm values
+type CNIDef struct {
+	// flannel, cilium, calico or none
+	Name    string `yaml:"name"`
+	Version string `yaml:"version,omitempty"`
+	// helm values file, file name in ./vars
+	ValuesFile string                 `yaml:"values_file,omitempty"`
+	Values     map[string]interface{} `yaml:"values,omitempty"`
+}
+
+func (c *CNIDef) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		c.Name = name
+		return nil
+	}
+	type plain CNIDef
+	return unmarshal((*plain)(c))
+}
+
+// helm chart of a CNI
+type cniChart struct {
+	repoName  string
+	repoURL   string
+	chart     string
+	namespace string
+	version   string
+}
+
+var cniCatalog = map[string]cniChart{
+	"flannel": {repoName: "flannel", repoURL: "https://flannel-io.github.io/flannel/", chart: "flannel/flannel", namespace: "kube-flannel", version: "v0.25.4"},
+	"cilium":  {repoName: "cilium", repoURL: "https://helm.cilium.io/", chart: "cilium/cilium", namespace: "kube-system", version: "1.14.5"},
+	"calico":  {repoName: "projectcalico", repoURL: "https://docs.tigera.io/calico/charts", chart: "projectcalico/tigera-operator", namespace: "tigera-operator", version: "v3.27.3"},
+	"none":    {},
+}
+
+// firewall rule between the nodes of the private network
+type nodeRule struct {
+	description string
+	protocol    string
+	port        string
+}
+
+// helm values supplied by the user, the inline values win over the values file
+func cniUserValues(cni CNIDef) (map[interface{}]interface{}, error) {
+	values := make(map[interface{}]interface{})
+	if cni.ValuesFile != "" {
+		content, err := os.ReadFile(filepath.Join("./vars", cni.ValuesFile))
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			return nil, fmt.Errorf("cannot parse CNI values file %s: %w", cni.ValuesFile, err)
+		}
+	}
+	inline := make(map[interface{}]interface{})
+	for k, v := range cni.Values {
+		inline[k] = v
+	}
+	return mergeValues(values, inline), nil
+}
+
+// default helm values of the CNI for this cluster
+func cniDefaultValues(inv Inventory) map[interface{}]interface{} {
+	cpEndpoint, _ := controlPlaneEndpoints(inv)
+	switch inv.Cni {
+	case "flannel":
+		return map[interface{}]interface{}{
+			"podCidr": inv.PodSubnet,
+		}
+	case "cilium":
+		return mergeValues(map[interface{}]interface{}{
+			"kubeProxyReplacement": true,
+			"k8sServiceHost":       cpEndpoint,
+			"k8sServicePort":       6443,
+			"ipam": map[interface{}]interface{}{
+				"operator": map[interface{}]interface{}{
+					"clusterPoolIPv4PodCIDRList": []string{inv.PodSubnet},
+				},
+			},
+		}, ciliumValues(inv))
+	case "calico":
+		return map[interface{}]interface{}{
+			"installation": map[interface{}]interface{}{
+				"calicoNetwork": map[interface{}]interface{}{
+					"ipPools": []interface{}{map[interface{}]interface{}{
+						"cidr":          inv.PodSubnet,
+						"encapsulation": "VXLANCrossSubnet",
+						"natOutgoing":   "Enabled",
+						"blockSize":     26,
+					}},
+				},
+			},
+		}
+	}
+	return map[interface{}]interface{}{}
+}
+
+// look up a nested value such as flannel.backend
+func lookupValue(values map[interface{}]interface{}, path string) (interface{}, bool) {
+	var current interface{} = values
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(map[interface{}]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func valueString(values map[interface{}]interface{}, path string, def string) string {
+	if v, ok := lookupValue(values, path); ok {
+		return fmt.Sprint(v)
+	}
+	return def
+}
+
+// cilium replaces kube-proxy unless told otherwise
+func skipKubeProxy(cni CNIDef) bool {
+	if cni.Name != "cilium" {
+		return false
+	}
+	values, err := cniUserValues(cni)
+	if err != nil {
+		return true
+	}
+	switch valueString(values, "kubeProxyReplacement", "true") {
+	case "true", "strict":
+		return true
+	}
+	return false
+}
+
+// node to node ports the CNI needs with the given helm values and cilium block
+func cniNodeRules(cni CNIDef, cilium *CiliumDef) ([]nodeRule, error) {
+	values, err := cniUserValues(cni)
+	if err != nil {
+		return nil, err
+	}
+	switch cni.Name {
+	case "flannel":
+		switch backend := valueString(values, "flannel.backend", "vxlan"); backend {
+		case "vxlan":
+			return []nodeRule{{"flannel VXLAN", "udp", "8472"}}, nil
+		case "wireguard":
+			return []nodeRule{{"flannel WireGuard", "udp", "51820-51821"}}, nil
+		case "host-gw":
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("unsupported flannel backend %s, must be one of vxlan, wireguard, host-gw", backend)
+		}
+	case "cilium":
+		rules := []nodeRule{{"cilium health", "tcp", "4240"}, {"cilium hubble", "tcp", "4244"}}
+		if !nativeRouting(cilium) && valueString(values, "routingMode", "tunnel") == "tunnel" {
+			switch protocol := valueString(values, "tunnelProtocol", "vxlan"); protocol {
+			case "vxlan":
+				rules = append(rules, nodeRule{"cilium VXLAN", "udp", "8472"})
+			case "geneve":
+				rules = append(rules, nodeRule{"cilium Geneve", "udp", "6081"})
+			default:
+				return nil, fmt.Errorf("unsupported cilium tunnelProtocol %s, must be one of vxlan, geneve", protocol)
+			}
+		}
+		return append(rules, ciliumNodeRules(cilium)...), nil
+	case "calico":
+		return []nodeRule{{"calico BGP", "tcp", "179"}, {"calico VXLAN", "udp", "4789"}, {"calico typha", "tcp", "5473"}}, nil
+	}
+	return nil, nil
+}
+
+func validateCNI(cni CNIDef, kadm KubeadmDef) error {
+	if _, ok := cniCatalog[cni.Name]; !ok {
+		return fmt.Errorf("unknown cni %q, must be one of flannel, cilium, calico, none", cni.Name)
+	}
+	if cni.Name == "none" && (cni.Version != "" || cni.ValuesFile != "" || len(cni.Values) > 0) {
+		return fmt.Errorf("cni none does not take a version or values")
+	}
+	if _, err := cniNodeRules(cni, nil); err != nil {
+		return err
+	}
+	if skipKubeProxy(cni) && len(kadm.KubeProxy.Config) > 0 {
+		return fmt.Errorf("kubeadm.kube_proxy is set but kube-proxy is not installed, cilium replaces it (set kubeProxyReplacement: false in the cilium values to keep kube-proxy)")
+	}
+	return nil
+}
+
+// firewall rules for the CNI of a cluster, between the nodes of the private network
+func cniFirewallRules(cluster Cluster) hcloud.FirewallRuleArray {
+	rules := hcloud.FirewallRuleArray{}
+	nodeRules, _ := cniNodeRules(cluster.Cni, cluster.Cilium)
+	for _, r := range nodeRules {
+		rules = append(rules, &hcloud.FirewallRuleArgs{
+			Description: pulumi.String(r.description),
+			Direction:   pulumi.String("in"),
+			Protocol:    pulumi.String(r.protocol),
+			Port:        pulumi.String(r.port),
+			SourceIps: pulumi.StringArray{
+				pulumi.String("10.0.1.0/24"),
+			},
+		})
+	}
+	return rules
+}
+
+// helm values of the CNI, the user values are merged over the defaults
+func cniValues(inv Inventory) ([]byte, error) {
+	values, err := cniUserValues(inv.CniDef)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(mergeValues(cniDefaultValues(inv), values))
+}
+
+// helm values of the CNI, written to ./vars
+func genCNIFiles(ctx *pulumi.Context, clusterInventory Inventory) {
+	if clusterInventory.Cni == "none" {
+		return
+	}
+	out, err := cniValues(clusterInventory)
+	if err != nil {
+		ctx.Log.Error("Failed to render CNI values "+err.Error(), nil)
+		return
+	}
+	outFileLoc := fmt.Sprintf("./vars/cni-values-%s.yaml", clusterInventory.ClusterName)
+	// holds the cilium CA key with cluster mesh
+	if err := os.WriteFile(outFileLoc, out, 0600); err != nil {
+		ctx.Log.Error("Failed to write CNI values "+err.Error(), nil)
+	}
+}
@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// type tokens of the components served by the component provider
+const (
+	NetworkType = "hcloudkubeadm:index:Network"
+	BastionType = "hcloudkubeadm:index:Bastion"
+	ClusterType = "hcloudkubeadm:index:Cluster"
+)
+
+// network, firewalls, ssh key and cluster mesh CA shared by the clusters, created with NewNetwork
+type Network struct {
+	pulumi.ResourceState
+
+	// id of the private network
+	NetworkID pulumi.IDOutput `pulumi:"networkId"`
+	// private key of the ssh user of the servers
+	PrivateKey pulumi.StringOutput `pulumi:"privateKey"`
+
+	core *Core
+}
+
+// inputs of the network
+type NetworkArgs struct {
+	Infra *InfraConfig
+	// clusters in the cilium cluster mesh, the network creates the mesh CA for them and the mesh is connected once they are created
+	ClusterMesh []string
+}
+
+// bastion and NAT gateway of a network, created with NewBastion
+type Bastion struct {
+	pulumi.ResourceState
+
+	// public IP of the bastion
+	PublicIP pulumi.StringOutput `pulumi:"publicIp"`
+
+	core *Core
+}
+
+// inputs of the bastion
+type BastionArgs struct {
+	Network *Network
+	// installs kubernetes on the servers, defaults to the ansible playbooks
+	Provisioner Provisioner
+}
+
+// create the network shared by the clusters as a component, NewCore does the same for the clusters of a topology without components
+func NewNetwork(ctx *pulumi.Context, name string, args *NetworkArgs, opts ...pulumi.ResourceOption) (*Network, error) {
+	if args == nil || args.Infra == nil {
+		return nil, errors.New("the network needs the infrastructure configuration")
+	}
+	mesh := append([]string(nil), args.ClusterMesh...)
+	sort.Strings(mesh)
+	// the clusters are checked against the ones created before them
+	network := &Network{core: &Core{infraCfg: args.Infra, topology: &Topology{}, mesh: mesh, clusters: make(map[string]Cluster)}}
+	err := ctx.RegisterComponentResource(NetworkType, name, network, opts...)
+	if err != nil {
+		return nil, err
+	}
+	parent := pulumi.Parent(network)
+	err = setupKeys(ctx, network.core, parent)
+	if err != nil {
+		return nil, err
+	}
+	err = setupNetwork(ctx, args.Infra, network.core, parent)
+	if err != nil {
+		return nil, err
+	}
+	network.core.meshCA, err = setupClusterMeshCA(ctx, network.core.mesh, parent)
+	if err != nil {
+		return nil, err
+	}
+	network.NetworkID = network.core.network.ID()
+	network.PrivateKey = pulumi.ToSecret(network.core.privateKey.PrivateKeyOpenssh).(pulumi.StringOutput)
+	err = ctx.RegisterResourceOutputs(network, pulumi.Map{
+		"networkId":  network.NetworkID,
+		"privateKey": network.PrivateKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return network, nil
+}
+
+// create the bastion of a network as a component, the clusters are created behind it
+func NewBastion(ctx *pulumi.Context, name string, args *BastionArgs, opts ...pulumi.ResourceOption) (*Bastion, error) {
+	if args == nil || args.Network == nil {
+		return nil, errors.New("the bastion needs a network, create it with NewNetwork")
+	}
+	core := args.Network.core
+	// the NAT route of the network points to a single bastion
+	if core.jumpServer != nil {
+		return nil, errors.New("the network already has a bastion")
+	}
+	core.provisioner = args.Provisioner
+	if core.provisioner == nil {
+		core.provisioner = NewAnsibleProvisioner()
+	}
+	bastion := &Bastion{core: core}
+	err := ctx.RegisterComponentResource(BastionType, name, bastion, opts...)
+	if err != nil {
+		return nil, err
+	}
+	parent := pulumi.Parent(bastion)
+	err = setupNATAndBastionHost(ctx, core.infraCfg, core, parent)
+	if err != nil {
+		return nil, err
+	}
+	core.bastionSetup, err = core.provisioner.PrepareBastion(ctx, core.infraCfg, core, parent)
+	if err != nil {
+		return nil, err
+	}
+	bastion.PublicIP = core.jumpServer.Ipv4Address
+	err = ctx.RegisterResourceOutputs(bastion, pulumi.Map{
+		"publicIp": bastion.PublicIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bastion, nil
+}
+
+// network and bastion to create the clusters on, see K8sClusterArgs
+func (bastion *Bastion) Core() *Core {
+	return bastion.core
+}
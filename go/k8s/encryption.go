@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"gopkg.in/yaml.v2"
+)
+
+const encryptionConfigDir = "/etc/kubernetes/encryption"
+
+type EncryptionDef struct {
+	Enabled bool `yaml:"enabled"`
+	// aescbc, aesgcm or secretbox (defaults to aescbc)
+	Provider string `yaml:"provider,omitempty"`
+	// resources to encrypt (defaults to secrets)
+	Resources []string `yaml:"resources,omitempty"`
+}
+
+func validateEncryption(enc EncryptionDef) error {
+	switch enc.Provider {
+	case "", "aescbc", "aesgcm", "secretbox":
+		return nil
+	}
+	return fmt.Errorf("unknown encryption provider %s, must be one of aescbc, aesgcm, secretbox", enc.Provider)
+}
+
+// generate the etcd encryption key, kept as a secret in the pulumi state
+func setupEncryption(ctx *pulumi.Context, ictx *infra, clusterName string, pulumik8sCluster *K8sCluster) error {
+	if !ictx.cluster.Encryption.Enabled {
+		return nil
+	}
+	key, err := random.NewRandomBytes(ctx, fmt.Sprintf("encryption-key-%s", clusterName), &random.RandomBytesArgs{
+		Length: pulumi.Int(32),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return err
+	}
+	ek := key.Base64.ApplyT(func(k string) []string {
+		ictx.inventory.EncryptionKey = k
+		return make([]string, 0)
+	})
+	ictx.waitFor = append(ictx.waitFor, ek)
+	return nil
+}
+
+func encryptionConfig(enc EncryptionDef, key string) ([]byte, error) {
+	provider := enc.Provider
+	if provider == "" {
+		provider = "aescbc"
+	}
+	resources := enc.Resources
+	if len(resources) == 0 {
+		resources = []string{"secrets"}
+	}
+	config := map[string]interface{}{
+		"apiVersion": "apiserver.config.k8s.io/v1",
+		"kind":       "EncryptionConfiguration",
+		"resources": []interface{}{map[string]interface{}{
+			"resources": resources,
+			"providers": []interface{}{
+				map[string]interface{}{
+					provider: map[string]interface{}{
+						"keys": []interface{}{map[string]interface{}{
+							"name":   "key1",
+							"secret": key,
+						}},
+					},
+				},
+				map[string]interface{}{"identity": map[string]interface{}{}},
+			},
+		}},
+	}
+	return yaml.Marshal(config)
+}
+
+func encryptionAPIServerArgs(enc EncryptionDef) map[string]string {
+	args := make(map[string]string)
+	if enc.Enabled {
+		args["encryption-provider-config"] = encryptionConfigDir + "/config.yaml"
+	}
+	return args
+}
+
+func encryptionAPIServerVolumes(enc EncryptionDef) []kubeadmVolume {
+	if !enc.Enabled {
+		return nil
+	}
+	return []kubeadmVolume{
+		{Name: "encryption-config", HostPath: encryptionConfigDir, MountPath: encryptionConfigDir, ReadOnly: true, PathType: "DirectoryOrCreate"},
+	}
+}
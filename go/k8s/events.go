@@ -0,0 +1,220 @@
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/internals"
+)
+
+// event of a playbook run, written by the pulumi_events callback plugin
+type ansibleEvent struct {
+	Event   string               `json:"event"`
+	Time    float64              `json:"time"`
+	Play    string               `json:"play"`
+	Task    string               `json:"task"`
+	Host    string               `json:"host"`
+	Changed bool                 `json:"changed"`
+	Msg     string               `json:"msg"`
+	Hosts   map[string]hostStats `json:"hosts"`
+}
+
+// task results of a host at the end of a playbook run
+type hostStats struct {
+	Ok          int `json:"ok"`
+	Changed     int `json:"changed"`
+	Failed      int `json:"failed"`
+	Unreachable int `json:"unreachable"`
+	Skipped     int `json:"skipped"`
+	Rescued     int `json:"rescued"`
+}
+
+// polling interval of the events file
+const eventsPoll = 2 * time.Second
+
+func eventsFile(clusterName string, step string) string {
+	return fmt.Sprintf("./vars/events-%s-%s.jsonl", clusterName, step)
+}
+
+// enable the callback plugin of a playbook run
+func eventsEnv(env pulumi.StringMap, path string) {
+	env["ANSIBLE_CALLBACKS_ENABLED"] = pulumi.String("pulumi_events")
+	env["PULUMI_EVENTS_FILE"] = pulumi.String(path)
+}
+
+// logs the events of a playbook run against a resource while it runs
+type eventStream struct {
+	ctx      *pulumi.Context
+	resource pulumi.Resource
+	prefix   string
+	path     string
+	// events of earlier runs are not logged again
+	since time.Time
+	// first line of the file and end of the lines logged
+	head     []byte
+	offset   int64
+	stop     chan struct{}
+	stopOnce sync.Once
+	finished chan struct{}
+}
+
+func streamEvents(ctx *pulumi.Context, resource pulumi.Resource, prefix string, path string) *eventStream {
+	s := &eventStream{
+		ctx:      ctx,
+		resource: resource,
+		prefix:   prefix,
+		path:     path,
+		since:    time.Now(),
+		stop:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// stop following the file once the command is done, and log what is left
+func (s *eventStream) wait() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.finished
+}
+
+// stop the stream once o is resolved or rejected, the outputs of a failed command are never resolved
+func (s *eventStream) stopWith(o pulumi.Output) {
+	go func() {
+		_, _ = internals.UnsafeAwaitOutput(s.ctx.Context(), o)
+		s.wait()
+	}()
+}
+
+func (s *eventStream) run() {
+	defer close(s.finished)
+	ticker := time.NewTicker(eventsPoll)
+	defer ticker.Stop()
+	for {
+		s.logEvents(s.read())
+		select {
+		case <-s.stop:
+			s.logEvents(s.read())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// events of the complete lines written since the last read. The plugin creates a new file for every run,
+// which starts with a playbook event of another time
+func (s *eventStream) read() []ansibleEvent {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil
+	}
+	first := bytes.IndexByte(content, '\n')
+	if first < 0 {
+		return nil
+	}
+	if !bytes.Equal(content[:first], s.head) || int64(len(content)) < s.offset {
+		s.head = append([]byte(nil), content[:first]...)
+		s.offset = 0
+	}
+	// a line without newline is still being written
+	end := bytes.LastIndexByte(content, '\n') + 1
+	scanner := bufio.NewScanner(bytes.NewReader(content[s.offset:end]))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var events []ansibleEvent
+	for scanner.Scan() {
+		var e ansibleEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if e.Time < float64(s.since.UnixNano())/1e9 {
+			continue
+		}
+		events = append(events, e)
+	}
+	s.offset = int64(end)
+	return events
+}
+
+func (s *eventStream) logEvents(events []ansibleEvent) {
+	for _, e := range events {
+		s.log(e)
+	}
+}
+
+func (s *eventStream) log(e ansibleEvent) {
+	args := &pulumi.LogArgs{Resource: s.resource}
+	switch e.Event {
+	case "play":
+		_ = s.ctx.Log.Info(fmt.Sprintf("%s: play %s", s.prefix, e.Play), args)
+	case "ok":
+		status := "ok"
+		if e.Changed {
+			status = "changed"
+		}
+		_ = s.ctx.Log.Info(fmt.Sprintf("%s: [%s] %s: %s %s", s.prefix, e.Play, e.Host, status, e.Task), args)
+	case "ignored":
+		_ = s.ctx.Log.Info(fmt.Sprintf("%s: [%s] %s: failed (ignored) %s", s.prefix, e.Play, e.Host, e.Task), args)
+	case "failed", "unreachable":
+		_ = s.ctx.Log.Warn(fmt.Sprintf("%s: [%s] %s: %s %s: %s", s.prefix, e.Play, e.Host, e.Event, e.Task, e.Msg), args)
+	case "stats":
+		hosts := make([]string, 0, len(e.Hosts))
+		for host := range e.Hosts {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		for _, host := range hosts {
+			h := e.Hosts[host]
+			_ = s.ctx.Log.Info(fmt.Sprintf("%s: %s ok=%d changed=%d failed=%d unreachable=%d skipped=%d rescued=%d", s.prefix, host, h.Ok, h.Changed, h.Failed, h.Unreachable, h.Skipped, h.Rescued), args)
+		}
+	}
+}
+
+// changed, failed and unreachable hosts of the last run of a playbook, and the hosts it went on without
+func readEventSummary(path string) (map[string]interface{}, error) {
+	summary := map[string]interface{}{"changed": []interface{}{}, "failed": []interface{}{}, "unreachable": []interface{}{}, "rescued": []interface{}{}}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return summary, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stats *ansibleEvent
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		var e ansibleEvent
+		if json.Unmarshal(line, &e) == nil && e.Event == "stats" {
+			stats = &e
+		}
+	}
+	if stats == nil {
+		return summary, nil
+	}
+	hosts := make([]string, 0, len(stats.Hosts))
+	for host := range stats.Hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		h := stats.Hosts[host]
+		if h.Changed > 0 {
+			summary["changed"] = append(summary["changed"].([]interface{}), host)
+		}
+		if h.Failed > 0 {
+			summary["failed"] = append(summary["failed"].([]interface{}), host)
+		}
+		if h.Unreachable > 0 {
+			summary["unreachable"] = append(summary["unreachable"].([]interface{}), host)
+		}
+		if h.Rescued > 0 {
+			summary["rescued"] = append(summary["rescued"].([]interface{}), host)
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,158 @@
+package k8s
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// JSON line of an event as the pulumi_events plugin writes it
+func eventLine(t *testing.T, e ansibleEvent) string {
+	t.Helper()
+	out, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out) + "\n"
+}
+
+func writeEvents(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadEventSummary(t *testing.T) {
+	empty := map[string]interface{}{"changed": []interface{}{}, "failed": []interface{}{}, "unreachable": []interface{}{}, "rescued": []interface{}{}}
+	firstRun := ansibleEvent{Event: "stats", Hosts: map[string]hostStats{"10.0.1.3": {Ok: 4, Failed: 1}, "10.0.1.2": {Ok: 5, Unreachable: 1}}}
+	lastRun := ansibleEvent{Event: "stats", Hosts: map[string]hostStats{
+		"10.0.1.4": {Ok: 5, Changed: 2, Rescued: 1},
+		"10.0.1.3": {Ok: 5, Changed: 1},
+		"10.0.1.2": {Ok: 5},
+	}}
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    map[string]interface{}
+	}{
+		{name: "no file", want: empty},
+		{name: "no stats", content: eventLine(t, ansibleEvent{Event: "play", Play: "install"}) + eventLine(t, ansibleEvent{Event: "ok", Host: "10.0.1.2", Changed: true}), want: empty},
+		{name: "failed run", content: eventLine(t, ansibleEvent{Event: "play"}) + eventLine(t, firstRun), want: map[string]interface{}{
+			"changed": []interface{}{}, "failed": []interface{}{"10.0.1.3"}, "unreachable": []interface{}{"10.0.1.2"}, "rescued": []interface{}{},
+		}},
+		// the hosts are sorted, the stats of the last run win
+		{name: "last stats", content: eventLine(t, firstRun) + eventLine(t, lastRun), want: map[string]interface{}{
+			"changed": []interface{}{"10.0.1.3", "10.0.1.4"}, "failed": []interface{}{}, "unreachable": []interface{}{}, "rescued": []interface{}{"10.0.1.4"},
+		}},
+		// the line of a run killed while writing is not valid JSON
+		{name: "partial trailing line", content: eventLine(t, firstRun) + `{"event": "stats", "hosts": {"10.0.1.2": {"fail`, want: map[string]interface{}{
+			"changed": []interface{}{}, "failed": []interface{}{"10.0.1.3"}, "unreachable": []interface{}{"10.0.1.2"}, "rescued": []interface{}{},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "events.jsonl")
+			if tc.content != "" {
+				writeEvents(t, path, tc.content)
+			}
+			summary, err := readEventSummary(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(summary, tc.want) {
+				t.Errorf("summary %v, want %v", summary, tc.want)
+			}
+		})
+	}
+}
+
+// tasks of the events, in order
+func eventTasks(events []ansibleEvent) []string {
+	tasks := []string{}
+	for _, e := range events {
+		tasks = append(tasks, e.Task)
+	}
+	return tasks
+}
+
+func TestEventStreamRead(t *testing.T) {
+	since := time.Now()
+	at := func(d time.Duration) float64 {
+		return float64(since.Add(d).UnixNano()) / 1e9
+	}
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	s := &eventStream{path: path, since: since}
+	if events := s.read(); events != nil {
+		t.Errorf("events %v without a file", events)
+	}
+
+	// the events of the run before the stream started are left out
+	oldRun := eventLine(t, ansibleEvent{Event: "play", Time: at(-time.Hour)}) + eventLine(t, ansibleEvent{Event: "ok", Task: "old", Time: at(-time.Hour)})
+	writeEvents(t, path, oldRun)
+	if tasks := eventTasks(s.read()); len(tasks) != 0 {
+		t.Errorf("events of an earlier run %v", tasks)
+	}
+
+	// a new run recreates the file, it is read from the start
+	run := eventLine(t, ansibleEvent{Event: "play", Time: at(time.Second)}) + eventLine(t, ansibleEvent{Event: "ok", Task: "first", Time: at(2 * time.Second)})
+	writeEvents(t, path, run)
+	if tasks := eventTasks(s.read()); !reflect.DeepEqual(tasks, []string{"", "first"}) {
+		t.Errorf("tasks %v of the new run", tasks)
+	}
+
+	// a line is logged once it is complete
+	second := eventLine(t, ansibleEvent{Event: "ok", Task: "second", Time: at(3 * time.Second)})
+	writeEvents(t, path, run+second[:10])
+	if tasks := eventTasks(s.read()); len(tasks) != 0 {
+		t.Errorf("tasks %v of a partial line", tasks)
+	}
+	writeEvents(t, path, run+second)
+	if tasks := eventTasks(s.read()); !reflect.DeepEqual(tasks, []string{"second"}) {
+		t.Errorf("tasks %v, want the completed line", tasks)
+	}
+	if tasks := eventTasks(s.read()); len(tasks) != 0 {
+		t.Errorf("tasks %v read twice", tasks)
+	}
+
+	// the retry of a failed run starts a file of the same size with another first line
+	retry := eventLine(t, ansibleEvent{Event: "play", Time: at(5 * time.Second)}) + eventLine(t, ansibleEvent{Event: "ok", Task: "retry", Time: at(6 * time.Second)})
+	writeEvents(t, path, retry)
+	if tasks := eventTasks(s.read()); !reflect.DeepEqual(tasks, []string{"", "retry"}) {
+		t.Errorf("tasks %v of the retry", tasks)
+	}
+}
+
+// the stream follows the file until the command is done, whether it succeeds or fails
+func TestEventStreamStopsWithCommand(t *testing.T) {
+	for _, failure := range []error{nil, errors.New("playbook failed")} {
+		m := newMocks()
+		m.failures = map[string]error{"ansible-k8s-join-c1": failure}
+		var stream *eventStream
+		err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+			cmd, err := local.NewCommand(ctx, "ansible-k8s-join-c1", &local.CommandArgs{Create: pulumi.String("ansible-playbook")})
+			if err != nil {
+				return err
+			}
+			stream = streamEvents(ctx, nil, "c1 join", filepath.Join(t.TempDir(), "events.jsonl"))
+			stream.stopWith(cmd.Stdout)
+			return nil
+		}, pulumi.WithMocks("project", "stack", m))
+		if (err != nil) != (failure != nil) {
+			t.Fatalf("err = %v, want %v", err, failure)
+		}
+		select {
+		case <-stream.finished:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("the stream still runs after the command failed with %v", failure)
+		}
+		// the summary waits on the stream too
+		stream.wait()
+	}
+}
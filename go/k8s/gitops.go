@@ -0,0 +1,253 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// name of the manifests step creating the root sync object
+const gitopsRoot = "gitops"
+
+// GitOps engine bootstrapped after the add-ons
+type GitOpsDef struct {
+	// flux or argocd
+	Engine string `yaml:"engine"`
+	// ssh or https URL of the repository
+	Repo string `yaml:"repo"`
+	// defaults to main
+	Branch string `yaml:"branch,omitempty"`
+	// directory of the root sync object in the repository, defaults to the repository root
+	Path string `yaml:"path,omitempty"`
+	// chart version of the engine, defaults to the latest
+	Version string `yaml:"version,omitempty"`
+}
+
+// deploy key of the repository, read from the `gitops` pulumi secret config
+type GitOpsCredentials struct {
+	DeployKey  string `json:"deployKey"`
+	KnownHosts string `json:"knownHosts"`
+}
+
+var gitopsCatalog = map[string]addonChart{
+	"flux": {repoName: "fluxcd-community", repoURL: "https://fluxcd-community.github.io/helm-charts", chart: "flux2", namespace: "flux-system",
+		values: func() map[interface{}]interface{} {
+			return map[interface{}]interface{}{}
+		}},
+	"argocd": {repoName: "argo", repoURL: "https://argoproj.github.io/argo-helm", chart: "argo-cd", namespace: "argocd",
+		values: func() map[interface{}]interface{} {
+			return map[interface{}]interface{}{}
+		}},
+}
+
+func (g GitOpsDef) branch() string {
+	if g.Branch != "" {
+		return g.Branch
+	}
+	return "main"
+}
+
+func (g GitOpsDef) path() string {
+	if g.Path != "" {
+		return g.Path
+	}
+	return "."
+}
+
+// any URL but http, https and file is ssh and needs the deploy key
+func (g GitOpsDef) ssh() bool {
+	for _, scheme := range []string{"https://", "http://", "file://"} {
+		if strings.HasPrefix(g.Repo, scheme) {
+			return false
+		}
+	}
+	return true
+}
+
+// flux only takes ssh:// URLs, argo cd also the scp-like git@host:path form
+func (g GitOpsDef) sshURL() string {
+	if strings.Contains(g.Repo, "://") {
+		return g.Repo
+	}
+	hostPath := strings.SplitN(g.Repo, ":", 2)
+	if len(hostPath) != 2 {
+		return g.Repo
+	}
+	return "ssh://" + hostPath[0] + "/" + strings.TrimPrefix(hostPath[1], "/")
+}
+
+func validateGitOps(clusterName string, cluster Cluster) error {
+	g := cluster.GitOps
+	if g == nil {
+		return nil
+	}
+	if _, ok := gitopsCatalog[g.Engine]; !ok {
+		return fmt.Errorf("gitops.engine of cluster %s must be flux or argocd", clusterName)
+	}
+	if g.Repo == "" {
+		return fmt.Errorf("gitops.repo of cluster %s is not set", clusterName)
+	}
+	if cluster.AirGapped {
+		return fmt.Errorf("air_gapped cluster %s does not support gitops, the %s chart is not in the bundle", clusterName, g.Engine)
+	}
+	return nil
+}
+
+// look up the deploy key of the cluster repository
+func setupGitOps(infraCfg *InfraConfig, ictx *infra, clusterName string) error {
+	g := ictx.cluster.GitOps
+	creds, ok := infraCfg.GitOps[clusterName]
+	if g == nil {
+		if ok {
+			return fmt.Errorf("the pulumi config has a gitops deploy key for cluster %s which has no gitops block", clusterName)
+		}
+		return nil
+	}
+	if g.ssh() && creds.DeployKey == "" {
+		return fmt.Errorf("gitops.repo of cluster %s is an ssh URL, set its key with `pulumi config set --secret --path gitops.%s.deployKey`", clusterName, clusterName)
+	}
+	if g.ssh() && g.Engine == "flux" && creds.KnownHosts == "" {
+		return fmt.Errorf("flux needs the ssh host keys of the repository in the pulumi config gitops.%s.knownHosts", clusterName)
+	}
+	ictx.inventory.GitOpsCredentials = creds
+	return nil
+}
+
+// engine release and the root sync object, as built-in install steps
+func gitopsReleases(g *GitOpsDef) []string {
+	if g == nil {
+		return nil
+	}
+	return []string{g.Engine}
+}
+
+func gitopsManifests(g *GitOpsDef) []ManifestDef {
+	if g == nil {
+		return nil
+	}
+	return []ManifestDef{{Name: gitopsRoot, Needs: []string{g.Engine}}}
+}
+
+// helmfile release of the engine, it waits for the CRDs the root sync object needs
+func gitopsRelease(dir string, inv Inventory) (helmfileRepository, helmfileRelease, error) {
+	g := inv.GitOps
+	chart := gitopsCatalog[g.Engine]
+	values := chart.values()
+	if g.Engine == "argocd" && inv.GitOpsCredentials.KnownHosts != "" {
+		values["configs"] = map[interface{}]interface{}{
+			"ssh": map[interface{}]interface{}{"extraHosts": inv.GitOpsCredentials.KnownHosts},
+		}
+	}
+	out, err := yaml.Marshal(values)
+	if err != nil {
+		return helmfileRepository{}, helmfileRelease{}, err
+	}
+	valuesFile := g.Engine + "-values.yaml"
+	if err := os.WriteFile(filepath.Join(dir, valuesFile), out, 0644); err != nil {
+		return helmfileRepository{}, helmfileRelease{}, err
+	}
+	return helmfileRepository{Name: chart.repoName, URL: chart.repoURL}, helmfileRelease{
+		Name:      g.Engine,
+		Namespace: chart.namespace,
+		Chart:     chart.repoName + "/" + chart.chart,
+		Version:   g.Version,
+		Values:    []string{"/tmp/addons/" + valuesFile},
+		Wait:      true,
+	}, nil
+}
+
+func fluxObjects(g GitOpsDef, creds GitOpsCredentials) []interface{} {
+	meta := map[string]interface{}{"name": "flux-system", "namespace": "flux-system"}
+	source := map[string]interface{}{
+		"interval": "1m0s",
+		"url":      g.Repo,
+		"ref":      map[string]interface{}{"branch": g.branch()},
+	}
+	var objects []interface{}
+	if g.ssh() {
+		source["url"] = g.sshURL()
+		source["secretRef"] = map[string]interface{}{"name": "flux-system"}
+		objects = append(objects, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   meta,
+			"type":       "Opaque",
+			"stringData": map[string]interface{}{"identity": creds.DeployKey, "known_hosts": creds.KnownHosts},
+		})
+	}
+	return append(objects,
+		map[string]interface{}{
+			"apiVersion": "source.toolkit.fluxcd.io/v1",
+			"kind":       "GitRepository",
+			"metadata":   meta,
+			"spec":       source,
+		},
+		map[string]interface{}{
+			"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+			"kind":       "Kustomization",
+			"metadata":   meta,
+			"spec": map[string]interface{}{
+				"interval":  "10m0s",
+				"path":      g.path(),
+				"prune":     true,
+				"sourceRef": map[string]interface{}{"kind": "GitRepository", "name": "flux-system"},
+			},
+		})
+}
+
+func argocdObjects(g GitOpsDef, creds GitOpsCredentials) []interface{} {
+	var objects []interface{}
+	if g.ssh() {
+		objects = append(objects, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      "gitops-repository",
+				"namespace": "argocd",
+				"labels":    map[string]interface{}{"argocd.argoproj.io/secret-type": "repository"},
+			},
+			"stringData": map[string]interface{}{"type": "git", "url": g.Repo, "sshPrivateKey": creds.DeployKey},
+		})
+	}
+	return append(objects, map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata":   map[string]interface{}{"name": "root", "namespace": "argocd"},
+		"spec": map[string]interface{}{
+			"project": "default",
+			"source": map[string]interface{}{
+				"repoURL":        g.Repo,
+				"targetRevision": g.branch(),
+				"path":           g.path(),
+			},
+			"destination": map[string]interface{}{"server": "https://kubernetes.default.svc", "namespace": "argocd"},
+			"syncPolicy": map[string]interface{}{
+				"automated": map[string]interface{}{"prune": true, "selfHeal": true},
+			},
+		},
+	})
+}
+
+// root sync object and repository secret in <dir>/manifests/gitops, it holds the deploy key
+func writeGitOpsManifests(dir string, inv Inventory) error {
+	objects := fluxObjects(*inv.GitOps, inv.GitOpsCredentials)
+	if inv.GitOps.Engine == "argocd" {
+		objects = argocdObjects(*inv.GitOps, inv.GitOpsCredentials)
+	}
+	docs := make([][]byte, 0, len(objects))
+	for _, o := range objects {
+		out, err := yaml.Marshal(o)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, out)
+	}
+	rootDir := filepath.Join(dir, "manifests", gitopsRoot)
+	if err := os.MkdirAll(rootDir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(rootDir, "root.yaml"), joinDocuments(docs...), 0600)
+}
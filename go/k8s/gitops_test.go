@@ -0,0 +1,176 @@
+package k8s
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// bare repository with a kustomization in clusters/c1 on main, as a file:// URL
+func bareRepo(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	bare := filepath.Join(dir, "fleet.git")
+	work := filepath.Join(dir, "work")
+	git(t, dir, "init", "--bare", "--initial-branch=main", bare)
+	git(t, dir, "clone", bare, work)
+	if err := os.MkdirAll(filepath.Join(work, "clusters", "c1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	kustomization := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources: []\n"
+	if err := os.WriteFile(filepath.Join(work, "clusters", "c1", "kustomization.yaml"), []byte(kustomization), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(t, work, "add", ".")
+	git(t, work, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", "c1")
+	git(t, work, "push", "origin", "HEAD:main")
+	return "file://" + bare, bare
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// objects of the rendered root sync manifest, by kind
+func renderGitOps(t *testing.T, g GitOpsDef, creds GitOpsCredentials) map[string]map[string]interface{} {
+	t.Helper()
+	chdirVars(t)
+	ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: "1.30.2", GitOps: &g})
+	if err := setupGitOps(&InfraConfig{GitOps: map[string]GitOpsCredentials{"c1": creds}}, ictx, "c1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := genAddonFiles(*ictx.inventory); err != nil {
+		t.Fatal(err)
+	}
+	out, err := os.ReadFile("./vars/addons-c1/manifests/gitops/root.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := map[string]map[string]interface{}{}
+	dec := yaml.NewDecoder(bytes.NewReader(out))
+	for {
+		o := map[string]interface{}{}
+		if err := dec.Decode(&o); err != nil {
+			break
+		}
+		objects[o["kind"].(string)] = o
+	}
+	hf, err := os.ReadFile("./vars/addons-c1/helmfile.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(hf), "name: "+g.Engine) {
+		t.Errorf("no %s release in the helmfile:\n%s", g.Engine, hf)
+	}
+	return objects
+}
+
+// field of an object by its dotted path
+func field(o map[string]interface{}, path string) interface{} {
+	var v interface{} = o
+	for _, k := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+func TestGitOpsFlux(t *testing.T) {
+	url, bare := bareRepo(t)
+	objects := renderGitOps(t, GitOpsDef{Engine: "flux", Repo: url, Path: "clusters/c1"}, GitOpsCredentials{})
+	if _, ok := objects["Secret"]; ok {
+		t.Error("a file:// repository gets a deploy key secret")
+	}
+	repo, kustomization := objects["GitRepository"], objects["Kustomization"]
+	if repo == nil || kustomization == nil {
+		t.Fatalf("objects: %v", objects)
+	}
+	if field(repo, "spec.url") != url || field(repo, "spec.secretRef") != nil {
+		t.Errorf("GitRepository spec: %v", repo["spec"])
+	}
+	branch := field(repo, "spec.ref.branch").(string)
+	path := field(kustomization, "spec.path").(string)
+	if field(kustomization, "spec.sourceRef.name") != field(repo, "metadata.name") || field(kustomization, "spec.prune") != true {
+		t.Errorf("Kustomization spec: %v", kustomization["spec"])
+	}
+	// the branch and the path point at the kustomization of the repository
+	if files := git(t, bare, "ls-tree", "--name-only", branch, path+"/"); files != path+"/kustomization.yaml" {
+		t.Errorf("%s:%s holds %q", branch, path, files)
+	}
+}
+
+func TestGitOpsFluxSSH(t *testing.T) {
+	creds := GitOpsCredentials{DeployKey: "private key", KnownHosts: "github.com ssh-ed25519 AAAA"}
+	objects := renderGitOps(t, GitOpsDef{Engine: "flux", Repo: "git@github.com:example/fleet.git"}, creds)
+	secret, repo := objects["Secret"], objects["GitRepository"]
+	if secret == nil || field(secret, "stringData.identity") != creds.DeployKey || field(secret, "stringData.known_hosts") != creds.KnownHosts {
+		t.Errorf("deploy key secret: %v", secret)
+	}
+	if field(repo, "spec.url") != "ssh://git@github.com/example/fleet.git" || field(repo, "spec.secretRef.name") != field(secret, "metadata.name") {
+		t.Errorf("GitRepository spec: %v", repo["spec"])
+	}
+	if field(objects["Kustomization"], "spec.path") != "." || field(repo, "spec.ref.branch") != "main" {
+		t.Errorf("defaults: %v %v", repo["spec"], objects["Kustomization"]["spec"])
+	}
+}
+
+func TestGitOpsArgoCD(t *testing.T) {
+	url, bare := bareRepo(t)
+	objects := renderGitOps(t, GitOpsDef{Engine: "argocd", Repo: url, Path: "clusters/c1"}, GitOpsCredentials{})
+	if _, ok := objects["Secret"]; ok {
+		t.Error("a file:// repository gets a repository secret")
+	}
+	app := objects["Application"]
+	if app == nil || field(app, "metadata.name") != "root" || field(app, "metadata.namespace") != "argocd" {
+		t.Fatalf("objects: %v", objects)
+	}
+	if field(app, "spec.source.repoURL") != url || field(app, "spec.syncPolicy.automated.prune") != true ||
+		field(app, "spec.syncPolicy.automated.selfHeal") != true || field(app, "spec.destination.server") != "https://kubernetes.default.svc" {
+		t.Errorf("Application spec: %v", app["spec"])
+	}
+	revision := field(app, "spec.source.targetRevision").(string)
+	path := field(app, "spec.source.path").(string)
+	if files := git(t, bare, "ls-tree", "--name-only", revision, path+"/"); files != path+"/kustomization.yaml" {
+		t.Errorf("%s:%s holds %q", revision, path, files)
+	}
+}
+
+func TestGitOpsArgoCDSSH(t *testing.T) {
+	creds := GitOpsCredentials{DeployKey: "private key", KnownHosts: "github.com ssh-ed25519 AAAA"}
+	objects := renderGitOps(t, GitOpsDef{Engine: "argocd", Repo: "git@github.com:example/fleet.git", Branch: "prod"}, creds)
+	secret := objects["Secret"]
+	// the label key holds dots, it is looked up directly
+	labels, _ := field(secret, "metadata.labels").(map[string]interface{})
+	if labels["argocd.argoproj.io/secret-type"] != "repository" || field(secret, "stringData.url") != "git@github.com:example/fleet.git" ||
+		field(secret, "stringData.sshPrivateKey") != creds.DeployKey {
+		t.Errorf("repository secret: %v", secret)
+	}
+	if field(objects["Application"], "spec.source.targetRevision") != "prod" {
+		t.Errorf("Application spec: %v", objects["Application"]["spec"])
+	}
+	values, err := os.ReadFile("./vars/addons-c1/argocd-values.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(values), creds.KnownHosts) {
+		t.Errorf("argocd values without the known hosts:\n%s", values)
+	}
+}
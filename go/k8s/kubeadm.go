@@ -0,0 +1,451 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const podSubnet = "10.244.0.0/16"
+
+// kubeadm patch targets, see `kubeadm init --help` (--patches)
+var patchTargets = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd", "kubeletconfiguration"}
+
+type ComponentDef struct {
+	ExtraArgs map[string]string `yaml:"extra_args,omitempty"`
+}
+
+type KubeletDef struct {
+	ExtraArgs map[string]string      `yaml:"extra_args,omitempty"`
+	Config    map[string]interface{} `yaml:"config,omitempty"`
+}
+
+type KubeProxyDef struct {
+	Config map[string]interface{} `yaml:"config,omitempty"`
+}
+
+// kubeadm tuning from the topology
+type KubeadmDef struct {
+	APIServer         ComponentDef    `yaml:"api_server,omitempty"`
+	ControllerManager ComponentDef    `yaml:"controller_manager,omitempty"`
+	Scheduler         ComponentDef    `yaml:"scheduler,omitempty"`
+	Etcd              ComponentDef    `yaml:"etcd,omitempty"`
+	Kubelet           KubeletDef      `yaml:"kubelet,omitempty"`
+	KubeProxy         KubeProxyDef    `yaml:"kube_proxy,omitempty"`
+	FeatureGates      map[string]bool `yaml:"feature_gates,omitempty"`
+	// strategic merge patches, keyed by patch target
+	Patches map[string]map[string]interface{} `yaml:"patches,omitempty"`
+}
+
+type kubeadmArg struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type kubeadmVolume struct {
+	Name      string `yaml:"name"`
+	HostPath  string `yaml:"hostPath"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+	PathType  string `yaml:"pathType,omitempty"`
+}
+
+type kubeadmControlPlaneComponent struct {
+	CertSANs     []string        `yaml:"certSANs,omitempty"`
+	ExtraArgs    interface{}     `yaml:"extraArgs,omitempty"`
+	ExtraVolumes []kubeadmVolume `yaml:"extraVolumes,omitempty"`
+}
+
+type kubeadmClusterConfiguration struct {
+	APIVersion      string `yaml:"apiVersion"`
+	Kind            string `yaml:"kind"`
+	ImageRepository string `yaml:"imageRepository,omitempty"`
+	DNS             struct {
+		ImageRepository string `yaml:"imageRepository,omitempty"`
+	} `yaml:"dns,omitempty"`
+	Networking struct {
+		PodSubnet string `yaml:"podSubnet"`
+	} `yaml:"networking"`
+	ControlPlaneEndpoint string                       `yaml:"controlPlaneEndpoint"`
+	APIServer            kubeadmControlPlaneComponent `yaml:"apiServer"`
+	ControllerManager    kubeadmControlPlaneComponent `yaml:"controllerManager,omitempty"`
+	Scheduler            kubeadmControlPlaneComponent `yaml:"scheduler,omitempty"`
+	Etcd                 struct {
+		Local struct {
+			ExtraArgs interface{} `yaml:"extraArgs,omitempty"`
+		} `yaml:"local"`
+	} `yaml:"etcd"`
+}
+
+type kubeadmNodeRegistration struct {
+	CriSocket        string         `yaml:"criSocket,omitempty"`
+	KubeletExtraArgs interface{}    `yaml:"kubeletExtraArgs,omitempty"`
+	Taints           []kubeadmTaint `yaml:"taints,omitempty"`
+}
+
+type kubeadmTaint struct {
+	Key    string `yaml:"key" json:"key"`
+	Value  string `yaml:"value,omitempty" json:"value,omitempty"`
+	Effect string `yaml:"effect" json:"effect"`
+}
+
+type kubeadmPatches struct {
+	Directory string `yaml:"directory"`
+}
+
+type kubeadmBootstrapToken struct {
+	Token  string   `yaml:"token"`
+	TTL    string   `yaml:"ttl"`
+	Usages []string `yaml:"usages"`
+	Groups []string `yaml:"groups"`
+}
+
+type kubeadmInitConfiguration struct {
+	APIVersion       string                  `yaml:"apiVersion"`
+	Kind             string                  `yaml:"kind"`
+	BootstrapTokens  []kubeadmBootstrapToken `yaml:"bootstrapTokens"`
+	NodeRegistration kubeadmNodeRegistration `yaml:"nodeRegistration,omitempty"`
+	SkipPhases       []string                `yaml:"skipPhases,omitempty"`
+	Patches          *kubeadmPatches         `yaml:"patches,omitempty"`
+}
+
+type kubeadmJoinConfiguration struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Discovery  struct {
+		BootstrapToken struct {
+			APIServerEndpoint string   `yaml:"apiServerEndpoint"`
+			Token             string   `yaml:"token"`
+			CACertHashes      []string `yaml:"caCertHashes"`
+		} `yaml:"bootstrapToken"`
+	} `yaml:"discovery"`
+	NodeRegistration kubeadmNodeRegistration `yaml:"nodeRegistration,omitempty"`
+	ControlPlane     *struct{}               `yaml:"controlPlane,omitempty"`
+	Patches          *kubeadmPatches         `yaml:"patches,omitempty"`
+}
+
+type kubeletConfiguration struct {
+	APIVersion   string          `yaml:"apiVersion"`
+	Kind         string          `yaml:"kind"`
+	CgroupDriver string          `yaml:"cgroupDriver"`
+	FeatureGates map[string]bool `yaml:"featureGates,omitempty"`
+}
+
+type kubeProxyConfiguration struct {
+	APIVersion   string          `yaml:"apiVersion"`
+	Kind         string          `yaml:"kind"`
+	FeatureGates map[string]bool `yaml:"featureGates,omitempty"`
+}
+
+// minor version of a kubernetes version string such as 1.29 or 1.29.3
+func k8sMinor(version string) int {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 {
+		return 0
+	}
+	minor, _ := strconv.Atoi(parts[1])
+	return minor
+}
+
+// kubeadm.k8s.io/v1beta4 is available from kubeadm 1.31
+func kubeadmAPIVersion(version string) string {
+	if k8sMinor(version) >= 31 {
+		return "kubeadm.k8s.io/v1beta4"
+	}
+	return "kubeadm.k8s.io/v1beta3"
+}
+
+// v1beta3 takes extra args as a map, v1beta4 as a list of name/value pairs
+func kubeadmExtraArgs(apiVersion string, args map[string]string) interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	if apiVersion == "kubeadm.k8s.io/v1beta3" {
+		return args
+	}
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]kubeadmArg, 0, len(args))
+	for _, name := range names {
+		list = append(list, kubeadmArg{Name: name, Value: args[name]})
+	}
+	return list
+}
+
+// merge component args, override wins
+func mergeArgs(base map[string]string, override map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+// add the cluster wide feature gates to component extra args
+func withFeatureGates(args map[string]string, gates map[string]bool) map[string]string {
+	merged := make(map[string]string)
+	if len(gates) > 0 {
+		names := make([]string, 0, len(gates))
+		for name := range gates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fg := make([]string, 0, len(gates))
+		for _, name := range names {
+			fg = append(fg, fmt.Sprintf("%s=%t", name, gates[name]))
+		}
+		merged["feature-gates"] = strings.Join(fg, ",")
+	}
+	for k, v := range args {
+		merged[k] = v
+	}
+	return merged
+}
+
+func criSocket(cri string) string {
+	switch cri {
+	case "docker":
+		return "unix:///var/run/cri-dockerd.sock"
+	case "cri-o":
+		return "unix:///var/run/crio/crio.sock"
+	}
+	return "unix:///var/run/containerd/containerd.sock"
+}
+
+func validateCri(cri string, version string) error {
+	switch cri {
+	case "", "containerd", "docker":
+		return nil
+	case "cri-o":
+		// cri-o is packaged on pkgs.k8s.io from 1.28, with the same minor as kubernetes
+		if k8sMinor(version) < 28 {
+			return fmt.Errorf("cri-o needs kubernetes_version 1.28 or later, got %s", version)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown cri %q, must be one of containerd, docker, cri-o", cri)
+}
+
+// control plane endpoints (private, public) as seen by the nodes
+func controlPlaneEndpoints(inv Inventory) (string, string) {
+	if inv.LoadBalancer != nil {
+		return inv.LoadBalancer.PrivateIP, inv.LoadBalancer.PublicIP
+	}
+	return inv.MasterIPs[0].PrivateIP, inv.MasterIPs[0].PublicIP
+}
+
+// recursively merge override into base
+func mergeValues(base map[interface{}]interface{}, override map[interface{}]interface{}) map[interface{}]interface{} {
+	for k, v := range override {
+		if ov, ok := v.(map[interface{}]interface{}); ok {
+			if bv, ok := base[k].(map[interface{}]interface{}); ok {
+				base[k] = mergeValues(bv, ov)
+				continue
+			}
+		}
+		base[k] = v
+	}
+	return base
+}
+
+// marshal a typed configuration object with user supplied fields merged on top
+func marshalWithOverrides(obj interface{}, overrides map[string]interface{}) ([]byte, error) {
+	out, err := yaml.Marshal(obj)
+	if err != nil || len(overrides) == 0 {
+		return out, err
+	}
+	base := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(out, &base); err != nil {
+		return nil, err
+	}
+	ov := make(map[interface{}]interface{})
+	for k, v := range overrides {
+		ov[k] = v
+	}
+	return yaml.Marshal(mergeValues(base, ov))
+}
+
+func joinDocuments(docs ...[]byte) []byte {
+	parts := make([]string, 0, len(docs))
+	for _, d := range docs {
+		parts = append(parts, strings.TrimSpace(string(d)))
+	}
+	return []byte(strings.Join(parts, "\n---\n") + "\n")
+}
+
+// render the kubeadm init and join configurations of a cluster
+func kubeadmConfig(inv Inventory) (initConfig []byte, joinCP []byte, joinWorker []byte, err error) {
+	apiVersion := kubeadmAPIVersion(inv.K8sversion)
+	kadm := inv.Kubeadm
+	cpEndpoint, cpPublicEndpoint := controlPlaneEndpoints(inv)
+
+	cc := kubeadmClusterConfiguration{APIVersion: apiVersion, Kind: "ClusterConfiguration"}
+	if inv.PrivateRegistry != "" {
+		cc.ImageRepository = inv.PrivateRegistry
+		cc.DNS.ImageRepository = inv.PrivateRegistry + "/coredns"
+	}
+	cc.Networking.PodSubnet = inv.PodSubnet
+	cc.ControlPlaneEndpoint = cpEndpoint
+	cc.APIServer.CertSANs = []string{cpEndpoint}
+	if cpPublicEndpoint != "" {
+		cc.APIServer.CertSANs = append(cc.APIServer.CertSANs, cpPublicEndpoint)
+	}
+	apiServerArgs := mergeArgs(oidcAPIServerArgs(inv.OIDC), auditAPIServerArgs(inv.Audit))
+	apiServerArgs = mergeArgs(apiServerArgs, encryptionAPIServerArgs(inv.Encryption))
+	apiServerArgs = mergeArgs(apiServerArgs, kadm.APIServer.ExtraArgs)
+	cc.APIServer.ExtraArgs = kubeadmExtraArgs(apiVersion, withFeatureGates(apiServerArgs, kadm.FeatureGates))
+	cc.APIServer.ExtraVolumes = append(auditAPIServerVolumes(inv.Audit), encryptionAPIServerVolumes(inv.Encryption)...)
+	controllerManagerArgs := kadm.ControllerManager.ExtraArgs
+	if nativeRouting(inv.Cilium) {
+		// node pod CIDRs match the hcloud routes, ansible assigns them
+		controllerManagerArgs = mergeArgs(map[string]string{"allocate-node-cidrs": "false"}, controllerManagerArgs)
+	}
+	cc.ControllerManager.ExtraArgs = kubeadmExtraArgs(apiVersion, withFeatureGates(controllerManagerArgs, kadm.FeatureGates))
+	cc.Scheduler.ExtraArgs = kubeadmExtraArgs(apiVersion, withFeatureGates(kadm.Scheduler.ExtraArgs, kadm.FeatureGates))
+	cc.Etcd.Local.ExtraArgs = kubeadmExtraArgs(apiVersion, kadm.Etcd.ExtraArgs)
+
+	var patches *kubeadmPatches
+	if len(kadm.Patches) > 0 {
+		patches = &kubeadmPatches{Directory: "/etc/kubernetes/patches"}
+	}
+	nodeRegistration := kubeadmNodeRegistration{
+		CriSocket:        criSocket(inv.Cri),
+		KubeletExtraArgs: kubeadmExtraArgs(apiVersion, kadm.Kubelet.ExtraArgs),
+	}
+
+	ttl := "24h0m0s"
+	if inv.Bootstrap == bootstrapCloudInit {
+		// the join configurations in the user data hold the token
+		ttl = cloudInitTokenTTL
+	}
+	ic := kubeadmInitConfiguration{APIVersion: apiVersion, Kind: "InitConfiguration"}
+	ic.BootstrapTokens = []kubeadmBootstrapToken{{
+		Token:  inv.Pki.BootstrapToken,
+		TTL:    ttl,
+		Usages: []string{"signing", "authentication"},
+		Groups: []string{"system:bootstrappers:kubeadm:default-node-token"},
+	}}
+	ic.NodeRegistration = nodeRegistration
+	ic.Patches = patches
+	if skipKubeProxy(inv.CniDef) {
+		ic.SkipPhases = []string{"addon/kube-proxy"}
+	}
+
+	kc := kubeletConfiguration{
+		APIVersion:   "kubelet.config.k8s.io/v1beta1",
+		Kind:         "KubeletConfiguration",
+		CgroupDriver: "systemd",
+		FeatureGates: kadm.FeatureGates,
+	}
+	kp := kubeProxyConfiguration{
+		APIVersion:   "kubeproxy.config.k8s.io/v1alpha1",
+		Kind:         "KubeProxyConfiguration",
+		FeatureGates: kadm.FeatureGates,
+	}
+
+	ccOut, err := yaml.Marshal(cc)
+	if err != nil {
+		return
+	}
+	icOut, err := yaml.Marshal(ic)
+	if err != nil {
+		return
+	}
+	kcOut, err := marshalWithOverrides(kc, kadm.Kubelet.Config)
+	if err != nil {
+		return
+	}
+	kpOut, err := marshalWithOverrides(kp, kadm.KubeProxy.Config)
+	if err != nil {
+		return
+	}
+	initConfig = joinDocuments(ccOut, icOut, kcOut, kpOut)
+
+	jc := kubeadmJoinConfiguration{APIVersion: apiVersion, Kind: "JoinConfiguration"}
+	jc.Discovery.BootstrapToken.APIServerEndpoint = cpEndpoint + ":6443"
+	jc.Discovery.BootstrapToken.Token = inv.Pki.BootstrapToken
+	jc.Discovery.BootstrapToken.CACertHashes = []string{"sha256:" + inv.Pki.CACertHash}
+	jc.NodeRegistration = workerNodeRegistration(apiVersion, nodeRegistration, kadm, inv.Worker)
+	jc.Patches = patches
+	joinWorker, err = yaml.Marshal(jc)
+	if err != nil {
+		return
+	}
+	jc.NodeRegistration = nodeRegistration
+	jc.ControlPlane = &struct{}{}
+	joinCP, err = yaml.Marshal(jc)
+	return
+}
+
+func validateKubeadm(kadm KubeadmDef) error {
+	for target := range kadm.Patches {
+		valid := false
+		for _, t := range patchTargets {
+			valid = valid || t == target
+		}
+		if !valid {
+			return fmt.Errorf("unknown kubeadm patch target %s, must be one of %s", target, strings.Join(patchTargets, ", "))
+		}
+	}
+	return nil
+}
+
+// the kubeadm configurations hold the bootstrap token and are written straight to ./vars
+func genKubeadmFiles(clusterInventory Inventory) error {
+	initConfig, joinCP, joinWorker, err := kubeadmConfig(clusterInventory)
+	if err != nil {
+		return fmt.Errorf("cannot render the kubeadm configuration of cluster %s: %w", clusterInventory.ClusterName, err)
+	}
+	files := map[string][]byte{
+		fmt.Sprintf("./vars/kubeadm-%s.yaml", clusterInventory.ClusterName):             initConfig,
+		fmt.Sprintf("./vars/kubeadm-join-cp-%s.yaml", clusterInventory.ClusterName):     joinCP,
+		fmt.Sprintf("./vars/kubeadm-join-worker-%s.yaml", clusterInventory.ClusterName): joinWorker,
+	}
+	if clusterInventory.Audit.Enabled() {
+		policy, err := auditPolicy(clusterInventory.Audit)
+		if err != nil {
+			return fmt.Errorf("cannot read the audit policy of cluster %s: %w", clusterInventory.ClusterName, err)
+		}
+		files[fmt.Sprintf("./vars/audit-policy-%s.yaml", clusterInventory.ClusterName)] = policy
+	}
+	if clusterInventory.Encryption.Enabled {
+		enc, err := encryptionConfig(clusterInventory.Encryption, clusterInventory.EncryptionKey)
+		if err != nil {
+			return fmt.Errorf("cannot render the encryption configuration of cluster %s: %w", clusterInventory.ClusterName, err)
+		}
+		files[fmt.Sprintf("./vars/encryption-%s.yaml", clusterInventory.ClusterName)] = enc
+	}
+	patchDir := fmt.Sprintf("./vars/kubeadm-patches-%s", clusterInventory.ClusterName)
+	if err := os.RemoveAll(patchDir); err != nil {
+		return err
+	}
+	if len(clusterInventory.Kubeadm.Patches) > 0 {
+		if err := os.MkdirAll(patchDir, 0700); err != nil {
+			return err
+		}
+		for target, patch := range clusterInventory.Kubeadm.Patches {
+			out, err := yaml.Marshal(patch)
+			if err != nil {
+				return fmt.Errorf("cannot render the kubeadm patch %s of cluster %s: %w", target, clusterInventory.ClusterName, err)
+			}
+			files[filepath.Join(patchDir, target+"+strategic.yaml")] = out
+		}
+	}
+	for outFileLoc, content := range files {
+		if err := os.WriteFile(outFileLoc, content, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
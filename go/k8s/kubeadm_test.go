@@ -0,0 +1,242 @@
+package k8s
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenKubeadmFiles(t *testing.T) {
+	chdirVars(t)
+	ictx := backupCluster(BackupDef{})
+	ictx.inventory.Audit = AuditDef{Profile: "metadata"}
+	if err := genKubeadmFiles(*ictx.inventory); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"kubeadm-c1.yaml", "kubeadm-join-cp-c1.yaml", "kubeadm-join-worker-c1.yaml", "audit-policy-c1.yaml"} {
+		out, err := os.ReadFile("./vars/" + f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out) == 0 {
+			t.Errorf("%s is empty", f)
+		}
+	}
+}
+
+func TestGenKubeadmFilesFails(t *testing.T) {
+	chdirVars(t)
+	ictx := backupCluster(BackupDef{})
+	ictx.inventory.Audit = AuditDef{Profile: "custom", PolicyFile: "missing-policy.yaml"}
+	err := genKubeadmFiles(*ictx.inventory)
+	if err == nil || !strings.Contains(err.Error(), "audit policy of cluster c1") {
+		t.Fatalf("got error %v, want the missing audit policy", err)
+	}
+	// nothing is written when a file cannot be rendered
+	entries, err := os.ReadDir("./vars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d files written", len(entries))
+	}
+}
+
+// documents of a rendered kubeadm file by kind
+func kubeadmDocs(t *testing.T, out []byte) map[string]map[string]interface{} {
+	t.Helper()
+	docs := map[string]map[string]interface{}{}
+	for _, doc := range strings.Split(string(out), "\n---\n") {
+		var m map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &m); err != nil {
+			t.Fatal(err)
+		}
+		docs[m["kind"].(string)] = m
+	}
+	return docs
+}
+
+func TestKubeadmConfigVersions(t *testing.T) {
+	for _, tc := range []struct {
+		version    string
+		apiVersion string
+		// extraArgs as a map before v1beta4, a list of name/value pairs from v1beta4
+		argsList bool
+	}{
+		{"1.29.6", "kubeadm.k8s.io/v1beta3", false},
+		{"1.30.2", "kubeadm.k8s.io/v1beta3", false},
+		{"1.31.0", "kubeadm.k8s.io/v1beta4", true},
+		{"v1.32", "kubeadm.k8s.io/v1beta4", true},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: tc.version})
+			ictx.inventory.Kubeadm = KubeadmDef{
+				APIServer: ComponentDef{ExtraArgs: map[string]string{"max-requests-inflight": "800"}},
+				Etcd:      ComponentDef{ExtraArgs: map[string]string{"quota-backend-bytes": "8589934592"}},
+				Kubelet:   KubeletDef{ExtraArgs: map[string]string{"node-labels": "tier=apps"}},
+			}
+			initConfig, joinCP, joinWorker, err := kubeadmConfig(*ictx.inventory)
+			if err != nil {
+				t.Fatal(err)
+			}
+			docs := kubeadmDocs(t, initConfig)
+			for _, kind := range []string{"ClusterConfiguration", "InitConfiguration"} {
+				if docs[kind]["apiVersion"] != tc.apiVersion {
+					t.Errorf("%s apiVersion %v", kind, docs[kind]["apiVersion"])
+				}
+			}
+			for _, join := range [][]byte{joinCP, joinWorker} {
+				if jc := kubeadmDocs(t, join)["JoinConfiguration"]; jc["apiVersion"] != tc.apiVersion {
+					t.Errorf("JoinConfiguration apiVersion %v", jc["apiVersion"])
+				}
+			}
+			cc := docs["ClusterConfiguration"]
+			etcd := cc["etcd"].(map[string]interface{})["local"].(map[string]interface{})
+			kubelet := docs["InitConfiguration"]["nodeRegistration"].(map[string]interface{})
+			for name, args := range map[string]interface{}{
+				"apiServer":        cc["apiServer"].(map[string]interface{})["extraArgs"],
+				"etcd":             etcd["extraArgs"],
+				"kubeletExtraArgs": kubelet["kubeletExtraArgs"],
+			} {
+				list, isList := args.([]interface{})
+				if _, isMap := args.(map[string]interface{}); isList == isMap || isList != tc.argsList {
+					t.Errorf("%s extraArgs %#v", name, args)
+					continue
+				}
+				if isList {
+					if pair, ok := list[0].(map[string]interface{}); !ok || pair["name"] == nil || pair["value"] == nil {
+						t.Errorf("%s extraArgs %#v, want name/value pairs", name, args)
+					}
+				}
+			}
+			if args := apiServerExtraArgs(t, initConfig); args["max-requests-inflight"] != "800" {
+				t.Errorf("apiserver extraArgs %v", args)
+			}
+		})
+	}
+}
+
+func TestKubeadmFeatureGates(t *testing.T) {
+	ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: "1.30.2"})
+	gates := map[string]bool{"SidecarContainers": true, "InPlacePodVerticalScaling": false}
+	ictx.inventory.Kubeadm = KubeadmDef{
+		FeatureGates: gates,
+		// an explicit flag of a component wins over the cluster wide gates
+		Scheduler: ComponentDef{ExtraArgs: map[string]string{"feature-gates": "SchedulerQueueingHints=true"}},
+	}
+	initConfig, _, _, err := kubeadmConfig(*ictx.inventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := kubeadmDocs(t, initConfig)
+	cc := docs["ClusterConfiguration"]
+	want := "InPlacePodVerticalScaling=false,SidecarContainers=true"
+	for component, flag := range map[string]string{"apiServer": want, "controllerManager": want, "scheduler": "SchedulerQueueingHints=true"} {
+		args, _ := cc[component].(map[string]interface{})["extraArgs"].(map[string]interface{})
+		if args["feature-gates"] != flag {
+			t.Errorf("%s feature-gates %v, want %s", component, args["feature-gates"], flag)
+		}
+	}
+	for _, kind := range []string{"KubeletConfiguration", "KubeProxyConfiguration"} {
+		fg, _ := docs[kind]["featureGates"].(map[string]interface{})
+		if len(fg) != len(gates) || fg["SidecarContainers"] != true || fg["InPlacePodVerticalScaling"] != false {
+			t.Errorf("%s featureGates %v", kind, fg)
+		}
+	}
+}
+
+func TestKubeletConfigurationOverrides(t *testing.T) {
+	ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: "1.31.0"})
+	ictx.inventory.Kubeadm = KubeadmDef{
+		FeatureGates: map[string]bool{"SidecarContainers": true},
+		Kubelet: KubeletDef{Config: map[string]interface{}{
+			"maxPods":      200,
+			"cgroupDriver": "cgroupfs",
+			"featureGates": map[interface{}]interface{}{"GracefulNodeShutdown": true},
+			"evictionHard": map[interface{}]interface{}{"memory.available": "200Mi"},
+		}},
+		KubeProxy: KubeProxyDef{Config: map[string]interface{}{"mode": "ipvs"}},
+	}
+	initConfig, _, _, err := kubeadmConfig(*ictx.inventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := kubeadmDocs(t, initConfig)
+	kc := docs["KubeletConfiguration"]
+	if kc["apiVersion"] != "kubelet.config.k8s.io/v1beta1" || kc["maxPods"] != 200 || kc["cgroupDriver"] != "cgroupfs" {
+		t.Errorf("KubeletConfiguration %v", kc)
+	}
+	// the maps are merged, the gates of the cluster are kept
+	if fg, _ := kc["featureGates"].(map[string]interface{}); fg["SidecarContainers"] != true || fg["GracefulNodeShutdown"] != true {
+		t.Errorf("featureGates %v", kc["featureGates"])
+	}
+	if eviction, _ := kc["evictionHard"].(map[string]interface{}); eviction["memory.available"] != "200Mi" {
+		t.Errorf("evictionHard %v", kc["evictionHard"])
+	}
+	if kp := docs["KubeProxyConfiguration"]; kp["mode"] != "ipvs" || kp["apiVersion"] != "kubeproxy.config.k8s.io/v1alpha1" {
+		t.Errorf("KubeProxyConfiguration %v", kp)
+	}
+}
+
+func TestKubeadmPatches(t *testing.T) {
+	chdirVars(t)
+	ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: "1.30.2"})
+	ictx.inventory.Kubeadm = KubeadmDef{Patches: map[string]map[string]interface{}{
+		"kube-apiserver": {"spec": map[string]interface{}{"priorityClassName": "system-cluster-critical"}},
+		"etcd":           {"spec": map[string]interface{}{"hostNetwork": true}},
+	}}
+	if err := validateKubeadm(ictx.inventory.Kubeadm); err != nil {
+		t.Fatal(err)
+	}
+	if err := genKubeadmFiles(*ictx.inventory); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir("./vars/kubeadm-patches-c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if strings.Join(names, ",") != "etcd+strategic.yaml,kube-apiserver+strategic.yaml" {
+		t.Errorf("patches %v", names)
+	}
+	var patch map[string]map[string]interface{}
+	out, err := os.ReadFile("./vars/kubeadm-patches-c1/kube-apiserver+strategic.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(out, &patch); err != nil || patch["spec"]["priorityClassName"] != "system-cluster-critical" {
+		t.Errorf("kube-apiserver patch %s: %v", out, err)
+	}
+	// the init and join configurations read the patches from the directory ansible copies them to
+	for _, f := range []string{"kubeadm-c1.yaml", "kubeadm-join-cp-c1.yaml", "kubeadm-join-worker-c1.yaml"} {
+		out, err := os.ReadFile("./vars/" + f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for kind, doc := range kubeadmDocs(t, out) {
+			if kind != "InitConfiguration" && kind != "JoinConfiguration" {
+				continue
+			}
+			if p, _ := doc["patches"].(map[string]interface{}); p["directory"] != "/etc/kubernetes/patches" {
+				t.Errorf("%s %s patches %v", f, kind, doc["patches"])
+			}
+		}
+	}
+	// a removed patch is removed from the directory
+	delete(ictx.inventory.Kubeadm.Patches, "etcd")
+	if err := genKubeadmFiles(*ictx.inventory); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("./vars/kubeadm-patches-c1/etcd+strategic.yaml"); err == nil {
+		t.Error("the etcd patch is kept")
+	}
+	ictx.inventory.Kubeadm.Patches["kubelet"] = map[string]interface{}{}
+	if err := validateKubeadm(ictx.inventory.Kubeadm); err == nil || !strings.Contains(err.Error(), "unknown kubeadm patch target kubelet") {
+		t.Errorf("error %v", err)
+	}
+}
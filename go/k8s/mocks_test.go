@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// mocked resource monitor, it records the inputs of every resource by name
+type mocks struct {
+	mu     sync.Mutex
+	nextID int
+	inputs map[string]resource.PropertyMap
+	// aliases of the resources by name
+	aliases map[string][]string
+	// run when the resource with the name is created, standing in for the commands the resource runs
+	onCreate map[string]func()
+	// error of the resource with the name, standing in for a failed command
+	failures map[string]error
+}
+
+func newMocks() *mocks {
+	return &mocks{inputs: make(map[string]resource.PropertyMap), aliases: make(map[string][]string)}
+}
+
+func (m *mocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	m.inputs[args.Name] = args.Inputs
+	if args.RegisterRPC != nil {
+		for _, a := range args.RegisterRPC.GetAliases() {
+			if spec := a.GetSpec(); spec != nil {
+				m.aliases[args.Name] = append(m.aliases[args.Name], fmt.Sprintf("name=%s noParent=%t", spec.GetName(), spec.GetNoParent()))
+			}
+		}
+	}
+	if f := m.onCreate[args.Name]; f != nil {
+		f()
+	}
+	if err := m.failures[args.Name]; err != nil {
+		return "", nil, err
+	}
+	outputs := args.Inputs.Copy()
+	// the addresses hcloud assigns, the inventory is rendered from them
+	if args.TypeToken == "hcloud:index/server:Server" {
+		outputs["ipv4Address"] = resource.NewStringProperty(fmt.Sprintf("203.0.113.%d", m.nextID))
+		outputs["networks"] = resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.PropertyMap{
+			"ip": resource.NewStringProperty(fmt.Sprintf("10.0.1.%d", m.nextID)),
+		})})
+	}
+	if args.TypeToken == "tls:index/selfSignedCert:SelfSignedCert" {
+		outputs["certPem"] = resource.NewStringProperty(testCACert())
+	}
+	if args.TypeToken == "tls:index/privateKey:PrivateKey" {
+		for _, k := range []resource.PropertyKey{"privateKeyPem", "publicKeyPem", "privateKeyOpenssh", "publicKeyOpenssh"} {
+			outputs[k] = resource.NewStringProperty(string(k) + " of " + args.Name)
+		}
+	}
+	return strconv.Itoa(m.nextID), outputs, nil
+}
+
+var (
+	testCACertOnce sync.Once
+	testCACertPem  string
+)
+
+// self-signed certificate standing in for the CAs, the PKI hashes its public key
+func testCACert() string {
+	testCACertOnce.Do(func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "kubernetes"},
+			NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		if err != nil {
+			panic(err)
+		}
+		testCACertPem = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	})
+	return testCACertPem
+}
+
+func (m *mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	return args.Args, nil
+}
+
+// inputs of the resource registered with name, nil when there is none
+func (m *mocks) resource(name string) resource.PropertyMap {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inputs[name]
+}
+
+// runs the test in an empty directory with a ./vars directory, the rendered files are written to it
+func chdirVars(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "vars"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+// infra of a cluster c1 with one control plane node and one worker, as it is after its servers are created
+func testCluster(cluster *Cluster) *infra {
+	ictx := NewClusterInfra(&InfraConfig{SSHUser: "root"}, cluster)
+	ictx.inventory.ClusterName = "c1"
+	ictx.inventory.MasterIPs = []*Node{{PrivateIP: "10.0.1.2", PublicIP: "203.0.113.2"}}
+	ictx.inventory.WorkerIPs = []*Node{{PrivateIP: "10.0.1.3", PublicIP: "203.0.113.3"}}
+	ictx.inventory.Bastion = &Node{PrivateIP: "10.0.0.2", PublicIP: "203.0.113.1"}
+	ictx.inventory.Pki = &PKI{}
+	return ictx
+}
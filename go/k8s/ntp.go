@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// default tolerated clock offset of a node to its time source, in milliseconds
+const defaultNtpMaxOffset = 500
+
+// host names and IPv4 or IPv6 addresses, they are written into the extra vars of the playbooks
+var ntpServerRe = regexp.MustCompile(`^[A-Za-z0-9.:-]+$`)
+
+type NtpDef struct {
+	Primary   string `yaml:"primary"`
+	Secondary string `yaml:"secondary"`
+	// nodes whose clock is further off fail the cluster before kubeadm runs, defaults to 500
+	MaxOffsetMs int `yaml:"max_offset_ms,omitempty"`
+}
+
+// configured time servers, primary first
+func (n NtpDef) Servers() []string {
+	var servers []string
+	for _, s := range []string{n.Primary, n.Secondary} {
+		if s != "" {
+			servers = append(servers, s)
+		}
+	}
+	return servers
+}
+
+func validateNtp(ntp NtpDef) error {
+	if ntp.Secondary != "" && ntp.Primary == "" {
+		return fmt.Errorf("ntp.secondary %s needs ntp.primary", ntp.Secondary)
+	}
+	for _, s := range ntp.Servers() {
+		if !ntpServerRe.MatchString(s) {
+			return fmt.Errorf("ntp server %q must be a host name or IP address", s)
+		}
+	}
+	if ntp.MaxOffsetMs < 0 {
+		return fmt.Errorf("ntp.max_offset_ms must not be negative")
+	}
+	return nil
+}
+
+// extra vars of bastion-prep.yaml, the time servers of the bastion
+func bastionVars(infraCfg *InfraConfig) string {
+	servers := infraCfg.Ntp.Servers()
+	if servers == nil {
+		servers = []string{}
+	}
+	out, _ := json.Marshal(map[string]interface{}{"ntp_servers": servers})
+	return string(out)
+}
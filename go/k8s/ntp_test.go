@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateNtp(t *testing.T) {
+	for _, tc := range []struct {
+		ntp NtpDef
+		err string
+	}{
+		{NtpDef{}, ""},
+		{NtpDef{Primary: "ntp1.hetzner.de", Secondary: "ntp2.hetzner.com", MaxOffsetMs: 100}, ""},
+		{NtpDef{Primary: "10.0.0.2", Secondary: "2a01:4ff:ff00::add:1"}, ""},
+		{NtpDef{Secondary: "ntp2.hetzner.com"}, "needs ntp.primary"},
+		{NtpDef{Primary: "ntp.example.com/pool"}, "must be a host name or IP address"},
+		{NtpDef{Primary: "ntp.example.com iburst"}, "must be a host name or IP address"},
+		// the servers are written into the extra vars of the playbooks
+		{NtpDef{Primary: `ntp"}' -e 'x`}, "must be a host name or IP address"},
+		{NtpDef{Primary: "ntp.example.com", MaxOffsetMs: -1}, "must not be negative"},
+	} {
+		err := validateNtp(tc.ntp)
+		if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%+v: error %v, want %q", tc.ntp, err, tc.err)
+		}
+	}
+}
+
+// ntp variables of install.yaml rendered for a cluster
+func renderNtpVariables(t *testing.T, ntp NtpDef) (servers []string, maxOffset int) {
+	t.Helper()
+	ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: "1.30.2", Ntp: ntp})
+	tmpl, err := template.New("variables").Parse(string(variablesTmpl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, *ictx.inventory); err != nil {
+		t.Fatal(err)
+	}
+	var vars struct {
+		NtpServers     []string `yaml:"ntp_servers"`
+		NtpMaxOffsetMs int      `yaml:"ntp_max_offset_ms"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &vars); err != nil {
+		t.Fatalf("%v\n%s", err, buf.String())
+	}
+	return vars.NtpServers, vars.NtpMaxOffsetMs
+}
+
+func TestNtpVariables(t *testing.T) {
+	for _, tc := range []struct {
+		ntp       NtpDef
+		servers   string
+		maxOffset int
+	}{
+		// the distribution defaults are kept
+		{NtpDef{}, "", defaultNtpMaxOffset},
+		{NtpDef{Primary: "ntp1.hetzner.de"}, "ntp1.hetzner.de", defaultNtpMaxOffset},
+		{NtpDef{Primary: "ntp1.hetzner.de", Secondary: "ntp2.hetzner.com", MaxOffsetMs: 100}, "ntp1.hetzner.de,ntp2.hetzner.com", 100},
+	} {
+		servers, maxOffset := renderNtpVariables(t, tc.ntp)
+		if strings.Join(servers, ",") != tc.servers || maxOffset != tc.maxOffset {
+			t.Errorf("%+v: ntp_servers %v, ntp_max_offset_ms %d", tc.ntp, servers, maxOffset)
+		}
+	}
+}
+
+func TestBastionVars(t *testing.T) {
+	if vars := bastionVars(&InfraConfig{}); vars != `{"ntp_servers":[]}` {
+		t.Errorf("bastion vars %s", vars)
+	}
+	infraCfg := &InfraConfig{Ntp: NtpDef{Primary: "ntp1.hetzner.de", Secondary: "ntp2.hetzner.com"}}
+	if vars := bastionVars(infraCfg); vars != `{"ntp_servers":["ntp1.hetzner.de","ntp2.hetzner.com"]}` {
+		t.Errorf("bastion vars %s", vars)
+	}
+}
+
+// the bastion is configured once by bastion-prep.yaml, not by the install runs of every cluster
+func TestBastionNtpCommand(t *testing.T) {
+	m := newMocks()
+	if err := deployFake(t, NewAnsibleProvisioner(), m); err != nil {
+		t.Fatal(err)
+	}
+	create := m.resource("ansible-setup-bastion")["create"].StringValue()
+	if !strings.Contains(create, `-e '{"ntp_servers":[]}'`) {
+		t.Errorf("bastion-prep.yaml run %s", create)
+	}
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// path of the OIDC issuer CA on the control plane nodes, kubeadm mounts /etc/kubernetes/pki into the API server
+const oidcCAPath = "/etc/kubernetes/pki/oidc-ca.crt"
+
+type OIDCDef struct {
+	IssuerURL      string   `yaml:"issuer_url"`
+	ClientID       string   `yaml:"client_id"`
+	UsernameClaim  string   `yaml:"username_claim,omitempty"`
+	UsernamePrefix string   `yaml:"username_prefix,omitempty"`
+	GroupsClaim    string   `yaml:"groups_claim,omitempty"`
+	GroupsPrefix   string   `yaml:"groups_prefix,omitempty"`
+	ExtraScopes    []string `yaml:"extra_scopes,omitempty"`
+	// CA of the issuer, file name in ./vars
+	CAFile string `yaml:"ca_file,omitempty"`
+}
+
+func validateOIDC(oidc *OIDCDef) error {
+	if oidc == nil {
+		return nil
+	}
+	if !strings.HasPrefix(oidc.IssuerURL, "https://") {
+		return fmt.Errorf("oidc.issuer_url must be an https URL, got %q", oidc.IssuerURL)
+	}
+	if oidc.ClientID == "" {
+		return fmt.Errorf("oidc.client_id is not set")
+	}
+	if oidc.CAFile != "" {
+		if _, err := os.Stat(filepath.Join("./vars", oidc.CAFile)); err != nil {
+			return fmt.Errorf("oidc.ca_file %s not found in ./vars: %w", oidc.CAFile, err)
+		}
+	}
+	return nil
+}
+
+// kube-apiserver flags for the OIDC authenticator
+func oidcAPIServerArgs(oidc *OIDCDef) map[string]string {
+	args := make(map[string]string)
+	if oidc == nil {
+		return args
+	}
+	args["oidc-issuer-url"] = oidc.IssuerURL
+	args["oidc-client-id"] = oidc.ClientID
+	if oidc.UsernameClaim != "" {
+		args["oidc-username-claim"] = oidc.UsernameClaim
+	}
+	if oidc.UsernamePrefix != "" {
+		args["oidc-username-prefix"] = oidc.UsernamePrefix
+	}
+	if oidc.GroupsClaim != "" {
+		args["oidc-groups-claim"] = oidc.GroupsClaim
+	}
+	if oidc.GroupsPrefix != "" {
+		args["oidc-groups-prefix"] = oidc.GroupsPrefix
+	}
+	if oidc.CAFile != "" {
+		args["oidc-ca-file"] = oidcCAPath
+	}
+	return args
+}
+
+// kubeconfig that logs in through the issuer with kubelogin (kubectl oidc-login)
+func oidcKubeconfig(clusterName string, server string, caCert string, oidc *OIDCDef) (string, error) {
+	loginArgs := []string{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=" + oidc.IssuerURL,
+		"--oidc-client-id=" + oidc.ClientID,
+	}
+	for _, scope := range oidc.ExtraScopes {
+		loginArgs = append(loginArgs, "--oidc-extra-scope="+scope)
+	}
+	if oidc.CAFile != "" {
+		ca, err := os.ReadFile(filepath.Join("./vars", oidc.CAFile))
+		if err != nil {
+			return "", err
+		}
+		loginArgs = append(loginArgs, "--certificate-authority-data="+base64.StdEncoding.EncodeToString(ca))
+	}
+	user := "oidc-" + clusterName
+	kubeconfig := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Config",
+		"clusters": []interface{}{map[string]interface{}{
+			"name": clusterName,
+			"cluster": map[string]interface{}{
+				"server":                     server,
+				"certificate-authority-data": base64.StdEncoding.EncodeToString([]byte(caCert)),
+			},
+		}},
+		"users": []interface{}{map[string]interface{}{
+			"name": user,
+			"user": map[string]interface{}{
+				"exec": map[string]interface{}{
+					"apiVersion":      "client.authentication.k8s.io/v1beta1",
+					"command":         "kubectl",
+					"args":            loginArgs,
+					"interactiveMode": "IfAvailable",
+				},
+			},
+		}},
+		"contexts": []interface{}{map[string]interface{}{
+			"name": user + "@" + clusterName,
+			"context": map[string]interface{}{
+				"cluster": clusterName,
+				"user":    user,
+			},
+		}},
+		"current-context": user + "@" + clusterName,
+	}
+	out, err := yaml.Marshal(kubeconfig)
+	return string(out), err
+}
@@ -0,0 +1,183 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var testOIDC = &OIDCDef{
+	IssuerURL:      "https://dex.example.com",
+	ClientID:       "kubernetes",
+	UsernameClaim:  "email",
+	UsernamePrefix: "oidc:",
+	GroupsClaim:    "groups",
+	GroupsPrefix:   "oidc:",
+	ExtraScopes:    []string{"email", "groups"},
+	CAFile:         "dex-ca.crt",
+}
+
+func TestOIDCAPIServerArgs(t *testing.T) {
+	if args := oidcAPIServerArgs(nil); len(args) != 0 {
+		t.Errorf("flags without oidc: %v", args)
+	}
+	want := map[string]string{
+		"oidc-issuer-url":      "https://dex.example.com",
+		"oidc-client-id":       "kubernetes",
+		"oidc-username-claim":  "email",
+		"oidc-username-prefix": "oidc:",
+		"oidc-groups-claim":    "groups",
+		"oidc-groups-prefix":   "oidc:",
+		"oidc-ca-file":         oidcCAPath,
+	}
+	if got := oidcAPIServerArgs(testOIDC); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	minimal := &OIDCDef{IssuerURL: "https://dex.example.com", ClientID: "kubernetes"}
+	want = map[string]string{"oidc-issuer-url": "https://dex.example.com", "oidc-client-id": "kubernetes"}
+	if got := oidcAPIServerArgs(minimal); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// apiServer.extraArgs of the rendered ClusterConfiguration, a map up to v1beta3 and a list of name/value from v1beta4
+func apiServerExtraArgs(t *testing.T, initConfig []byte) map[string]string {
+	t.Helper()
+	var cc struct {
+		APIServer struct {
+			ExtraArgs yaml.Node `yaml:"extraArgs"`
+		} `yaml:"apiServer"`
+	}
+	doc := strings.SplitN(string(initConfig), "\n---\n", 2)[0]
+	if err := yaml.Unmarshal([]byte(doc), &cc); err != nil {
+		t.Fatal(err)
+	}
+	args := map[string]string{}
+	if cc.APIServer.ExtraArgs.Kind == yaml.SequenceNode {
+		var list []struct{ Name, Value string }
+		if err := cc.APIServer.ExtraArgs.Decode(&list); err != nil {
+			t.Fatal(err)
+		}
+		for _, a := range list {
+			args[a.Name] = a.Value
+		}
+	} else if err := cc.APIServer.ExtraArgs.Decode(&args); err != nil {
+		t.Fatal(err)
+	}
+	return args
+}
+
+func TestOIDCKubeadmFlags(t *testing.T) {
+	for _, version := range []string{"1.30.2", "1.31.0"} {
+		ictx := testCluster(&Cluster{Cri: "containerd", KubernetesVersion: version, OIDC: testOIDC})
+		initConfig, _, _, err := kubeadmConfig(*ictx.inventory)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args := apiServerExtraArgs(t, initConfig)
+		for k, v := range oidcAPIServerArgs(testOIDC) {
+			if args[k] != v {
+				t.Errorf("%s: apiserver flag %s = %q, want %q", version, k, args[k], v)
+			}
+		}
+	}
+}
+
+func TestValidateOIDC(t *testing.T) {
+	chdirVars(t)
+	if err := os.WriteFile("./vars/dex-ca.crt", []byte("ca"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		oidc    *OIDCDef
+		wantErr string
+	}{
+		{oidc: nil},
+		{oidc: testOIDC},
+		{oidc: &OIDCDef{IssuerURL: "http://dex.example.com", ClientID: "kubernetes"}, wantErr: "https URL"},
+		{oidc: &OIDCDef{IssuerURL: "https://dex.example.com"}, wantErr: "client_id"},
+		{oidc: &OIDCDef{IssuerURL: "https://dex.example.com", ClientID: "kubernetes", CAFile: "missing.crt"}, wantErr: "not found in ./vars"},
+	}
+	for _, tt := range tests {
+		err := validateOIDC(tt.oidc)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%+v: %v", tt.oidc, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%+v: got error %v, want %q", tt.oidc, err, tt.wantErr)
+		}
+	}
+}
+
+func TestOIDCKubeconfig(t *testing.T) {
+	chdirVars(t)
+	if err := os.WriteFile("./vars/dex-ca.crt", []byte("dex ca"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := oidcKubeconfig("c1", "https://203.0.113.2:6443", "cluster ca", testOIDC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kubeconfig struct {
+		Clusters []struct {
+			Name    string
+			Cluster map[string]string
+		}
+		Users []struct {
+			Name string
+			User struct {
+				Exec struct {
+					APIVersion      string `yaml:"apiVersion"`
+					Command         string
+					Args            []string
+					InteractiveMode string `yaml:"interactiveMode"`
+				}
+			}
+		}
+		Contexts []struct {
+			Name    string
+			Context map[string]string
+		}
+		CurrentContext string `yaml:"current-context"`
+	}
+	if err := yaml.Unmarshal([]byte(out), &kubeconfig); err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeconfig.Clusters) != 1 || len(kubeconfig.Users) != 1 || len(kubeconfig.Contexts) != 1 {
+		t.Fatalf("kubeconfig:\n%s", out)
+	}
+	cluster := kubeconfig.Clusters[0]
+	if cluster.Name != "c1" || cluster.Cluster["server"] != "https://203.0.113.2:6443" ||
+		cluster.Cluster["certificate-authority-data"] != base64.StdEncoding.EncodeToString([]byte("cluster ca")) {
+		t.Errorf("cluster: %+v", cluster)
+	}
+	user := kubeconfig.Users[0]
+	if user.Name != "oidc-c1" {
+		t.Errorf("user name %s", user.Name)
+	}
+	exec := user.User.Exec
+	if exec.APIVersion != "client.authentication.k8s.io/v1beta1" || exec.Command != "kubectl" || exec.InteractiveMode != "IfAvailable" {
+		t.Errorf("exec: %+v", exec)
+	}
+	wantArgs := []string{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=https://dex.example.com",
+		"--oidc-client-id=kubernetes",
+		"--oidc-extra-scope=email",
+		"--oidc-extra-scope=groups",
+		"--certificate-authority-data=" + base64.StdEncoding.EncodeToString([]byte("dex ca")),
+	}
+	if !reflect.DeepEqual(exec.Args, wantArgs) {
+		t.Errorf("exec args %v, want %v", exec.Args, wantArgs)
+	}
+	context := kubeconfig.Contexts[0]
+	if context.Name != "oidc-c1@c1" || kubeconfig.CurrentContext != context.Name ||
+		context.Context["cluster"] != "c1" || context.Context["user"] != "oidc-c1" {
+		t.Errorf("context %+v, current %s", context, kubeconfig.CurrentContext)
+	}
+}
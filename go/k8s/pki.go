@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/pulumi/pulumi-random/sdk/v4/go/random"
+	"github.com/pulumi/pulumi-tls/sdk/v5/go/tls"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// validity of the cluster certificate authorities (10 years, same as kubeadm)
+const caValidityHours = 87600
+
+type certAuthority struct {
+	key  *tls.PrivateKey
+	cert *tls.SelfSignedCert
+}
+
+// cluster PKI kept in the pulumi state
+type clusterPKI struct {
+	ca           *certAuthority
+	frontProxyCA *certAuthority
+	etcdCA       *certAuthority
+	saKey        *tls.PrivateKey
+	tokenID      *random.RandomString
+	tokenSecret  *random.RandomString
+}
+
+// PKI material pushed to the nodes, rendered into the secrets file
+type PKI struct {
+	CACert           string `yaml:"pki_ca_crt"`
+	CAKey            string `yaml:"pki_ca_key"`
+	FrontProxyCACert string `yaml:"pki_front_proxy_ca_crt"`
+	FrontProxyCAKey  string `yaml:"pki_front_proxy_ca_key"`
+	EtcdCACert       string `yaml:"pki_etcd_ca_crt"`
+	EtcdCAKey        string `yaml:"pki_etcd_ca_key"`
+	SAKey            string `yaml:"pki_sa_key"`
+	SAPub            string `yaml:"pki_sa_pub"`
+	CACertHash       string `yaml:"ca_cert_hash"`
+	BootstrapToken   string `yaml:"bootstrap_token"`
+}
+
+func newCertAuthority(ctx *pulumi.Context, name string, commonName string, opts ...pulumi.ResourceOption) (*certAuthority, error) {
+	key, err := tls.NewPrivateKey(ctx, name, &tls.PrivateKeyArgs{
+		Algorithm: pulumi.String("RSA"),
+		RsaBits:   pulumi.Int(2048),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.NewSelfSignedCert(ctx, name, &tls.SelfSignedCertArgs{
+		PrivateKeyPem:       key.PrivateKeyPem,
+		IsCaCertificate:     pulumi.Bool(true),
+		ValidityPeriodHours: pulumi.Int(caValidityHours),
+		AllowedUses: pulumi.StringArray{
+			pulumi.String("cert_signing"),
+			pulumi.String("key_encipherment"),
+			pulumi.String("digital_signature"),
+		},
+		Subject: &tls.SelfSignedCertSubjectArgs{
+			CommonName: pulumi.String(commonName),
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &certAuthority{key: key, cert: cert}, nil
+}
+
+// generate the cluster CAs, service account keypair and bootstrap token
+func setupPKI(ctx *pulumi.Context, ictx *infra, clusterName string, pulumik8sCluster *K8sCluster) (err error) {
+	p := &clusterPKI{}
+	p.ca, err = newCertAuthority(ctx, fmt.Sprintf("ca-%s", clusterName), "kubernetes", pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	p.frontProxyCA, err = newCertAuthority(ctx, fmt.Sprintf("front-proxy-ca-%s", clusterName), "front-proxy-ca", pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	p.etcdCA, err = newCertAuthority(ctx, fmt.Sprintf("etcd-ca-%s", clusterName), "etcd-ca", pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	p.saKey, err = tls.NewPrivateKey(ctx, fmt.Sprintf("sa-%s", clusterName), &tls.PrivateKeyArgs{
+		Algorithm: pulumi.String("RSA"),
+		RsaBits:   pulumi.Int(2048),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	// bootstrap token format is [a-z0-9]{6}.[a-z0-9]{16}
+	p.tokenID, err = random.NewRandomString(ctx, fmt.Sprintf("bootstrap-token-id-%s", clusterName), &random.RandomStringArgs{
+		Length:  pulumi.Int(6),
+		Upper:   pulumi.Bool(false),
+		Special: pulumi.Bool(false),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	p.tokenSecret, err = random.NewRandomString(ctx, fmt.Sprintf("bootstrap-token-secret-%s", clusterName), &random.RandomStringArgs{
+		Length:  pulumi.Int(16),
+		Upper:   pulumi.Bool(false),
+		Special: pulumi.Bool(false),
+	}, pulumi.Parent(pulumik8sCluster))
+	if err != nil {
+		return
+	}
+	ictx.pki = p
+
+	pk := pulumi.All(p.ca.cert.CertPem, p.ca.key.PrivateKeyPem,
+		p.frontProxyCA.cert.CertPem, p.frontProxyCA.key.PrivateKeyPem,
+		p.etcdCA.cert.CertPem, p.etcdCA.key.PrivateKeyPem,
+		p.saKey.PrivateKeyPem, p.saKey.PublicKeyPem,
+		p.tokenID.Result, p.tokenSecret.Result).ApplyT(
+		func(v []interface{}) ([]string, error) {
+			hash, err := caCertHash(v[0].(string))
+			if err != nil {
+				return nil, err
+			}
+			ictx.inventory.Pki = &PKI{
+				CACert:           v[0].(string),
+				CAKey:            v[1].(string),
+				FrontProxyCACert: v[2].(string),
+				FrontProxyCAKey:  v[3].(string),
+				EtcdCACert:       v[4].(string),
+				EtcdCAKey:        v[5].(string),
+				SAKey:            v[6].(string),
+				SAPub:            v[7].(string),
+				CACertHash:       hash,
+				BootstrapToken:   v[8].(string) + "." + v[9].(string),
+			}
+			return make([]string, 0), nil
+		})
+	ictx.waitFor = append(ictx.waitFor, pk)
+	return
+}
+
+// sha256 of the CA public key, as expected by --discovery-token-ca-cert-hash
+func caCertHash(certPem string) (string, error) {
+	block, _ := pem.Decode([]byte(certPem))
+	if block == nil {
+		return "", errors.New("cannot decode cluster CA certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:]), nil
+}
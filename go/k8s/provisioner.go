@@ -0,0 +1,337 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/local"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// install.yaml tags of each step of a cluster installed by ansible
+const (
+	initTags   = "common,k8s,controlplane,cni"
+	joinTags   = "worker"
+	addonsTags = "backup,charts"
+)
+
+// ansible assets of each playbook run, changes to them run the playbook again
+var (
+	bastionAssets = []string{"./.ansible/bastion-prep.yaml", "./.ansible/ntp.yaml", "./.ansible/ansible.cfg"}
+	natAssets     = []string{"./.ansible/bastion.yaml", "./.ansible/ansible.cfg"}
+	installAssets = []string{"./.ansible/install.yaml", "./.ansible/ntp.yaml", "./.ansible/ansible.cfg", "./.ansible/templates", "./.ansible/files"}
+)
+
+// sha256 of the files and the directory trees at paths, missing paths are skipped
+func hashFiles(paths ...string) (string, error) {
+	h := sha256.New()
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			fmt.Fprintf(h, "%s\x00", path)
+			_, err = io.Copy(h, f)
+			return err
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// rendered configuration of a cluster, the inventory alone and with the variables and files
+func renderedFiles(clusterName string) (inventory []string, all []string) {
+	inventory = []string{"/tmp/inventory-" + clusterName + ".ini"}
+	all = append(inventory, "/tmp/variables-"+clusterName+".yaml")
+	for _, f := range []string{"secrets-%s.yaml", "kubeadm-%s.yaml", "kubeadm-join-cp-%s.yaml", "kubeadm-join-worker-%s.yaml", "kubeadm-patches-%s",
+		"audit-policy-%s.yaml", "encryption-%s.yaml", "cni-values-%s.yaml", "registries-%s", "addons-%s"} {
+		all = append(all, "./vars/"+fmt.Sprintf(f, clusterName))
+	}
+	return
+}
+
+// hashes of the assets and the rendered configuration, set as environment of a playbook run so a change updates the command.
+// Triggers would replace it, and the Delete of the old command runs after the new one
+func provisioningEnv(assets []string, config pulumi.StringInput) (pulumi.StringMap, error) {
+	hash, err := hashFiles(assets...)
+	if err != nil {
+		return nil, err
+	}
+	env := pulumi.StringMap{"ANSIBLE_ASSETS_HASH": pulumi.String(hash)}
+	if config != nil {
+		env["CONFIG_HASH"] = config
+	}
+	return env, nil
+}
+
+// cluster a provisioner step runs for, created by NewK8sCluster once the servers of the cluster are requested
+type ProvisionContext struct {
+	// name of the cluster
+	Name string
+	// definition of the cluster
+	Cluster *Cluster
+	// component of the cluster, the parent of the resources of the steps
+	Component *K8sCluster
+	// known once the shared core and the servers of the cluster are created
+	Ready pulumi.ArrayOutput
+
+	ictx *infra
+}
+
+func (ictx *infra) provisionContext() *ProvisionContext {
+	return &ProvisionContext{Name: ictx.inventory.ClusterName, Cluster: ictx.cluster, Component: ictx.component, Ready: ictx.ready(), ictx: ictx}
+}
+
+// installs kubernetes on the servers, every step runs after the resources it is given
+type Provisioner interface {
+	// prepare the bastion shared by all clusters
+	PrepareBastion(ctx *pulumi.Context, infraCfg *InfraConfig, core *Core, opts ...pulumi.ResourceOption) (pulumi.Resource, error)
+	// render the configuration of a cluster and prepare its nodes
+	PrepareNodes(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error)
+	// run kubeadm init, join the other control plane nodes and install the CNI
+	Init(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error)
+	// join the workers
+	Join(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error)
+	// install the etcd backups and the charts
+	InstallAddons(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error)
+	// admin kubeconfig of a cluster
+	FetchKubeconfig(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.StringOutput, error)
+	// hosts changed, failed or unreachable by each step of a cluster
+	Summary(ctx *pulumi.Context, pctx *ProvisionContext) pulumi.MapOutput
+}
+
+// depends on the steps a provisioner created, a step without resource is nil
+func dependsOnSteps(steps []pulumi.Resource) pulumi.ResourceOption {
+	deps := make([]pulumi.Resource, 0, len(steps))
+	for _, s := range steps {
+		if s != nil {
+			deps = append(deps, s)
+		}
+	}
+	return pulumi.DependsOn(deps)
+}
+
+// provisioner running the ansible playbooks through the bastion
+type ansibleProvisioner struct {
+	// install.yaml run fetching the kubeconfig, per cluster
+	installers map[string]*local.Command
+	// hashes of the rendered inventory and of the whole configuration, per cluster
+	inventoryHashes map[string]pulumi.StringOutput
+	configHashes    map[string]pulumi.StringOutput
+	// changed, failed and unreachable hosts of each step, per cluster
+	summaries map[string]pulumi.Map
+}
+
+func NewAnsibleProvisioner() *ansibleProvisioner {
+	return &ansibleProvisioner{
+		installers:      make(map[string]*local.Command),
+		inventoryHashes: make(map[string]pulumi.StringOutput),
+		configHashes:    make(map[string]pulumi.StringOutput),
+		summaries:       make(map[string]pulumi.Map),
+	}
+}
+
+func (p *ansibleProvisioner) PrepareBastion(ctx *pulumi.Context, infraCfg *InfraConfig, core *Core, opts ...pulumi.ResourceOption) (pulumi.Resource, error) {
+	env, err := provisioningEnv(bastionAssets, nil)
+	if err != nil {
+		return nil, err
+	}
+	return local.NewCommand(ctx, "ansible-setup-bastion", &local.CommandArgs{
+		Create: pulumi.All(core.jumpServer.Networks.Index(pulumi.Int(0)).Ip(), core.jumpServer.Ipv4Address).ApplyT(
+			func(ips []interface{}) string {
+				return fmt.Sprintf("ansible-playbook --private-key ./vars/id_rsa -u %s  -i \"%s,\" -e '%s' ./.ansible/bastion-prep.yaml", infraCfg.SSHUser, ips[1].(string), bastionVars(infraCfg))
+			}).(pulumi.StringOutput),
+		Environment: env,
+	}, opts...)
+}
+
+func (p *ansibleProvisioner) PrepareNodes(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error) {
+	clusterName := pctx.Name
+	ictx := pctx.ictx
+	hashes := pctx.Ready.ApplyT(func(notUsed []interface{}) (map[string]string, error) {
+		// add common bastio
+		*ictx.inventory.Bastion = *ictx.core.bastion
+		genInventoryFile(ctx, *ictx.inventory)
+		genSecretsFile(ctx, *ictx.inventory)
+		if err := genKubeadmFiles(*ictx.inventory); err != nil {
+			return nil, err
+		}
+		genCNIFiles(ctx, *ictx.inventory)
+		if err := genRegistryFiles(*ictx.inventory); err != nil {
+			return nil, err
+		}
+		if err := genAddonFiles(*ictx.inventory); err != nil {
+			return nil, err
+		}
+		inventory, all := renderedFiles(clusterName)
+		inventoryHash, err := hashFiles(inventory...)
+		if err != nil {
+			return nil, err
+		}
+		configHash, err := hashFiles(all...)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"inventory": inventoryHash, "config": configHash}, nil
+	}).(pulumi.StringMapOutput)
+	p.inventoryHashes[clusterName] = hashes.MapIndex(pulumi.String("inventory"))
+	p.configHashes[clusterName] = hashes.MapIndex(pulumi.String("config"))
+	inv, err := local.NewCommand(ctx, fmt.Sprintf("gen-inventory-%s", clusterName), &local.CommandArgs{
+		// the files are rendered before, a change of their hash moves the inventory and the variables again
+		Create:      pulumi.String(fmt.Sprintf("mv /tmp/inventory-%s.ini ./vars/inventory-%s.ini && mv /tmp/variables-%s.yaml ./vars/variables-%s.yaml && echo \"done\"", clusterName, clusterName, clusterName, clusterName)),
+		Environment: pulumi.StringMap{"CONFIG_HASH": p.configHashes[clusterName]},
+		AssetPaths:  pulumi.ToStringArray([]string{"./vars/inventory-" + clusterName + ".ini"}),
+		Delete: pulumi.String(fmt.Sprintf("rm -rf ./vars/inventory-%s.ini ./vars/variables-%s.yaml ./vars/secrets-%s.yaml ./vars/kubeadm-%s.yaml ./vars/kubeadm-join-cp-%s.yaml ./vars/kubeadm-join-worker-%s.yaml ./vars/kubeadm-patches-%s ./vars/audit-policy-%s.yaml ./vars/encryption-%s.yaml ./vars/cni-values-%s.yaml ./vars/cilium-clustermesh-%s.yaml ./vars/registries-%s ./vars/addons-%s ./vars/events-%s-*.jsonl",
+			clusterName, clusterName, clusterName, clusterName, clusterName, clusterName, clusterName, clusterName, clusterName, clusterName, clusterName, clusterName, clusterName, clusterName)),
+	}, dependsOnSteps(dependsOn), pulumi.Parent(pctx.Component))
+	if err != nil {
+		return nil, err
+	}
+	if cloudInitBootstrap(*pctx.Cluster) || sshBootstrap(*pctx.Cluster) {
+		// the nodes route through the bastion on their own
+		return inv, nil
+	}
+	// new nodes need the route through the bastion too
+	env, err := provisioningEnv(natAssets, p.inventoryHashes[clusterName])
+	if err != nil {
+		return nil, err
+	}
+	return p.playbook(ctx, pctx, "nat", fmt.Sprintf("ansible-setup-nat-%s", clusterName), env, &local.CommandArgs{
+		Create: pulumi.String(retryCommand(fmt.Sprintf("ansible-playbook -i ./vars/inventory-%s.ini ./.ansible/bastion.yaml", clusterName), pctx.Cluster.Provisioning.Nodes)),
+	}, []pulumi.Resource{inv})
+}
+
+// playbook run of a step of a cluster, its events are logged against the cluster while it runs
+func (p *ansibleProvisioner) playbook(ctx *pulumi.Context, pctx *ProvisionContext, step string, name string, env pulumi.StringMap, args *local.CommandArgs, dependsOn []pulumi.Resource) (*local.Command, error) {
+	clusterName := pctx.Name
+	path := eventsFile(clusterName, step)
+	eventsEnv(env, path)
+	args.Environment = env
+	cmd, err := local.NewCommand(ctx, name, args, dependsOnSteps(dependsOn), pulumi.Parent(pctx.Component))
+	if err != nil {
+		return nil, err
+	}
+	var stream *eventStream
+	if !ctx.DryRun() {
+		stream = streamEvents(ctx, pctx.Component, clusterName+" "+step, path)
+		stream.stopWith(cmd.Stdout)
+	}
+	if p.summaries[clusterName] == nil {
+		p.summaries[clusterName] = pulumi.Map{}
+	}
+	// the summary of the last run, the command is done once its stdout is known
+	p.summaries[clusterName][step] = cmd.Stdout.ApplyT(func(notUsed string) (map[string]interface{}, error) {
+		if stream != nil {
+			stream.wait()
+		}
+		return readEventSummary(path)
+	}).(pulumi.MapOutput)
+	return cmd, nil
+}
+
+// install.yaml run limited to tags
+func installPlaybook(clusterName string, tags string) string {
+	return fmt.Sprintf("ansible-playbook -i ./vars/inventory-%s.ini -e \"@./vars/variables-%s.yaml\" -e \"@./vars/secrets-%s.yaml\" --tags %s ./.ansible/install.yaml", clusterName, clusterName, clusterName, tags)
+}
+
+func (p *ansibleProvisioner) Init(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error) {
+	clusterName := pctx.Name
+	tags := initTags
+	if cloudInitBootstrap(*pctx.Cluster) {
+		tags = cloudInitTags
+	} else if sshBootstrap(*pctx.Cluster) {
+		tags = sshTags
+	}
+	env, err := provisioningEnv(installAssets, p.configHashes[clusterName])
+	if err != nil {
+		return nil, err
+	}
+	installer, err := p.playbook(ctx, pctx, "init", fmt.Sprintf("ansible-k8s-installer-%s", clusterName), env, &local.CommandArgs{
+		Create: pulumi.String(retryCommand(installPlaybook(clusterName, tags), pctx.Cluster.Provisioning.Init)),
+		Delete: pulumi.String("rm -rf ./vars/cluster-" + clusterName + ".kubeconfig"),
+		AssetPaths: pulumi.ToStringArray([]string{"./vars/cluster-" + clusterName + ".kubeconfig",
+			"./vars/inventory-" + clusterName + ".ini"}),
+	}, dependsOn)
+	if err != nil {
+		return nil, err
+	}
+	p.installers[clusterName] = installer
+	return installer, nil
+}
+
+func (p *ansibleProvisioner) Join(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error) {
+	if cloudInitBootstrap(*pctx.Cluster) || sshBootstrap(*pctx.Cluster) {
+		// the workers joined while they were bootstrapped
+		return nil, nil
+	}
+	clusterName := pctx.Name
+	env, err := provisioningEnv(installAssets, p.configHashes[clusterName])
+	if err != nil {
+		return nil, err
+	}
+	return p.playbook(ctx, pctx, "join", fmt.Sprintf("ansible-k8s-join-%s", clusterName), env, &local.CommandArgs{
+		Create: pulumi.String(retryCommand(installPlaybook(clusterName, joinTags), pctx.Cluster.Provisioning.Join)),
+	}, dependsOn)
+}
+
+func (p *ansibleProvisioner) InstallAddons(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error) {
+	clusterName := pctx.Name
+	env, err := provisioningEnv(installAssets, p.configHashes[clusterName])
+	if err != nil {
+		return nil, err
+	}
+	return p.playbook(ctx, pctx, "addons", fmt.Sprintf("ansible-k8s-addons-%s", clusterName), env, &local.CommandArgs{
+		Create: pulumi.String(retryCommand(installPlaybook(clusterName, addonsTags), pctx.Cluster.Provisioning.Addons)),
+		Delete: pulumi.String("rm -rf " + releasesFile(clusterName)),
+	}, dependsOn)
+}
+
+// the kubeconfig is fetched by the init step and again by the steps renewing the certificates,
+// it is read once the steps in dependsOn are done
+func (p *ansibleProvisioner) FetchKubeconfig(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.StringOutput, error) {
+	installer, ok := p.installers[pctx.Name]
+	if !ok {
+		return pulumi.StringOutput{}, fmt.Errorf("cluster %s is not initialized", pctx.Name)
+	}
+	done := []interface{}{installer.AssetPaths}
+	for _, step := range dependsOn {
+		if res, ok := step.(pulumi.CustomResource); ok {
+			done = append(done, res.ID())
+		}
+	}
+	return pulumi.All(done...).ApplyT(func(v []interface{}) string {
+		paths := v[0].([]string)
+		kc, err := os.ReadFile(paths[0])
+		if err != nil {
+			return ""
+		}
+		return string(kc)
+	}).(pulumi.StringOutput), nil
+}
+
+func (p *ansibleProvisioner) Summary(ctx *pulumi.Context, pctx *ProvisionContext) pulumi.MapOutput {
+	summary := p.summaries[pctx.Name]
+	if summary == nil {
+		summary = pulumi.Map{}
+	}
+	return summary.ToMapOutput()
+}
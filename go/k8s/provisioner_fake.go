@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// kubeconfig of every cluster of the fake provisioner
+const fakeKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: kubernetes
+contexts:
+- context:
+    cluster: kubernetes
+    user: kubernetes-admin
+  name: kubernetes-admin@kubernetes
+current-context: kubernetes-admin@kubernetes
+users:
+- name: kubernetes-admin
+  user: {}
+`
+
+// in-memory provisioner, it creates no resources and records the steps, for deploy runs under pulumi.WithMocks
+type fakeProvisioner struct {
+	mu    sync.Mutex
+	steps []string
+	// step failing with fail, see FailStep
+	failStep string
+	fail     error
+}
+
+func NewFakeProvisioner() *fakeProvisioner {
+	return &fakeProvisioner{}
+}
+
+func (f *fakeProvisioner) record(step string, clusterName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := step + ":" + clusterName
+	f.steps = append(f.steps, name)
+	if name == f.failStep {
+		return f.fail
+	}
+	return nil
+}
+
+// step of a cluster returning err, e.g. FailStep("join", "cluster1", err), the bastion step has no cluster
+func (f *fakeProvisioner) FailStep(step string, clusterName string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failStep = step + ":" + clusterName
+	f.fail = err
+}
+
+// recorded steps as <step>:<cluster>, in call order
+func (f *fakeProvisioner) Steps() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.steps...)
+}
+
+func (f *fakeProvisioner) PrepareBastion(ctx *pulumi.Context, infraCfg *InfraConfig, core *Core, opts ...pulumi.ResourceOption) (pulumi.Resource, error) {
+	return nil, f.record("bastion", "")
+}
+
+func (f *fakeProvisioner) PrepareNodes(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error) {
+	return nil, f.record("nodes", pctx.Name)
+}
+
+func (f *fakeProvisioner) Init(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error) {
+	return nil, f.record("init", pctx.Name)
+}
+
+func (f *fakeProvisioner) Join(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error) {
+	return nil, f.record("join", pctx.Name)
+}
+
+func (f *fakeProvisioner) InstallAddons(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.Resource, error) {
+	return nil, f.record("addons", pctx.Name)
+}
+
+func (f *fakeProvisioner) FetchKubeconfig(ctx *pulumi.Context, pctx *ProvisionContext, dependsOn []pulumi.Resource) (pulumi.StringOutput, error) {
+	if err := f.record("kubeconfig", pctx.Name); err != nil {
+		return pulumi.StringOutput{}, err
+	}
+	return pulumi.String(fakeKubeconfig).ToStringOutput(), nil
+}
+
+func (f *fakeProvisioner) Summary(ctx *pulumi.Context, pctx *ProvisionContext) pulumi.MapOutput {
+	return pulumi.Map{}.ToMapOutput()
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// runs NewCore and NewK8sCluster for cluster c1 under the mocks with the fake provisioner
+func deployFake(t *testing.T, p Provisioner, m *mocks) error {
+	t.Helper()
+	c1 := Cluster{Cri: "containerd", KubernetesVersion: "1.30.2", Cni: CNIDef{Name: "flannel"}}
+	c1.ControlPlane.NodeCount = 1
+	c1.Worker.NodeCount = 1
+	_, err := deployCluster(t, p, m, c1)
+	return err
+}
+
+// runs NewCore and NewK8sCluster for cluster c1 under the mocks, it returns the entry of c1 in the clusters output
+func deployCluster(t *testing.T, p Provisioner, m *mocks, c1 Cluster) (map[string]interface{}, error) {
+	t.Helper()
+	chdirVars(t)
+	topology := &Topology{Clusters: map[string]Cluster{"c1": c1}}
+	infraCfg := &InfraConfig{WorkerFlavor: "cpx41", MasterFlavor: "cpx31", LbType: "lb11", Image: "ubuntu-22.04",
+		NetworkZone: "eu-central", DataCenter: "fsn1-dc14", SSHUser: "root"}
+	var config map[string]interface{}
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		core, err := NewCore(ctx, &CoreArgs{Infra: infraCfg, Topology: topology, Provisioner: p})
+		if err != nil {
+			return err
+		}
+		cluster, err := NewK8sCluster(ctx, "c1", &K8sClusterArgs{Cluster: c1, Core: core})
+		if err != nil {
+			return err
+		}
+		resolved := make(chan map[string]interface{}, 1)
+		cluster.Config.ApplyT(func(c map[string]interface{}) map[string]interface{} {
+			resolved <- c
+			return c
+		})
+		config = <-resolved
+		return nil
+	}, pulumi.WithMocks("project", "stack", m))
+	return config, err
+}
+
+func TestFakeProvisioner(t *testing.T) {
+	p := NewFakeProvisioner()
+	m := newMocks()
+	if err := deployFake(t, p, m); err != nil {
+		t.Fatal(err)
+	}
+	want := "bastion:,nodes:c1,init:c1,join:c1,addons:c1,kubeconfig:c1"
+	if steps := strings.Join(p.Steps(), ","); steps != want {
+		t.Errorf("steps %s, want %s", steps, want)
+	}
+	// the playbook runs of the steps are left out, the servers are still created
+	if m.resource("control-plane-c1-0") == nil || m.resource("jump-server") == nil {
+		t.Error("no servers")
+	}
+	for name := range m.inputs {
+		if strings.HasPrefix(name, "ansible-k8s-") || strings.HasPrefix(name, "gen-inventory-") {
+			t.Errorf("the fake provisioner created %s", name)
+		}
+	}
+}
+
+func TestFakeProvisionerFails(t *testing.T) {
+	p := NewFakeProvisioner()
+	p.FailStep("join", "c1", errors.New("worker unreachable"))
+	err := deployFake(t, p, newMocks())
+	if err == nil || !strings.Contains(err.Error(), "worker unreachable") {
+		t.Fatalf("error %v, want the failed join", err)
+	}
+	// the steps after the failed one do not run
+	if steps := strings.Join(p.Steps(), ","); steps != "bastion:,nodes:c1,init:c1,join:c1" {
+		t.Errorf("steps %s", steps)
+	}
+}
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wait before the first retry of a step without backoff
+const defaultBackoff = 30 * time.Second
+
+// timeout and retries of a provisioning step
+type PhaseDef struct {
+	// duration, e.g. 30m, a step is not limited without it
+	Timeout string `yaml:"timeout,omitempty"`
+	// attempts after a failed one
+	Retries int `yaml:"retries,omitempty"`
+	// wait before the first retry, doubled for every next one (default 30s)
+	Backoff string `yaml:"backoff,omitempty"`
+}
+
+// retry and timeout policy of the provisioning steps of a cluster
+type ProvisioningDef struct {
+	// route through the bastion, or the node phases of an ssh bootstrap
+	Nodes  PhaseDef `yaml:"nodes,omitempty"`
+	Init   PhaseDef `yaml:"init,omitempty"`
+	Join   PhaseDef `yaml:"join,omitempty"`
+	Addons PhaseDef `yaml:"addons,omitempty"`
+	// workers that may fail to join, in percent of the workers, the cluster is installed without them
+	MaxFailPercentage int `yaml:"max_fail_percentage,omitempty"`
+}
+
+func validatePhase(clusterName string, name string, phase PhaseDef) error {
+	if phase.Timeout != "" {
+		if d, err := time.ParseDuration(phase.Timeout); err != nil || d < time.Second {
+			return fmt.Errorf("provisioning.%s.timeout of cluster %s must be a duration of at least 1s, e.g. 30m", name, clusterName)
+		}
+	}
+	if phase.Backoff != "" {
+		// sleep takes whole seconds
+		if d, err := time.ParseDuration(phase.Backoff); err != nil || d < time.Second {
+			return fmt.Errorf("provisioning.%s.backoff of cluster %s must be a duration of at least 1s, e.g. 30s", name, clusterName)
+		}
+	}
+	if phase.Retries < 0 {
+		return fmt.Errorf("provisioning.%s.retries of cluster %s cannot be negative", name, clusterName)
+	}
+	return nil
+}
+
+func validateProvisioning(clusterName string, cluster Cluster) error {
+	p := cluster.Provisioning
+	for name, phase := range map[string]PhaseDef{"nodes": p.Nodes, "init": p.Init, "join": p.Join, "addons": p.Addons} {
+		if err := validatePhase(clusterName, name, phase); err != nil {
+			return err
+		}
+	}
+	if p.MaxFailPercentage < 0 || p.MaxFailPercentage > 100 {
+		return fmt.Errorf("provisioning.max_fail_percentage of cluster %s must be between 0 and 100", clusterName)
+	}
+	if p.MaxFailPercentage > 0 && cluster.Bootstrap != "" && cluster.Bootstrap != bootstrapAnsible {
+		return fmt.Errorf("provisioning.max_fail_percentage of cluster %s needs the ansible bootstrap, the workers join on their own otherwise", clusterName)
+	}
+	return nil
+}
+
+// a simple command run again after a failure, with the timeout on each attempt, unchanged without policy
+func retryCommand(cmd string, phase PhaseDef) string {
+	if phase.Timeout == "" && phase.Retries == 0 {
+		return cmd
+	}
+	if phase.Timeout != "" {
+		// checked by validatePhase
+		d, _ := time.ParseDuration(phase.Timeout)
+		cmd = fmt.Sprintf("timeout %d %s", int(d.Seconds()), cmd)
+	}
+	backoff := defaultBackoff
+	if phase.Backoff != "" {
+		backoff, _ = time.ParseDuration(phase.Backoff)
+	}
+	delays := []string{"0"}
+	for i := 0; i < phase.Retries; i++ {
+		delays = append(delays, strconv.Itoa(int((backoff << i).Seconds())))
+	}
+	return fmt.Sprintf("for delay in %s; do sleep $delay; %s && exit 0; done; echo \"failed after %d attempts\" >&2; exit 1",
+		strings.Join(delays, " "), cmd, len(delays))
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRetryCommand(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		phase PhaseDef
+		want  string
+	}{
+		{name: "no policy", phase: PhaseDef{}, want: "ansible-playbook install.yaml"},
+		{name: "timeout", phase: PhaseDef{Timeout: "30m"},
+			want: `for delay in 0; do sleep $delay; timeout 1800 ansible-playbook install.yaml && exit 0; done; echo "failed after 1 attempts" >&2; exit 1`},
+		{name: "default backoff", phase: PhaseDef{Retries: 3},
+			want: `for delay in 0 30 60 120; do sleep $delay; ansible-playbook install.yaml && exit 0; done; echo "failed after 4 attempts" >&2; exit 1`},
+		{name: "backoff", phase: PhaseDef{Timeout: "90s", Retries: 2, Backoff: "1m"},
+			want: `for delay in 0 60 120; do sleep $delay; timeout 90 ansible-playbook install.yaml && exit 0; done; echo "failed after 3 attempts" >&2; exit 1`},
+		// the delays are doubled before they are truncated to seconds
+		{name: "fractional backoff", phase: PhaseDef{Retries: 3, Backoff: "1500ms"},
+			want: `for delay in 0 1 3 6; do sleep $delay; ansible-playbook install.yaml && exit 0; done; echo "failed after 4 attempts" >&2; exit 1`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := retryCommand("ansible-playbook install.yaml", tc.phase); got != tc.want {
+				t.Errorf("got  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+// the command is run by local.Command with sh
+func TestRetryCommandRuns(t *testing.T) {
+	if _, err := exec.LookPath("timeout"); err != nil {
+		t.Skip("timeout is not installed")
+	}
+	marker := filepath.Join(t.TempDir(), "attempt")
+	// fails on the first attempt only
+	flaky := "test -f " + marker + " || { touch " + marker + "; false; }"
+	if out, err := exec.Command("sh", "-c", retryCommand(flaky, PhaseDef{Retries: 1, Backoff: "1s"})).CombinedOutput(); err != nil {
+		t.Errorf("the retry failed: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error(err)
+	}
+	// the timeout stops an attempt, every attempt is counted
+	out, err := exec.Command("sh", "-c", retryCommand("sleep 5", PhaseDef{Timeout: "1s"})).CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "failed after 1 attempts") {
+		t.Errorf("err = %v\n%s", err, out)
+	}
+}
+
+func TestValidateProvisioning(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		provisioning ProvisioningDef
+		bootstrap    string
+		err          string
+	}{
+		{name: "empty"},
+		{name: "policy", provisioning: ProvisioningDef{Init: PhaseDef{Timeout: "30m", Retries: 2, Backoff: "1m"}, MaxFailPercentage: 10}},
+		{name: "backoff of 1s", provisioning: ProvisioningDef{Join: PhaseDef{Retries: 1, Backoff: "1s"}}},
+		{name: "timeout", provisioning: ProvisioningDef{Init: PhaseDef{Timeout: "30"}}, err: "provisioning.init.timeout of cluster c1 must be a duration of at least 1s"},
+		{name: "short timeout", provisioning: ProvisioningDef{Nodes: PhaseDef{Timeout: "500ms"}}, err: "provisioning.nodes.timeout"},
+		{name: "backoff", provisioning: ProvisioningDef{Join: PhaseDef{Backoff: "soon"}}, err: "provisioning.join.backoff of cluster c1 must be a duration of at least 1s"},
+		// sleep 0 and sleep -5
+		{name: "short backoff", provisioning: ProvisioningDef{Join: PhaseDef{Retries: 1, Backoff: "500ms"}}, err: "provisioning.join.backoff"},
+		{name: "negative backoff", provisioning: ProvisioningDef{Addons: PhaseDef{Retries: 1, Backoff: "-5s"}}, err: "provisioning.addons.backoff"},
+		{name: "negative retries", provisioning: ProvisioningDef{Init: PhaseDef{Retries: -1}}, err: "provisioning.init.retries of cluster c1 cannot be negative"},
+		{name: "max fail percentage", provisioning: ProvisioningDef{MaxFailPercentage: 101}, err: "between 0 and 100"},
+		{name: "max fail percentage with cloud-init", provisioning: ProvisioningDef{MaxFailPercentage: 10}, bootstrap: bootstrapCloudInit, err: "needs the ansible bootstrap"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateProvisioning("c1", Cluster{Provisioning: tc.provisioning, Bootstrap: tc.bootstrap})
+			if tc.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("err = %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// upstream endpoints of the registries whose name differs from their API host
+var registryUpstreams = map[string]string{
+	"docker.io": "https://registry-1.docker.io",
+}
+
+type RegistriesDef struct {
+	// keyed by registry host, e.g. harbor.example.com, docker.io, ghcr.io, registry.k8s.io
+	Hosts map[string]RegistryDef `yaml:"hosts,omitempty"`
+	// namespaces whose default service account gets the imagePullSecret, defaults to default
+	PullSecretNamespaces []string `yaml:"pull_secret_namespaces,omitempty"`
+}
+
+type RegistryDef struct {
+	// CA bundle of the registry, file name in ./vars
+	CAFile string `yaml:"ca_file,omitempty"`
+	// plain http or unverified TLS
+	Insecure bool `yaml:"insecure,omitempty"`
+	// pull-through mirror endpoints, tried in order before the registry itself
+	Mirrors []string `yaml:"mirrors,omitempty"`
+}
+
+// registry credentials, read from the `registries` pulumi secret config
+type RegistryCredentials struct {
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// registry entry as rendered into the variables file
+type RegistryEntry struct {
+	Host     string
+	Insecure bool
+	Mirrors  []string
+	CA       bool
+}
+
+// registries of the cluster with the insecure_registries folded in
+func clusterRegistries(cluster Cluster) map[string]RegistryDef {
+	registries := make(map[string]RegistryDef)
+	for host, r := range cluster.Registries.Hosts {
+		registries[host] = r
+	}
+	for _, host := range cluster.InsecureRegistries {
+		r := registries[host]
+		r.Insecure = true
+		registries[host] = r
+	}
+	airGapMirrors(cluster, registries)
+	return registries
+}
+
+// registries sorted by host, for the templates
+func (inv Inventory) RegistryEntries() []RegistryEntry {
+	hosts := make([]string, 0, len(inv.Registries))
+	for host := range inv.Registries {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	entries := make([]RegistryEntry, 0, len(hosts))
+	for _, host := range hosts {
+		r := inv.Registries[host]
+		entries = append(entries, RegistryEntry{Host: host, Insecure: r.Insecure, Mirrors: r.Mirrors, CA: r.CAFile != ""})
+	}
+	return entries
+}
+
+func validateRegistries(registries RegistriesDef) error {
+	for host, r := range registries.Hosts {
+		if strings.Contains(host, "/") {
+			return fmt.Errorf("registry %q must be a host name without scheme or path", host)
+		}
+		if r.CAFile != "" {
+			if _, err := os.Stat(filepath.Join("./vars", r.CAFile)); err != nil {
+				return fmt.Errorf("registries.hosts.%s.ca_file %s not found in ./vars: %w", host, r.CAFile, err)
+			}
+		}
+		for _, m := range r.Mirrors {
+			u, err := url.Parse(m)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+				return fmt.Errorf("mirror %q of registry %s must be an http or https URL", m, host)
+			}
+		}
+	}
+	return nil
+}
+
+// look up the credentials of the cluster registries, they must be listed in the topology
+func setupRegistries(infraCfg *InfraConfig, ictx *infra, clusterName string) error {
+	creds := infraCfg.Registries[clusterName]
+	for host, c := range creds {
+		if _, ok := ictx.inventory.Registries[host]; !ok {
+			return fmt.Errorf("the pulumi config has credentials for registry %s which is not in registries.hosts of cluster %s", host, clusterName)
+		}
+		if c.Username == "" || c.Password == "" {
+			return fmt.Errorf("registries.%s.%s needs a username and password", clusterName, host)
+		}
+	}
+	ictx.inventory.RegistryCredentials = creds
+	return nil
+}
+
+// credentials in the docker config.json format, used by the kubelet and the imagePullSecret
+func dockerConfigJSON(creds map[string]RegistryCredentials) (string, error) {
+	auths := make(map[string]interface{})
+	for host, c := range creds {
+		auths[host] = map[string]string{
+			"username": c.Username,
+			"password": c.Password,
+			"auth":     base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password)),
+		}
+	}
+	out, err := json.Marshal(map[string]interface{}{"auths": auths})
+	return string(out), err
+}
+
+func hostURL(host string) string {
+	if upstream, ok := registryUpstreams[host]; ok {
+		return upstream
+	}
+	return "https://" + host
+}
+
+// containerd hosts.toml of a registry
+func hostsToml(host string, r RegistryDef, registries map[string]RegistryDef) string {
+	var sb strings.Builder
+	server := hostURL(host)
+	if r.Insecure {
+		server = "http://" + host
+	}
+	fmt.Fprintf(&sb, "server = %q\n", server)
+	for _, m := range r.Mirrors {
+		u, _ := url.Parse(m)
+		if path := strings.TrimSuffix(u.Path, "/"); path != "" {
+			// mirror under a path of its registry, containerd must not append /v2 itself
+			if path != "/v2" && !strings.HasPrefix(path, "/v2/") {
+				path = "/v2" + path
+			}
+			fmt.Fprintf(&sb, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n  override_path = true\n", u.Scheme+"://"+u.Host+path)
+		} else {
+			fmt.Fprintf(&sb, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", m)
+		}
+		if mr, ok := registries[u.Host]; ok {
+			if mr.CAFile != "" {
+				fmt.Fprintf(&sb, "  ca = %q\n", fmt.Sprintf("/etc/containerd/certs.d/%s/ca.crt", u.Host))
+			}
+			if mr.Insecure {
+				sb.WriteString("  skip_verify = true\n")
+			}
+		}
+	}
+	if r.Insecure {
+		// unverified TLS first, plain http as fallback through server
+		fmt.Fprintf(&sb, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\", \"push\"]\n  skip_verify = true\n", "https://"+host)
+	} else if r.CAFile != "" {
+		fmt.Fprintf(&sb, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\", \"push\"]\n  ca = %q\n", server, fmt.Sprintf("/etc/containerd/certs.d/%s/ca.crt", host))
+	}
+	return sb.String()
+}
+
+// hosts.toml and CA bundle of every registry, in ./vars/registries-<cluster>/<host>
+func genRegistryFiles(inv Inventory) error {
+	dir := fmt.Sprintf("./vars/registries-%s", inv.ClusterName)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for host, r := range inv.Registries {
+		hostDir := filepath.Join(dir, host)
+		if err := os.MkdirAll(hostDir, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(hostDir, "hosts.toml"), []byte(hostsToml(host, r, inv.Registries)), 0644); err != nil {
+			return err
+		}
+		if r.CAFile != "" {
+			ca, err := os.ReadFile(filepath.Join("./vars", r.CAFile))
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(hostDir, "ca.crt"), ca, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"os"
+	"testing"
+)
+
+func TestHostsToml(t *testing.T) {
+	registries := map[string]RegistryDef{
+		"docker.io":            {Mirrors: []string{"https://mirror.example.com"}},
+		"ghcr.io":              {Mirrors: []string{"https://harbor.example.com/proxy-ghcr/"}},
+		"quay.io":              {Mirrors: []string{"https://harbor.example.com/v2/proxy-quay", "https://insecure.example.com"}},
+		"harbor.example.com":   {CAFile: "harbor-ca.crt"},
+		"insecure.example.com": {Insecure: true},
+		"registry.k8s.io":      {},
+	}
+	for _, tc := range []struct {
+		host string
+		want string
+	}{
+		// docker.io is served by registry-1.docker.io
+		{"docker.io", `server = "https://registry-1.docker.io"
+
+[host."https://mirror.example.com"]
+  capabilities = ["pull", "resolve"]
+`},
+		// a mirror under a path gets /v2 in front of it unless it has it already, containerd must not append it, and the CA of its registry
+		{"ghcr.io", `server = "https://ghcr.io"
+
+[host."https://harbor.example.com/v2/proxy-ghcr"]
+  capabilities = ["pull", "resolve"]
+  override_path = true
+  ca = "/etc/containerd/certs.d/harbor.example.com/ca.crt"
+`},
+		{"quay.io", `server = "https://quay.io"
+
+[host."https://harbor.example.com/v2/proxy-quay"]
+  capabilities = ["pull", "resolve"]
+  override_path = true
+  ca = "/etc/containerd/certs.d/harbor.example.com/ca.crt"
+
+[host."https://insecure.example.com"]
+  capabilities = ["pull", "resolve"]
+  skip_verify = true
+`},
+		{"harbor.example.com", `server = "https://harbor.example.com"
+
+[host."https://harbor.example.com"]
+  capabilities = ["pull", "resolve", "push"]
+  ca = "/etc/containerd/certs.d/harbor.example.com/ca.crt"
+`},
+		// unverified TLS first, plain http as the fallback
+		{"insecure.example.com", `server = "http://insecure.example.com"
+
+[host."https://insecure.example.com"]
+  capabilities = ["pull", "resolve", "push"]
+  skip_verify = true
+`},
+		{"registry.k8s.io", `server = "https://registry.k8s.io"
+`},
+	} {
+		t.Run(tc.host, func(t *testing.T) {
+			if got := hostsToml(tc.host, registries[tc.host], registries); got != tc.want {
+				t.Errorf("hosts.toml:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenRegistryFiles(t *testing.T) {
+	chdirVars(t)
+	if err := os.WriteFile("./vars/harbor-ca.crt", []byte("harbor ca"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	inv := *testCluster(&Cluster{}).inventory
+	inv.Registries = map[string]RegistryDef{"harbor.example.com": {CAFile: "harbor-ca.crt"}, "docker.io": {}}
+	if err := genRegistryFiles(inv); err != nil {
+		t.Fatal(err)
+	}
+	if ca, err := os.ReadFile("./vars/registries-c1/harbor.example.com/ca.crt"); err != nil || string(ca) != "harbor ca" {
+		t.Errorf("ca.crt %q: %v", ca, err)
+	}
+	if _, err := os.Stat("./vars/registries-c1/docker.io/ca.crt"); err == nil {
+		t.Error("docker.io has a CA")
+	}
+	// the directory holds only the registries of the last rendering, install.yaml removes the others from the nodes
+	delete(inv.Registries, "harbor.example.com")
+	if err := genRegistryFiles(inv); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir("./vars/registries-c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "docker.io" {
+		t.Errorf("registries %v", entries)
+	}
+}
@@ -0,0 +1,243 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var releaseNameRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// helm release installed after the add-ons
+type ReleaseDef struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+	// chart repository URL, empty when chart is an oci:// reference
+	Repo    string `yaml:"repo,omitempty"`
+	Chart   string `yaml:"chart"`
+	Version string `yaml:"version,omitempty"`
+	// helm values files, file names in ./vars, applied in order
+	ValuesFiles []string `yaml:"values_files,omitempty"`
+	// add-ons, releases or manifests installed before this release
+	Needs []string `yaml:"needs,omitempty"`
+}
+
+// directory of plain manifests applied after the add-ons
+type ManifestDef struct {
+	Name string `yaml:"name"`
+	// directory in ./vars, applied recursively
+	Dir   string   `yaml:"dir"`
+	Needs []string `yaml:"needs,omitempty"`
+}
+
+// add-on, release or manifest directory, in install order
+type InstallStep struct {
+	Kind string
+	Name string
+}
+
+// status of a helm release as reported by helm list
+type ReleaseStatus struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Revision   string `json:"revision"`
+	Updated    string `json:"updated"`
+	Status     string `json:"status"`
+	Chart      string `json:"chart"`
+	AppVersion string `json:"app_version"`
+}
+
+func releasesFile(clusterName string) string {
+	return fmt.Sprintf("./vars/releases-%s.json", clusterName)
+}
+
+// order the add-ons, releases and manifests by their needs, keeping the declared order otherwise
+func installSteps(addons []string, releases []ReleaseDef, manifests []ManifestDef) ([]InstallStep, error) {
+	var steps []InstallStep
+	needs := make(map[string][]string)
+	for _, name := range addons {
+		steps = append(steps, InstallStep{Kind: "release", Name: name})
+	}
+	for _, r := range releases {
+		steps = append(steps, InstallStep{Kind: "release", Name: r.Name})
+		needs[r.Name] = r.Needs
+	}
+	for _, m := range manifests {
+		steps = append(steps, InstallStep{Kind: "manifests", Name: m.Name})
+		needs[m.Name] = m.Needs
+	}
+	known := make(map[string]bool)
+	for _, s := range steps {
+		if known[s.Name] {
+			return nil, fmt.Errorf("release or manifests name %s is used more than once", s.Name)
+		}
+		known[s.Name] = true
+	}
+	for name, deps := range needs {
+		for _, d := range deps {
+			if !known[d] {
+				return nil, fmt.Errorf("%s needs %s, which is neither an enabled add-on, a release nor a manifests entry", name, d)
+			}
+		}
+	}
+	ordered := make([]InstallStep, 0, len(steps))
+	done := make(map[string]bool)
+	for len(ordered) < len(steps) {
+		progress := false
+		for _, s := range steps {
+			if done[s.Name] {
+				continue
+			}
+			ready := true
+			for _, d := range needs[s.Name] {
+				if !done[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, s)
+				done[s.Name] = true
+				progress = true
+				break
+			}
+		}
+		if !progress {
+			var pending []string
+			for _, s := range steps {
+				if !done[s.Name] {
+					pending = append(pending, s.Name)
+				}
+			}
+			return nil, fmt.Errorf("the needs of %v form a cycle", pending)
+		}
+	}
+	return ordered, nil
+}
+
+func validateReleases(clusterName string, cluster Cluster) error {
+	if cluster.AirGapped && len(cluster.Releases) > 0 {
+		return fmt.Errorf("air_gapped cluster %s does not support releases, their charts are not in the bundle", clusterName)
+	}
+	for _, r := range cluster.Releases {
+		if !releaseNameRe.MatchString(r.Name) {
+			return fmt.Errorf("release name %q must be a lowercase DNS label", r.Name)
+		}
+		if r.Namespace == "" || r.Chart == "" {
+			return fmt.Errorf("release %s needs a namespace and a chart", r.Name)
+		}
+		for _, f := range r.ValuesFiles {
+			if _, err := os.Stat(filepath.Join("./vars", f)); err != nil {
+				return fmt.Errorf("values file %s of release %s not found in ./vars: %w", f, r.Name, err)
+			}
+		}
+	}
+	for _, m := range cluster.Manifests {
+		if !releaseNameRe.MatchString(m.Name) {
+			return fmt.Errorf("manifests name %q must be a lowercase DNS label", m.Name)
+		}
+		if info, err := os.Stat(filepath.Join("./vars", m.Dir)); err != nil || !info.IsDir() {
+			return fmt.Errorf("manifests %s: %s is not a directory in ./vars", m.Name, m.Dir)
+		}
+	}
+	inv := Inventory{Addons: cluster.Addons, Releases: cluster.Releases, Manifests: cluster.Manifests, GitOps: cluster.GitOps, Worker: cluster.Worker}
+	_, err := inv.installSteps()
+	return err
+}
+
+// the built-in add-ons, the autoscaler and the gitops engine come first, the root sync object last
+func (inv Inventory) installSteps() ([]InstallStep, error) {
+	builtins := append(append(inv.AddonCharts(), autoscalerReleases(inv.Worker)...), gitopsReleases(inv.GitOps)...)
+	manifests := append(append([]ManifestDef{}, inv.Manifests...), gitopsManifests(inv.GitOps)...)
+	return installSteps(builtins, inv.Releases, manifests)
+}
+
+// add-ons, releases and manifests in install order, for the templates
+func (inv Inventory) InstallSteps() []InstallStep {
+	// the order is checked by validateReleases
+	steps, _ := inv.installSteps()
+	return steps
+}
+
+// helmfile releases of the user releases, their values are copied next to the helmfile
+func userReleases(dir string, releases []ReleaseDef) ([]helmfileRepository, []helmfileRelease, error) {
+	var repos []helmfileRepository
+	var out []helmfileRelease
+	for _, r := range releases {
+		release := helmfileRelease{Name: r.Name, Namespace: r.Namespace, Chart: r.Chart, Version: r.Version}
+		if r.Repo != "" {
+			// one repository per release, so it cannot clash with an add-on repository
+			repos = append(repos, helmfileRepository{Name: r.Name + "-repo", URL: r.Repo})
+			release.Chart = r.Name + "-repo/" + r.Chart
+		}
+		for i, f := range r.ValuesFiles {
+			content, err := os.ReadFile(filepath.Join("./vars", f))
+			if err != nil {
+				return nil, nil, err
+			}
+			name := fmt.Sprintf("%s-%d-%s", r.Name, i, filepath.Base(f))
+			if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+				return nil, nil, err
+			}
+			release.Values = append(release.Values, "/tmp/addons/"+name)
+		}
+		out = append(out, release)
+	}
+	return repos, out, nil
+}
+
+// copy the manifest directories to <dir>/manifests/<name>
+func copyManifests(dir string, manifests []ManifestDef) error {
+	for _, m := range manifests {
+		src := filepath.Join("./vars", m.Dir)
+		dst := filepath.Join(dir, "manifests", m.Name)
+		err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(src, path)
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return os.MkdirAll(filepath.Join(dst, rel), 0755)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(dst, rel), content, 0644)
+		})
+		if err != nil {
+			return fmt.Errorf("manifests %s: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// release status fetched by the installer, keyed by release name
+func readReleaseStatus(clusterName string) (map[string]interface{}, error) {
+	content, err := os.ReadFile(releasesFile(clusterName))
+	if err != nil {
+		return nil, err
+	}
+	var list []ReleaseStatus
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", releasesFile(clusterName), err)
+	}
+	status := make(map[string]interface{}, len(list))
+	for _, r := range list {
+		status[r.Name] = map[string]interface{}{
+			"namespace":  r.Namespace,
+			"revision":   r.Revision,
+			"updated":    r.Updated,
+			"status":     r.Status,
+			"chart":      r.Chart,
+			"appVersion": r.AppVersion,
+		}
+	}
+	return status, nil
+}
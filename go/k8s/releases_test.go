@@ -0,0 +1,127 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func stepNames(steps []InstallStep) string {
+	names := make([]string, 0, len(steps))
+	for _, s := range steps {
+		names = append(names, s.Kind+":"+s.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestInstallSteps(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		addons    []string
+		releases  []ReleaseDef
+		manifests []ManifestDef
+		want      string
+		err       string
+	}{
+		{name: "declared order", addons: []string{"metrics-server", "cert-manager"},
+			releases:  []ReleaseDef{{Name: "app"}, {Name: "db"}},
+			manifests: []ManifestDef{{Name: "config"}},
+			want:      "release:metrics-server,release:cert-manager,release:app,release:db,manifests:config"},
+		{name: "release needs manifests", addons: []string{"cert-manager"},
+			releases:  []ReleaseDef{{Name: "app", Needs: []string{"issuers"}}},
+			manifests: []ManifestDef{{Name: "issuers", Needs: []string{"cert-manager"}}},
+			want:      "release:cert-manager,manifests:issuers,release:app"},
+		{name: "chain", releases: []ReleaseDef{{Name: "app", Needs: []string{"db"}}, {Name: "db", Needs: []string{"crds"}}, {Name: "cache"}},
+			manifests: []ManifestDef{{Name: "crds"}},
+			want:      "release:cache,manifests:crds,release:db,release:app"},
+		{name: "diamond", releases: []ReleaseDef{{Name: "d", Needs: []string{"b", "c"}}, {Name: "b", Needs: []string{"a"}}, {Name: "c", Needs: []string{"a"}}, {Name: "a"}},
+			want: "release:a,release:b,release:c,release:d"},
+		{name: "unknown need", releases: []ReleaseDef{{Name: "app", Needs: []string{"ingress-nginx"}}},
+			err: "app needs ingress-nginx, which is neither an enabled add-on, a release nor a manifests entry"},
+		{name: "duplicate", addons: []string{"cert-manager"}, releases: []ReleaseDef{{Name: "cert-manager"}},
+			err: "name cert-manager is used more than once"},
+		{name: "cycle", releases: []ReleaseDef{{Name: "a", Needs: []string{"b"}}, {Name: "b", Needs: []string{"a"}}, {Name: "c"}},
+			err: "the needs of [a b] form a cycle"},
+		{name: "self", manifests: []ManifestDef{{Name: "m", Needs: []string{"m"}}},
+			err: "the needs of [m] form a cycle"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			steps, err := installSteps(tc.addons, tc.releases, tc.manifests)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("error %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := stepNames(steps); got != tc.want {
+				t.Errorf("steps %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateReleases(t *testing.T) {
+	chdirVars(t)
+	if err := os.Mkdir("./vars/manifests", 0755); err != nil {
+		t.Fatal(err)
+	}
+	app := ReleaseDef{Name: "app", Namespace: "apps", Chart: "oci://ghcr.io/example/app"}
+	for _, tc := range []struct {
+		cluster Cluster
+		err     string
+	}{
+		{Cluster{Releases: []ReleaseDef{app}, Manifests: []ManifestDef{{Name: "config", Dir: "manifests", Needs: []string{"app"}}}}, ""},
+		{Cluster{Releases: []ReleaseDef{{Name: "App", Namespace: "apps", Chart: "app"}}}, "must be a lowercase DNS label"},
+		{Cluster{Releases: []ReleaseDef{{Name: "app", Chart: "app"}}}, "needs a namespace and a chart"},
+		{Cluster{Releases: []ReleaseDef{{Name: "app", Namespace: "apps", Chart: "app", ValuesFiles: []string{"app.yaml"}}}}, "values file app.yaml of release app not found"},
+		{Cluster{Manifests: []ManifestDef{{Name: "config", Dir: "missing"}}}, "missing is not a directory"},
+		{Cluster{Releases: []ReleaseDef{app}, AirGapped: true}, "does not support releases"},
+		// the needs are checked against the built-in add-ons
+		{Cluster{Releases: []ReleaseDef{{Name: "app", Namespace: "apps", Chart: "app", Needs: []string{"cert-manager"}}}}, ""},
+		{Cluster{Releases: []ReleaseDef{{Name: "app", Namespace: "apps", Chart: "app", Needs: []string{"app"}}}}, "form a cycle"},
+	} {
+		err := validateReleases("c1", tc.cluster)
+		if tc.err == "" && err != nil || tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%+v: error %v, want %q", tc.cluster, err, tc.err)
+		}
+	}
+}
+
+func TestReadReleaseStatus(t *testing.T) {
+	chdirVars(t)
+	if _, err := readReleaseStatus("c1"); err == nil {
+		t.Error("no error without the status file")
+	}
+	// helm list -o json
+	list := `[{"name":"app","namespace":"apps","revision":"3","updated":"2026-10-19 10:00:00.0 +0000 UTC","status":"deployed","chart":"app-1.2.0","app_version":"1.2"},
+{"name":"db","namespace":"apps","revision":"1","updated":"2026-10-19 09:00:00.0 +0000 UTC","status":"failed","chart":"db-0.1.0","app_version":"16"}]`
+	if err := os.WriteFile(releasesFile("c1"), []byte(list), 0644); err != nil {
+		t.Fatal(err)
+	}
+	status, err := readReleaseStatus("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"app": "map[appVersion:1.2 chart:app-1.2.0 namespace:apps revision:3 status:deployed updated:2026-10-19 10:00:00.0 +0000 UTC]",
+		"db":  "map[appVersion:16 chart:db-0.1.0 namespace:apps revision:1 status:failed updated:2026-10-19 09:00:00.0 +0000 UTC]",
+	}
+	if len(status) != len(want) {
+		t.Errorf("status %v", status)
+	}
+	for name, s := range want {
+		if got := fmt.Sprint(status[name]); got != s {
+			t.Errorf("%s: %s, want %s", name, got, s)
+		}
+	}
+	if err := os.WriteFile(releasesFile("c1"), []byte("Error: Kubernetes cluster unreachable"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readReleaseStatus("c1"); err == nil || !strings.Contains(err.Error(), "cannot parse") {
+		t.Errorf("error %v", err)
+	}
+}
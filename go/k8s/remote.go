@@ -0,0 +1,201 @@
+package k8s
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pulumi/pulumi-command/sdk/go/command/remote"
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+const bootstrapSSH = "ssh"
+
+// install.yaml tags of the init step after an ssh bootstrap, the CNI is only installed by install.yaml
+const sshTags = "cni,pullsecret"
+
+// bootstrap-node.sh phases run before kubeadm, each one is a remote command per node
+var preparePhases = []string{"network", "system", "packages", "runtime"}
+
+// a node bootstrapped over ssh
+type sshNode struct {
+	name   string
+	server *hcloud.Server
+	role   string
+	nat    bool
+}
+
+func sshBootstrap(cluster Cluster) bool {
+	return cluster.Bootstrap == bootstrapSSH
+}
+
+// ssh connection to the private IP of a node, through the bastion
+func nodeConnection(infraCfg *InfraConfig, ictx *infra, server *hcloud.Server) remote.ConnectionArgs {
+	return remote.ConnectionArgs{
+		Host:       server.Networks.Index(pulumi.Int(0)).Ip().Elem(),
+		User:       pulumi.String(infraCfg.SSHUser),
+		PrivateKey: ictx.core.privateKey.PrivateKeyOpenssh,
+		// new servers take a while to accept ssh
+		DialErrorLimit: pulumi.Int(60),
+		Proxy: remote.ProxyConnectionArgs{
+			Host:           ictx.core.jumpServer.Ipv4Address,
+			User:           pulumi.String("root"),
+			PrivateKey:     ictx.core.privateKey.PrivateKeyOpenssh,
+			DialErrorLimit: pulumi.Int(60),
+		},
+	}
+}
+
+func sudo(infraCfg *InfraConfig) string {
+	if infraCfg.SSHUser == "root" {
+		return ""
+	}
+	return "sudo "
+}
+
+// shell script writing the files of a node, read from stdin by bash
+func filesScript(files []cloudConfigFile) string {
+	var sb strings.Builder
+	sb.WriteString("set -eu\numask 077\n")
+	for _, f := range files {
+		fmt.Fprintf(&sb, "mkdir -p %s\necho '%s' | base64 -d > %s\nchmod %s %s\n", filepath.Dir(f.Path), f.Content, f.Path, f.Permissions, f.Path)
+	}
+	return sb.String()
+}
+
+// nodes of a cluster bootstrapped by cloud-init or ssh, the first control plane node runs kubeadm init
+func bootstrapNodes(ictx *infra, clusterName string) []sshNode {
+	nodes := make([]sshNode, 0, len(ictx.cpNodes)+len(ictx.workerNodes))
+	for i, server := range ictx.cpNodes {
+		role := roleControlPlane
+		if i == 0 {
+			role = roleInit
+		}
+		// the control plane nodes only have a public IP without load balancer
+		nodes = append(nodes, sshNode{fmt.Sprintf("control-plane-%s-%d", clusterName, i), server, role, ictx.loadBal != nil})
+	}
+	for i, server := range ictx.workerNodes {
+		nodes = append(nodes, sshNode{fmt.Sprintf("worker-%s-%d", clusterName, i), server, roleWorker, true})
+	}
+	return nodes
+}
+
+// upload the keys and credentials of the nodes of a cloud-init cluster, bootstrap-node.sh waits for them before kubeadm
+func setupCloudInitSecrets(ctx *pulumi.Context, infraCfg *InfraConfig, ictx *infra, clusterName string, pulumik8sCluster *K8sCluster) error {
+	if !cloudInitBootstrap(*ictx.cluster) {
+		return nil
+	}
+	rendered := ictx.ready()
+	ictx.bootstrapSteps = make([]pulumi.Resource, 0)
+	for _, node := range bootstrapNodes(ictx, clusterName) {
+		node := node
+		if node.role == roleWorker && len(ictx.inventory.RegistryCredentials) == 0 {
+			// nothing to wait for
+			continue
+		}
+		secrets, err := remote.NewCommand(ctx, "ssh-secrets-"+node.name, &remote.CommandArgs{
+			Connection: nodeConnection(infraCfg, ictx, node.server),
+			Create:     pulumi.String(sudo(infraCfg) + "bash -s"),
+			Stdin: pulumi.ToSecret(rendered.ApplyT(func(notUsed []interface{}) (string, error) {
+				files, err := nodeSecretFiles(*ictx.inventory, node.role)
+				if err != nil {
+					return "", err
+				}
+				return filesScript(files), nil
+			})).(pulumi.StringOutput),
+		}, dependsOnSteps([]pulumi.Resource{ictx.core.bastionSetup}), pulumi.Parent(pulumik8sCluster))
+		if err != nil {
+			return err
+		}
+		ictx.bootstrapSteps = append(ictx.bootstrapSteps, secrets)
+	}
+	return nil
+}
+
+// bootstrap the nodes over ssh, with one remote command per node and phase, so a failed phase is all a re-run repeats
+func setupSSHBootstrap(ctx *pulumi.Context, infraCfg *InfraConfig, ictx *infra, clusterName string, pulumik8sCluster *K8sCluster) error {
+	if !sshBootstrap(*ictx.cluster) {
+		return nil
+	}
+	nodes := bootstrapNodes(ictx, clusterName)
+	// the files are rendered once the PKI, the load balancer and the node IPs are known
+	rendered := ictx.ready()
+	inv := *ictx.inventory
+	tokenID := ictx.pki.tokenID.Result
+	token := pulumi.All(ictx.pki.tokenID.Result, ictx.pki.tokenSecret.Result).ApplyT(func(v []interface{}) string {
+		return v[0].(string) + "." + v[1].(string)
+	}).(pulumi.StringOutput)
+
+	var initConn remote.ConnectionArgs
+	var initStep pulumi.Resource
+	ictx.bootstrapSteps = make([]pulumi.Resource, 0)
+	for _, node := range nodes {
+		conn := nodeConnection(infraCfg, ictx, node.server)
+		files, err := remote.NewCommand(ctx, "ssh-files-"+node.name, &remote.CommandArgs{
+			Connection: conn,
+			Create:     pulumi.String(sudo(infraCfg) + "bash -s"),
+			// the control plane files hold the CA keys
+			Stdin: pulumi.ToSecret(rendered.ApplyT(func(notUsed []interface{}) (string, error) {
+				files, err := nodeFiles(*ictx.inventory, node.role)
+				if err != nil {
+					return "", err
+				}
+				secrets, err := nodeSecretFiles(*ictx.inventory, node.role)
+				if err != nil {
+					return "", err
+				}
+				return filesScript(append(files, secrets...)), nil
+			})).(pulumi.StringOutput),
+		}, dependsOnSteps([]pulumi.Resource{ictx.core.bastionSetup}), pulumi.Parent(pulumik8sCluster))
+		if err != nil {
+			return err
+		}
+		prev := pulumi.Resource(files)
+		for _, phase := range preparePhases {
+			step, err := remote.NewCommand(ctx, fmt.Sprintf("ssh-%s-%s", phase, node.name), &remote.CommandArgs{
+				Connection: conn,
+				Create:     pulumi.String(retryCommand(sudo(infraCfg)+strings.Join(bootstrapArgs(inv, node.role, node.nat, phase), " "), ictx.cluster.Provisioning.Nodes)),
+			}, pulumi.DependsOn([]pulumi.Resource{prev}), pulumi.Parent(pulumik8sCluster))
+			if err != nil {
+				return err
+			}
+			prev = step
+		}
+		deps := []pulumi.Resource{prev}
+		if node.role != roleInit {
+			// the bootstrap token expires after 24h, nodes added later need a new one
+			refresh, err := remote.NewCommand(ctx, "ssh-token-"+node.name, &remote.CommandArgs{
+				Connection: initConn,
+				Create: pulumi.All(tokenID, token).ApplyT(func(v []interface{}) string {
+					return fmt.Sprintf("%skubeadm token list | grep -q '^%s' || %skubeadm token create %s --ttl 24h", sudo(infraCfg), v[0].(string), sudo(infraCfg), v[1].(string))
+				}).(pulumi.StringOutput),
+				Logging: remote.LoggingStderr,
+			}, pulumi.DependsOn([]pulumi.Resource{initStep}), pulumi.Parent(pulumik8sCluster))
+			if err != nil {
+				return err
+			}
+			deps = append(deps, refresh)
+		}
+		if node.role == roleControlPlane {
+			// one etcd member joins at a time
+			deps = append(deps, ictx.bootstrapSteps[len(ictx.bootstrapSteps)-1])
+		}
+		policy := ictx.cluster.Provisioning.Join
+		if node.role == roleInit {
+			policy = ictx.cluster.Provisioning.Init
+		}
+		join, err := remote.NewCommand(ctx, "ssh-kubeadm-"+node.name, &remote.CommandArgs{
+			Connection: conn,
+			Create:     pulumi.String(retryCommand(sudo(infraCfg)+strings.Join(bootstrapArgs(inv, node.role, node.nat, "kubeadm"), " "), policy)),
+		}, pulumi.DependsOn(deps), pulumi.Parent(pulumik8sCluster))
+		if err != nil {
+			return err
+		}
+		if node.role == roleInit {
+			initConn, initStep = conn, join
+		}
+		ictx.bootstrapSteps = append(ictx.bootstrapSteps, join)
+	}
+	return nil
+}
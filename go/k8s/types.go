@@ -0,0 +1,247 @@
+package k8s
+
+import (
+	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
+	"github.com/pulumi/pulumi-tls/sdk/v5/go/tls"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// kubernetes cluster on hetzner cloud, created with NewK8sCluster
+type K8sCluster struct {
+	pulumi.ResourceState
+
+	// admin kubeconfig, pointing to the load balancer or the first control plane node
+	Kubeconfig pulumi.StringOutput `pulumi:"kubeconfig"`
+	// API server URL
+	APIServer pulumi.StringOutput `pulumi:"apiServer"`
+	// public addresses of the API server (cluster-api) and the node ports (app), and their type
+	Endpoints pulumi.StringMapOutput `pulumi:"endpoints"`
+	// private IPs of the nodes, in creation order
+	ControlPlaneNodes pulumi.StringArrayOutput `pulumi:"controlPlaneNodes"`
+	WorkerNodes       pulumi.StringArrayOutput `pulumi:"workerNodes"`
+	// hosts changed, failed or unreachable by each provisioning step
+	Provisioning pulumi.MapOutput `pulumi:"provisioning"`
+	// entry of the cluster in the clusters stack output: kubeconfig, endpoints, PKI, certificates...
+	Config pulumi.MapOutput `pulumi:"config"`
+}
+
+// inputs of a cluster, the settings of a cluster of the topology and the infrastructure it runs on
+type K8sClusterArgs struct {
+	Cluster
+	// server types, image, location and credentials, defaults to the ones of the core
+	Infra *InfraConfig
+	// network and bastion shared with the other clusters, created with NewCore or NewBastion
+	Core *Core
+}
+
+// server types, image, location and credentials of the clusters
+type InfraConfig struct {
+	WorkerFlavor string
+	MasterFlavor string
+	LbType       string
+	Image        string
+	NetworkZone  string
+	DataCenter   string
+	SSHUser      string
+	// credentials by cluster name
+	Backups     map[string]BackupCredentials
+	Registries  map[string]map[string]RegistryCredentials
+	GitOps      map[string]GitOpsCredentials
+	HcloudToken string
+	// time servers of the bastion, the distribution defaults when unset. The nodes use the ntp of their cluster
+	Ntp NtpDef
+}
+
+// inputs of the infrastructure shared by the clusters
+type CoreArgs struct {
+	Infra *InfraConfig
+	// clusters on the network, their pod CIDRs and cluster mesh IDs are checked against each other and the mesh clusters get the mesh CA
+	Topology *Topology
+	// installs kubernetes on the servers, defaults to the ansible playbooks
+	Provisioner Provisioner
+}
+
+// network, firewalls, ssh key and bastion shared by the clusters, created with NewCore or NewNetwork and NewBastion
+type Core struct {
+	privateKey         *tls.PrivateKey
+	sshKey             *hcloud.SshKey
+	network            *hcloud.Network
+	subnet             *hcloud.NetworkSubnet
+	jumpServerFirewall *hcloud.Firewall
+	jumpServer         *hcloud.Server
+	bastion            *Node
+	bastionSetup       pulumi.Resource
+	// outputs filling in the bastion, every cluster waits for them
+	waitFor []pulumi.Output
+
+	infraCfg *InfraConfig
+	topology *Topology
+	// clusters in the cilium cluster mesh, it is connected once they are all created
+	mesh        []string
+	provisioner Provisioner
+	// cilium cluster mesh CA, and the members created so far
+	meshCA      *certAuthority
+	meshMembers []*infra
+	// clusters created so far, by name
+	clusters map[string]Cluster
+}
+
+// clusters on the network other than name, the ones of the topology and the ones created so far
+func (core *Core) otherClusters(name string) map[string]Cluster {
+	others := make(map[string]Cluster)
+	for n, c := range core.topology.Clusters {
+		others[n] = c
+	}
+	for n, c := range core.clusters {
+		others[n] = c
+	}
+	delete(others, name)
+	return others
+}
+
+type infra struct {
+	core      *Core
+	cluster   *Cluster
+	component *K8sCluster
+
+	cpNodes     []*hcloud.Server
+	workerNodes []*hcloud.Server
+	loadBal     *hcloud.LoadBalancer
+	// firewalls of the nodes, with the ports of the CNI of the cluster
+	ctrlPlaneFirewall *hcloud.Firewall
+	workerFirewall    *hcloud.Firewall
+	loadBalTargets    []*hcloud.LoadBalancerTarget
+	pki               *clusterPKI
+	inventory         *Inventory
+	installer         pulumi.Resource
+	// remote commands of an ssh bootstrap, install.yaml runs after them
+	bootstrapSteps []pulumi.Resource
+	// outputs filling in the inventory of the cluster
+	waitFor []pulumi.Output
+}
+
+// the shared core and the resources of the cluster created so far are known, other clusters are not waited for
+func (ictx *infra) ready() pulumi.ArrayOutput {
+	return pulumi.All(ictx.core.waitFor, ictx.waitFor)
+}
+
+type Inventory struct {
+	ClusterName          string
+	User                 string
+	LoadBalancer         *Node
+	MasterIPs            []*Node
+	WorkerIPs            []*Node
+	Cni                  string
+	CniDef               CNIDef
+	CniVersion           string
+	CniRepoName          string
+	CniRepoURL           string
+	CniChart             string
+	CniNamespace         string
+	Cilium               *CiliumDef
+	CiliumCA             *CiliumCA
+	PodSubnet            string
+	Cri                  string
+	K8sversion           string
+	PrivateRegistry      string
+	InsecureRegistries   []string
+	Registries           map[string]RegistryDef
+	RegistryCredentials  map[string]RegistryCredentials
+	PullSecretNamespaces []string
+	AirGapped            bool
+	Bootstrap            string
+	Ntp                  NtpDef
+	Addons               map[string]AddonDef
+	Releases             []ReleaseDef
+	Manifests            []ManifestDef
+	GitOps               *GitOpsDef
+	GitOpsCredentials    GitOpsCredentials
+	Worker               WorkerDef
+	Autoscaler           *AutoscalerConfig
+	HcloudToken          string
+	Bastion              *Node
+	Pki                  *PKI
+	Backup               BackupDef
+	BackupCredentials    BackupCredentials
+	Kubeadm              KubeadmDef
+	OIDC                 *OIDCDef
+	Audit                AuditDef
+	Encryption           EncryptionDef
+	EncryptionKey        string
+	MaxFailPercentage    int
+}
+
+type Node struct {
+	PrivateIP string
+	PublicIP  string
+	// pod CIDR of the node, only set with native routing
+	PodCIDR string
+}
+
+type PortMapping struct {
+	Source int `yaml:"source"`
+	Target int `yaml:"target"`
+}
+
+type LoadBalancerDef struct {
+	Create       bool                   `yaml:"create"`
+	PortMappings map[string]PortMapping `yaml:"port_mappings"`
+}
+
+type CertificatesDef struct {
+	// any change of this value renews all kubeadm certificates
+	Rotate string `yaml:"rotate,omitempty"`
+	// any change of this value reads the expiry dates again, they are also read after a rotation and when the control plane nodes change
+	Refresh string `yaml:"refresh,omitempty"`
+}
+
+type BackupDef struct {
+	Enabled       bool   `yaml:"enabled"`
+	Schedule      string `yaml:"schedule,omitempty"`
+	RetentionDays int    `yaml:"retention_days,omitempty"`
+	Endpoint      string `yaml:"endpoint"`
+	// any change of this value restores the control plane from the named snapshot
+	Restore string `yaml:"restore,omitempty"`
+}
+
+// object store bucket and credentials, read from the `backup` pulumi secret config
+type BackupCredentials struct {
+	Bucket    string `json:"bucket" yaml:"backup_bucket,omitempty"`
+	AccessKey string `json:"accessKey" yaml:"backup_access_key,omitempty"`
+	SecretKey string `json:"secretKey" yaml:"backup_secret_key,omitempty"`
+}
+
+type Cluster struct {
+	Cri                string          `yaml:"cri"`
+	KubernetesVersion  string          `yaml:"kubernetes_version"`
+	PrivateRegistry    string          `yaml:"private_registry,omitempty"`
+	InsecureRegistries []string        `yaml:"insecure_registries,omitempty"`
+	LoadBalancer       LoadBalancerDef `yaml:"load_balancer,omitempty"`
+	Ntp                NtpDef          `yaml:"ntp"`
+	ControlPlane       struct {
+		NodeCount int `yaml:"node_count"`
+	} `yaml:"control_plane"`
+	Worker     WorkerDef     `yaml:"worker"`
+	Cni        CNIDef        `yaml:"cni"`
+	Cilium     *CiliumDef    `yaml:"cilium,omitempty"`
+	Registries RegistriesDef `yaml:"registries,omitempty"`
+	// node bootstrap, ansible, cloud-init or ssh, defaults to ansible
+	Bootstrap string `yaml:"bootstrap,omitempty"`
+	// install only from the bundle created by the bundle command
+	AirGapped    bool                `yaml:"air_gapped,omitempty"`
+	Addons       map[string]AddonDef `yaml:"addons,omitempty"`
+	Releases     []ReleaseDef        `yaml:"releases,omitempty"`
+	Manifests    []ManifestDef       `yaml:"manifests,omitempty"`
+	GitOps       *GitOpsDef          `yaml:"gitops,omitempty"`
+	Certificates CertificatesDef     `yaml:"certificates,omitempty"`
+	Backup       BackupDef           `yaml:"backup,omitempty"`
+	Kubeadm      KubeadmDef          `yaml:"kubeadm,omitempty"`
+	OIDC         *OIDCDef            `yaml:"oidc,omitempty"`
+	Audit        AuditDef            `yaml:"audit,omitempty"`
+	Encryption   EncryptionDef       `yaml:"encryption,omitempty"`
+	Provisioning ProvisioningDef     `yaml:"provisioning,omitempty"`
+}
+
+type Topology struct {
+	Clusters map[string]Cluster `yaml:"clusters"`
+}
@@ -1,142 +1,84 @@
 package main
 
 import (
-	"bytes"
-	_ "embed"
-	"errors"
-	"regexp"
-	"text/template"
-
-	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
-
 	"fmt"
 	"os"
-	"strconv"
 
-	"github.com/pulumi/pulumi-command/sdk/go/command/local"
-	"github.com/pulumi/pulumi-hcloud/sdk/go/hcloud"
-	"github.com/pulumi/pulumi-tls/sdk/v5/go/tls"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
 	"github.com/rs/zerolog/log"
 
-	"gopkg.in/yaml.v2"
+	"pulumi-hcloud-kubeadm/k8s"
 )
 
-//go:embed inventory.tmpl
-var inventoryTmpl []byte
-
-//go:embed variables.tmpl
-var variablesTmpl []byte
-
-// read the cluster topology
-func readTopology(filename string) *Topology {
-	topology := &Topology{}
-	topo, err := os.ReadFile(filename)
+// read pulumi configuration
+func readConfig(ctx *pulumi.Context) (*k8s.InfraConfig, *k8s.Topology, error) {
+	conf := config.New(ctx, "")
+	infraCfg := &k8s.InfraConfig{}
+	infraCfg.WorkerFlavor = conf.Require("workerFlavor")
+	infraCfg.MasterFlavor = conf.Require("masterFlavor")
+	infraCfg.NetworkZone = conf.Require("networkZone")
+	infraCfg.DataCenter = conf.Require("dataCenter")
+	infraCfg.LbType = conf.Require("lbType")
+	infraCfg.Image = conf.Require("image")
+	infraCfg.SSHUser = conf.Require("sshUser")
+	err := conf.GetObject("backup", &infraCfg.Backups)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("Cannot open topology file %s ", filename)
+		return nil, nil, fmt.Errorf("cannot read backup configuration, is it in correct format? %w", err)
 	}
-	err = yaml.Unmarshal(topo, &topology)
+	err = conf.GetObject("registries", &infraCfg.Registries)
 	if err != nil {
-		log.Fatal().Err(err).Msgf("Cannot unmarshal topology file %s, is it in correct format?", filename)
+		return nil, nil, fmt.Errorf("cannot read registries configuration, is it in correct format? %w", err)
 	}
-	return topology
-}
-
-// create a new component resource
-func NewK8sCluster(ctx *pulumi.Context, name string, opts ...pulumi.ResourceOption) (*K8sCluster, error) {
-	k8sCluster := &K8sCluster{}
-	err := ctx.RegisterComponentResource("pkg:k8s:K8sCluster", name, k8sCluster, opts...)
+	infraCfg.HcloudToken = config.New(ctx, "hcloud").Get("token")
+	if infraCfg.HcloudToken == "" {
+		infraCfg.HcloudToken = os.Getenv("HCLOUD_TOKEN")
+	}
+	err = conf.GetObject("gitops", &infraCfg.GitOps)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("cannot read gitops configuration, is it in correct format? %w", err)
+	}
+	err = conf.GetObject("ntp", &infraCfg.Ntp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read ntp configuration, is it in correct format? %w", err)
 	}
-	return k8sCluster, nil
-}
-
-// read pulumi configuration
-func readConfig(ctx *pulumi.Context) (*infrastructureConfig, *Topology) {
-	conf := config.New(ctx, "")
-	infraCfg := &infrastructureConfig{}
-	infraCfg.workerFlavor = conf.Require("workerFlavor")
-	infraCfg.masterFlavor = conf.Require("masterFlavor")
-	infraCfg.networkZone = conf.Require("networkZone")
-	infraCfg.dataCenter = conf.Require("dataCenter")
-	infraCfg.lbType = conf.Require("lbType")
-	infraCfg.image = conf.Require("image")
-	infraCfg.sshUser = conf.Require("sshUser")
 	topologyFile := conf.Require("topologyFile")
-	topology := readTopology(topologyFile)
-	return infraCfg, topology
+	topology, err := k8s.ReadTopology(topologyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return infraCfg, topology, nil
 }
 
 func main() {
-	pulumi.Run(deploy)
-}
-
-func deploy(ctx *pulumi.Context) (err error) {
-	infraCfg, topology := readConfig(ctx)
-	clusterConfigs := make([]interface{}, 0)
-	coreInfra := &commonInfra{}
-	// generate a key pair
-	err = setupKeys(ctx, coreInfra)
-	if err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "bundle" {
+		if err := k8s.Bundle(os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("Cannot create the air-gapped bundle")
+		}
 		return
 	}
-	// network and subnet
-	err = setupNetwork(ctx, infraCfg, coreInfra)
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		return deploy(ctx, k8s.NewAnsibleProvisioner())
+	})
+}
+
+// deploy the network, the bastion and the clusters of the topology, p installs kubernetes on the servers
+func deploy(ctx *pulumi.Context, p k8s.Provisioner) (err error) {
+	infraCfg, topology, err := readConfig(ctx)
 	if err != nil {
 		return
 	}
-	// jump server
-	err = setupNATAndBastionHost(ctx, infraCfg, coreInfra)
+	clusterConfigs := make([]interface{}, 0)
+	core, err := k8s.NewCore(ctx, &k8s.CoreArgs{Infra: infraCfg, Topology: topology, Provisioner: p})
 	if err != nil {
 		return
 	}
-
-	for cName, cluster := range topology.Clusters {
-		c := cluster
-		clusterName := cName
-		clusterIterator := cluster
-		pulumik8sCluster, err := NewK8sCluster(ctx, clusterName)
-		if err != nil {
-			return err
-		}
-		infra := NewClusterInfra(infraCfg, &c)
-		infra.inventory.ClusterName = clusterName
-		infra.core = coreInfra
-		// create load balancer condition
-		createLoadBal := (cluster.LoadBalancer.Create) || (cluster.ControlPlane.NodeCount+cluster.Worker.NodeCount > 1)
-		for instanceIndex := 0; instanceIndex < cluster.ControlPlane.NodeCount; instanceIndex++ {
-			// control plane nodes
-			masterWorker := cluster.ControlPlane.NodeCount+cluster.Worker.NodeCount <= 1
-			err = setupCtrlPlaneNodes(ctx, infraCfg, infra, instanceIndex, clusterName, masterWorker, createLoadBal, pulumik8sCluster)
-			if err != nil {
-				return err
-			}
-		}
-		for instanceIndex := 0; instanceIndex < cluster.Worker.NodeCount; instanceIndex++ {
-			// worker nodes
-			err = setupWorkerNodes(ctx, infraCfg, infra, instanceIndex, clusterName, pulumik8sCluster)
-			if err != nil {
-				return err
-			}
-		}
-		if createLoadBal {
-			// create loadbalancer
-			err = setupLoadBalancer(ctx, infraCfg, infra, clusterIterator, clusterName, pulumik8sCluster)
-			if err != nil {
-				return err
-			}
-		}
-		ctx.RegisterResourceOutputs(pulumik8sCluster, pulumi.Map{
-			"clusterName": pulumi.String(clusterName),
-		})
-
-		// create inventory and run ansible playbooks
-		kubeConfig, err := installK8s(ctx, clusterName, infra, pulumik8sCluster)
+	for clusterName, cluster := range topology.Clusters {
+		c, err := k8s.NewK8sCluster(ctx, clusterName, &k8s.K8sClusterArgs{Cluster: cluster, Core: core})
 		if err != nil {
 			return err
 		}
-		clusterConfigs = append(clusterConfigs, kubeConfig)
+		clusterConfigs = append(clusterConfigs, pulumi.Map{clusterName: c.Config})
 	}
 	output := pulumi.All(clusterConfigs...).ApplyT(func(k []interface{}) []map[string]interface{} {
 		clusters := make([]map[string]interface{}, 0)
@@ -150,537 +92,7 @@ func deploy(ctx *pulumi.Context) (err error) {
 		return clusters
 	}).(pulumi.MapArrayOutput)
 	ctx.Export("clusters", pulumi.ToSecret(output))
-	ctx.Export("sshkey", coreInfra.privateKey.PrivateKeyOpenssh)
-	ctx.Export("jumpserver", coreInfra.jumpServer.Ipv4Address)
+	ctx.Export("sshkey", core.PrivateKey())
+	ctx.Export("jumpserver", core.JumpServerIP())
 	return
 }
-
-func installK8s(ctx *pulumi.Context, clusterName string, ictx *infra, pulumik8sCluster *K8sCluster) (config *pulumi.MapOutput, err error) {
-	inv, err := local.NewCommand(ctx, fmt.Sprintf("gen-inventory-%s", clusterName), &local.CommandArgs{
-		Create: pulumi.All(infraWaitFor).ApplyT(func(notUsed []interface{}) (string, error) {
-			// add common bastio
-			*ictx.inventory.Bastion = *ictx.core.bastion
-			genInventoryFile(ctx, *ictx.inventory)
-			return fmt.Sprintf("mv /tmp/inventory-%s.ini ./vars/inventory-%s.ini && mv /tmp/variables-%s.yaml ./vars/variables-%s.yaml && echo \"done\"", clusterName, clusterName, clusterName, clusterName), nil
-		}).(pulumi.StringOutput),
-		AssetPaths: pulumi.ToStringArray([]string{"./vars/inventory-" + clusterName + ".ini"}),
-		Delete:     pulumi.String("rm -rf ./vars/inventory-" + clusterName + ".ini & rm -rf ./vars/variables-" + clusterName + ".yaml"),
-	}, pulumi.Parent(pulumik8sCluster))
-	if err != nil {
-		return
-	}
-	bastionSetup, err := local.NewCommand(ctx, fmt.Sprintf("ansible-setup-nat-%s", clusterName), &local.CommandArgs{
-		Create: pulumi.String(fmt.Sprintf("ansible-playbook -i ./vars/inventory-%s.ini ./.ansible/bastion.yaml", clusterName)),
-	}, pulumi.DependsOn([]pulumi.Resource{ictx.core.bastionSetup, inv}), pulumi.Parent(pulumik8sCluster))
-	if err != nil {
-		return nil, err
-	}
-	k8sAnsible, err := local.NewCommand(ctx, fmt.Sprintf("ansible-k8s-installer-%s", clusterName), &local.CommandArgs{
-		Create: pulumi.String(fmt.Sprintf("ansible-playbook -i ./vars/inventory-%s.ini -e \"@./vars/variables-%s.yaml\" ./.ansible/install.yaml", clusterName, clusterName)),
-		Delete: pulumi.String("rm -rf ./vars/cluster-" + clusterName + ".kubeconfig"),
-		AssetPaths: pulumi.ToStringArray([]string{"./vars/cluster-" + clusterName + ".kubeconfig",
-			"./vars/inventory-" + clusterName + ".ini"}),
-	}, pulumi.DependsOn([]pulumi.Resource{bastionSetup}), pulumi.Parent(pulumik8sCluster))
-
-	if err != nil {
-		return nil, err
-	}
-	kubeConfig := k8sAnsible.AssetPaths.ApplyT(func(kubeconfigPaths []string) (map[string]interface{}, error) {
-		ret := make(map[string]interface{}, 0)
-		cConfig := make(map[string]interface{})
-		endPointConfig := make(map[string]interface{})
-		var kubeConfig string
-		kc, err := os.ReadFile(kubeconfigPaths[0])
-		if err != nil {
-			return nil, nil
-		}
-		inv, err := os.ReadFile(kubeconfigPaths[1])
-		if err != nil {
-			return nil, nil
-		}
-		privateKey, err := os.ReadFile("./vars/id_rsa")
-		if err != nil {
-			return nil, nil
-		}
-		m1 := regexp.MustCompile(`server:.*`)
-		if ictx.inventory.LoadBalancer != nil {
-			kubeConfig = m1.ReplaceAllString(string(kc), "server: https://"+ictx.inventory.LoadBalancer.PublicIP+":6443")
-			endPointConfig["app"] = ictx.inventory.LoadBalancer.PublicIP
-			endPointConfig["cluster-api"] = ictx.inventory.LoadBalancer.PublicIP
-			endPointConfig["type"] = "LoadBalancer"
-		} else {
-			if len(ictx.inventory.WorkerIPs) > 0 {
-				endPointConfig["app"] = ictx.inventory.WorkerIPs[0].PublicIP
-			} else {
-				endPointConfig["app"] = ictx.inventory.MasterIPs[0].PublicIP
-			}
-			endPointConfig["cluster-api"] = ictx.inventory.MasterIPs[0].PublicIP
-			kubeConfig = m1.ReplaceAllString(string(kc), "server: https://"+ictx.inventory.MasterIPs[0].PublicIP+":6443")
-			endPointConfig["type"] = "NodePort"
-		}
-		cConfig["endpoints"] = endPointConfig
-		cConfig["kubeconfig"] = kubeConfig
-		cConfig["inventory"] = string(inv)
-		cConfig["privateKey"] = string(privateKey)
-		ret[clusterName] = cConfig
-		return ret, nil
-	}).(pulumi.MapOutput)
-
-	return &kubeConfig, nil
-}
-
-func setupLoadBalancer(ctx *pulumi.Context, infraCfg *infrastructureConfig, ictx *infra, c Cluster, clusterName string, pulumik8sCluster *K8sCluster) (err error) {
-	ictx.loadBal, err = hcloud.NewLoadBalancer(ctx, fmt.Sprintf("loadBalancer-%s", clusterName), &hcloud.LoadBalancerArgs{
-		LoadBalancerType: pulumi.String(infraCfg.lbType),
-		NetworkZone:      pulumi.String(infraCfg.networkZone),
-	}, pulumi.Parent(pulumik8sCluster))
-	if err != nil {
-		return
-	}
-	lbNetwork, err := hcloud.NewLoadBalancerNetwork(ctx, fmt.Sprintf("srvnetwork-%s", clusterName), &hcloud.LoadBalancerNetworkArgs{
-		LoadBalancerId: ictx.loadBal.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-		SubnetId:       ictx.core.subnet.ID(),
-	}, pulumi.Parent(pulumik8sCluster))
-	if err != nil {
-		return
-	}
-	_, err = hcloud.NewLoadBalancerService(ctx, fmt.Sprintf("lbService-%s-kube-api-6443", clusterName), &hcloud.LoadBalancerServiceArgs{
-		LoadBalancerId:  ictx.loadBal.ID(),
-		Protocol:        pulumi.String("tcp"),
-		DestinationPort: pulumi.Int(6443),
-		ListenPort:      pulumi.Int(6443),
-	}, pulumi.Parent(pulumik8sCluster))
-	if err != nil {
-		return
-	}
-	// always create for http and https
-	_, err = hcloud.NewLoadBalancerService(ctx, fmt.Sprintf("lbService-%s-%s-%d", clusterName, "ingress-http", 80), &hcloud.LoadBalancerServiceArgs{
-		LoadBalancerId:  ictx.loadBal.ID(),
-		Protocol:        pulumi.String("tcp"),
-		DestinationPort: pulumi.Int(31394),
-		ListenPort:      pulumi.Int(80),
-	}, pulumi.Parent(pulumik8sCluster))
-	if err != nil {
-		return
-	}
-	_, err = hcloud.NewLoadBalancerService(ctx, fmt.Sprintf("lbService-%s-%s-%d", clusterName, "ingress-https", 443), &hcloud.LoadBalancerServiceArgs{
-		LoadBalancerId:  ictx.loadBal.ID(),
-		Protocol:        pulumi.String("tcp"),
-		DestinationPort: pulumi.Int(31390),
-		ListenPort:      pulumi.Int(443),
-	}, pulumi.Parent(pulumik8sCluster))
-	if err != nil {
-		return
-	}
-	for name, mapping := range c.LoadBalancer.PortMappings {
-		if mapping.Source == 80 || mapping.Source == 443 {
-			return errors.New("ports 80 and 443 cannot be defined for loadbalancer. They default to 80:31394 443:31390")
-		}
-		_, err = hcloud.NewLoadBalancerService(ctx, fmt.Sprintf("lbService-%s-%s-%d", clusterName, name, mapping.Source), &hcloud.LoadBalancerServiceArgs{
-			LoadBalancerId:  ictx.loadBal.ID(),
-			Protocol:        pulumi.String("tcp"),
-			DestinationPort: pulumi.Int(mapping.Target),
-			ListenPort:      pulumi.Int(mapping.Source),
-		}, pulumi.Parent(pulumik8sCluster))
-		if err != nil {
-			return
-		}
-	}
-	ictx.loadBalTargets = make([]*hcloud.LoadBalancerTarget, 0)
-	for i, cpNode := range ictx.cpNodes {
-		lbT, err := hcloud.NewLoadBalancerTarget(ctx, fmt.Sprintf("lbtarget-%s-cp-%d", clusterName, i), &hcloud.LoadBalancerTargetArgs{
-			Type:           pulumi.String("server"),
-			LoadBalancerId: ictx.loadBal.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-			ServerId:       cpNode.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-			UsePrivateIp:   pulumi.Bool(true),
-		}, pulumi.Parent(pulumik8sCluster))
-		if err != nil {
-			return err
-		}
-		ictx.loadBalTargets = append(ictx.loadBalTargets, lbT)
-	}
-	for i, worker := range ictx.workerNodes {
-		lbT, err := hcloud.NewLoadBalancerTarget(ctx, fmt.Sprintf("lbtarget-%s-wrk-%d", clusterName, i), &hcloud.LoadBalancerTargetArgs{
-			Type:           pulumi.String("server"),
-			LoadBalancerId: ictx.loadBal.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-			ServerId:       worker.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-			UsePrivateIp:   pulumi.Bool(true),
-		}, pulumi.Parent(pulumik8sCluster))
-		if err != nil {
-			return err
-		}
-		ictx.loadBalTargets = append(ictx.loadBalTargets, lbT)
-	}
-	lb := pulumi.All(lbNetwork.Ip, ictx.loadBal.Ipv4).ApplyT(func(ips []interface{}) []string {
-		node := &Node{}
-		node.PrivateIP = ips[0].(string)
-		node.PublicIP = ips[1].(string)
-		ictx.inventory.LoadBalancer = node
-		return make([]string, 0)
-	}).(pulumi.StringArrayOutput)
-	infraWaitFor = append(infraWaitFor, lb)
-
-	return
-}
-
-func setupWorkerNodes(ctx *pulumi.Context, infraCfg *infrastructureConfig, ictx *infra, index int, clusterName string, pulumik8sCluster *K8sCluster) (err error) {
-	if ictx.workerNodes == nil {
-		ictx.workerNodes = make([]*hcloud.Server, 0)
-	}
-	workerNode, err := hcloud.NewServer(ctx, fmt.Sprintf("worker-%s-%d", clusterName, index), &hcloud.ServerArgs{
-		Image:                 pulumi.String(infraCfg.image),
-		Datacenter:            pulumi.String(infraCfg.dataCenter),
-		ServerType:            pulumi.String(infraCfg.workerFlavor),
-		SshKeys:               pulumi.StringArray{ictx.core.sshKey.ID()},
-		AllowDeprecatedImages: pulumi.Bool(true),
-		PublicNets: hcloud.ServerPublicNetArray{hcloud.ServerPublicNetArgs{
-			Ipv4Enabled: pulumi.Bool(false),
-			Ipv6Enabled: pulumi.Bool(false),
-		}},
-		Networks: hcloud.ServerNetworkTypeArray{
-			hcloud.ServerNetworkTypeArgs{
-				NetworkId: ictx.core.subnet.NetworkId,
-			}},
-		FirewallIds: pulumi.IntArray{
-			ictx.core.workerFirewall.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-		},
-	}, pulumi.Parent(pulumik8sCluster))
-	ictx.workerNodes = append(ictx.workerNodes, workerNode)
-	wn := workerNode.Networks.Index(pulumi.Int(0)).Ip().ApplyT(func(ip *string) string {
-		node := &Node{}
-		node.PrivateIP = *ip
-		// workers will never have public IP
-		ictx.inventory.WorkerIPs = append(ictx.inventory.WorkerIPs, node)
-		return ""
-	})
-	infraWaitFor = append(infraWaitFor, wn)
-	return
-}
-
-func setupCtrlPlaneNodes(ctx *pulumi.Context, infraCfg *infrastructureConfig, ictx *infra, index int, clusterName string, masterWorker bool, createLoadBal bool, pulumik8sCluster *K8sCluster) (err error) {
-	if ictx.cpNodes == nil {
-		ictx.cpNodes = make([]*hcloud.Server, 0)
-	}
-	var flavor string
-	if masterWorker {
-		flavor = infraCfg.workerFlavor
-	} else {
-		flavor = infraCfg.masterFlavor
-	}
-	cpNode, err := hcloud.NewServer(ctx, fmt.Sprintf("control-plane-%s-%d", clusterName, index), &hcloud.ServerArgs{
-		Image:                 pulumi.String(infraCfg.image),
-		Datacenter:            pulumi.String(infraCfg.dataCenter),
-		ServerType:            pulumi.String(flavor),
-		SshKeys:               pulumi.StringArray{ictx.core.sshKey.ID()},
-		AllowDeprecatedImages: pulumi.Bool(true),
-		PublicNets: hcloud.ServerPublicNetArray{hcloud.ServerPublicNetArgs{
-			Ipv4Enabled: pulumi.Bool(!createLoadBal),
-			Ipv6Enabled: pulumi.Bool(false),
-		}},
-		Networks: hcloud.ServerNetworkTypeArray{
-			hcloud.ServerNetworkTypeArgs{
-				NetworkId: ictx.core.subnet.NetworkId,
-			}},
-		FirewallIds: pulumi.IntArray{
-			ictx.core.ctrlPlaneFirewall.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-		},
-	}, pulumi.Parent(pulumik8sCluster))
-	ictx.cpNodes = append(ictx.cpNodes, cpNode)
-
-	cp := pulumi.All(cpNode.Ipv4Address, cpNode.Networks.Index(pulumi.Int(0)).Ip()).ApplyT(
-		func(ips []interface{}) []string {
-			node := &Node{}
-			node.PrivateIP = *ips[1].(*string)
-			node.PublicIP = ips[0].(string)
-			ictx.inventory.MasterIPs = append(ictx.inventory.MasterIPs, node)
-			return make([]string, 0)
-		})
-
-	infraWaitFor = append(infraWaitFor, cp)
-	return
-}
-
-func setupNATAndBastionHost(ctx *pulumi.Context, infraCfg *infrastructureConfig, coreinfra *commonInfra) (err error) {
-	coreinfra.jumpServer, err = hcloud.NewServer(ctx, "jump-server", &hcloud.ServerArgs{
-		Image:                 pulumi.String("ubuntu-24.04"),
-		Datacenter:            pulumi.String(infraCfg.dataCenter),
-		ServerType:            pulumi.String("cpx11"),
-		SshKeys:               pulumi.StringArray{coreinfra.sshKey.ID()},
-		AllowDeprecatedImages: pulumi.Bool(true),
-		PublicNets: hcloud.ServerPublicNetArray{hcloud.ServerPublicNetArgs{
-			Ipv4Enabled: pulumi.Bool(true),
-			Ipv6Enabled: pulumi.Bool(false),
-		}},
-		Networks: hcloud.ServerNetworkTypeArray{
-			hcloud.ServerNetworkTypeArgs{
-				NetworkId: coreinfra.subnet.NetworkId,
-			}},
-		FirewallIds: pulumi.IntArray{
-			coreinfra.jumpServerFirewall.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-		},
-	})
-	if err != nil {
-		return
-	}
-	bas := pulumi.All(coreinfra.jumpServer.Networks.Index(pulumi.Int(0)).Ip(), coreinfra.jumpServer.Ipv4Address).ApplyT(
-		func(ips []interface{}) []string {
-			node := &Node{}
-			node.PrivateIP = *ips[0].(*string)
-			node.PublicIP = ips[1].(string)
-			coreinfra.bastion = node
-			return make([]string, 0)
-		},
-	)
-
-	coreinfra.bastionSetup, err = local.NewCommand(ctx, "ansible-setup-bastion", &local.CommandArgs{
-		Create: pulumi.All(coreinfra.jumpServer.Networks.Index(pulumi.Int(0)).Ip(), coreinfra.jumpServer.Ipv4Address).ApplyT(
-			func(ips []interface{}) string {
-				return fmt.Sprintf("ansible-playbook --private-key ./vars/id_rsa -u %s  -i \"%s,\" ./.ansible/bastion-prep.yaml", infraCfg.sshUser, ips[1].(string))
-			}).(pulumi.StringOutput),
-	})
-	if err != nil {
-		return
-	}
-
-	infraWaitFor = append(infraWaitFor, bas)
-
-	bastionNet, err := hcloud.NewServerNetwork(ctx, "bastion-private-net", &hcloud.ServerNetworkArgs{
-		ServerId:  coreinfra.jumpServer.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-		NetworkId: coreinfra.network.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-	})
-	if err != nil {
-		return
-	}
-	_, err = hcloud.NewNetworkRoute(ctx, "nat-route", &hcloud.NetworkRouteArgs{
-		NetworkId:   coreinfra.network.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-		Destination: pulumi.String("0.0.0.0/0"),
-		Gateway:     bastionNet.Ip,
-	})
-	return
-}
-
-func setupKeys(ctx *pulumi.Context, ictx *commonInfra) (err error) {
-	ictx.privateKey, err = tls.NewPrivateKey(ctx, "pulumi-hcloud-kubeadm", &tls.PrivateKeyArgs{
-		Algorithm: pulumi.String("RSA"),
-	})
-	if err != nil {
-		return
-	}
-	pkey, err := local.NewCommand(ctx, "gen-privatekey", &local.CommandArgs{
-		Create: ictx.privateKey.PrivateKeyOpenssh.ApplyT(func(privateKey string) string {
-			er := os.WriteFile("./vars/id_rsa", []byte(privateKey), 0600)
-			if er != nil {
-				return "false"
-			}
-			return "echo \"./vars/id_rsa created\""
-		}).(pulumi.StringInput),
-		Delete: pulumi.String("rm -rf ./vars/id_rsa"),
-	})
-	if err != nil {
-		return
-	}
-	ictx.sshKey, err = hcloud.NewSshKey(ctx, "pulumi-hcloud-kubeadm", &hcloud.SshKeyArgs{
-		PublicKey: ictx.privateKey.PublicKeyOpenssh,
-	}, pulumi.DependsOn([]pulumi.Resource{pkey}))
-
-	ictx.sshKey.ToSshKeyOutput()
-	return
-}
-
-func setupNetwork(ctx *pulumi.Context, infraCfg *infrastructureConfig, ictx *commonInfra) (err error) {
-	ictx.network, err = hcloud.NewNetwork(ctx, "kubeadm-network", &hcloud.NetworkArgs{
-		IpRange: pulumi.String("10.0.0.0/16"),
-	})
-	if err != nil {
-		return
-	}
-	ictx.subnet, err = hcloud.NewNetworkSubnet(ctx, "kubeadm-network-subnet", &hcloud.NetworkSubnetArgs{
-		NetworkId:   ictx.network.ID().ToStringOutput().ApplyT(strconv.Atoi).(pulumi.IntOutput),
-		Type:        pulumi.String("cloud"),
-		NetworkZone: pulumi.String(infraCfg.networkZone),
-		IpRange:     pulumi.String("10.0.1.0/24"),
-	})
-	if err != nil {
-		return
-	}
-	ictx.jumpServerFirewall, err = hcloud.NewFirewall(ctx, "jump-server-firewall", &hcloud.FirewallArgs{
-		Rules: hcloud.FirewallRuleArray{
-			&hcloud.FirewallRuleArgs{
-				Direction: pulumi.String("in"),
-				Protocol:  pulumi.String("tcp"),
-				Port:      pulumi.String("22"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("0.0.0.0/0"),
-				},
-			},
-		},
-	})
-	if err != nil {
-		return
-	}
-	ictx.workerFirewall, err = hcloud.NewFirewall(ctx, "worker-firewall", &hcloud.FirewallArgs{
-		Rules: hcloud.FirewallRuleArray{
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("Kubelet API"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("tcp"),
-				Port:        pulumi.String("10250"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-			// nodeports only from loadbalancer
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("worker-Nodeports"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("tcp"),
-				Port:        pulumi.String("30000-32767"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-			// workers can only be ssh'ed from bastion host
-			&hcloud.FirewallRuleArgs{
-				Direction: pulumi.String("in"),
-				Protocol:  pulumi.String("tcp"),
-				Port:      pulumi.String("22"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-		},
-	})
-	if err != nil {
-		return
-	}
-	ictx.ctrlPlaneFirewall, err = hcloud.NewFirewall(ctx, "control-plane-firewall", &hcloud.FirewallArgs{
-		Rules: hcloud.FirewallRuleArray{
-			&hcloud.FirewallRuleArgs{
-				Direction: pulumi.String("in"),
-				Protocol:  pulumi.String("tcp"),
-				Port:      pulumi.String("22"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-			// nodeports only from loadbalancer
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("Ncp-odeports"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("tcp"),
-				Port:        pulumi.String("30000-32767"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("Kubernetes API server"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("tcp"),
-				Port:        pulumi.String("6443"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("0.0.0.0/0"),
-				},
-			},
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("etcd server client API"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("tcp"),
-				Port:        pulumi.String("2379-2380"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("Kubelet API"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("tcp"),
-				Port:        pulumi.String("10250"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("kube-scheduler"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("tcp"),
-				Port:        pulumi.String("10259"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("kube-controller-manager"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("tcp"),
-				Port:        pulumi.String("10257"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-			&hcloud.FirewallRuleArgs{
-				Description: pulumi.String("flannel"),
-				Direction:   pulumi.String("in"),
-				Protocol:    pulumi.String("udp"),
-				Port:        pulumi.String("8285"),
-				SourceIps: pulumi.StringArray{
-					pulumi.String("10.0.1.0/24"),
-				},
-			},
-		},
-	})
-	if err != nil {
-		return
-	}
-	return
-}
-
-func genInventoryFile(ctx *pulumi.Context, clusterInventory Inventory) {
-	renderedTemplate, parseErr := template.New("invtpl").Parse(string(inventoryTmpl))
-	if parseErr != nil {
-		ctx.Log.Error("Error parsing template file", nil)
-	}
-	var buff bytes.Buffer
-	if err := renderedTemplate.Execute(&buff, clusterInventory); err != nil {
-		ctx.Log.Error("Failed to render inventory template "+err.Error(), nil)
-	}
-
-	outFileLoc := fmt.Sprintf("/tmp/inventory-%s.ini", clusterInventory.ClusterName)
-
-	if err := os.WriteFile(outFileLoc, buff.Bytes(), 0655); err != nil {
-		ctx.Log.Error("Failed to write inventory files ", nil)
-	}
-
-	buff.Reset()
-	renderedTemplate, parseErr = template.New("invtpl").Parse(string(variablesTmpl))
-	if parseErr != nil {
-		ctx.Log.Error("Error parsing template file ", nil)
-	}
-	if err := renderedTemplate.Execute(&buff, clusterInventory); err != nil {
-		ctx.Log.Error("Failed to render inventory template "+err.Error(), nil)
-	}
-
-	outFileLoc = fmt.Sprintf("/tmp/variables-%s.yaml", clusterInventory.ClusterName)
-
-	if err := os.WriteFile(outFileLoc, buff.Bytes(), 0655); err != nil {
-		ctx.Log.Error("Failed to write inventory files "+err.Error(), nil)
-	}
-
-}
-
-func NewClusterInfra(infracfg *infrastructureConfig, cluster *Cluster) *infra {
-	workerIps := make([]*Node, 0)
-	cpIps := make([]*Node, 0)
-
-	inv := &Inventory{Cni: cluster.Cni,
-		Cri:                cluster.Cri,
-		K8sversion:         cluster.KubernetesVersion,
-		User:               infracfg.sshUser,
-		WorkerIPs:          workerIps,
-		MasterIPs:          cpIps,
-		Bastion:            &Node{},
-		PrivateRegistry:    cluster.PrivateRegistry,
-		InsecureRegistries: cluster.InsecureRegistries}
-	i := &infra{inventory: inv}
-	return i
-}
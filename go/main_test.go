@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+
+	"pulumi-hcloud-kubeadm/k8s"
+)
+
+// mocked resource monitor, it records the inputs of every resource by name
+type mocks struct {
+	mu     sync.Mutex
+	nextID int
+	inputs map[string]resource.PropertyMap
+}
+
+func (m *mocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	m.inputs[args.Name] = args.Inputs
+	outputs := args.Inputs.Copy()
+	switch args.TypeToken {
+	case "hcloud:index/server:Server":
+		outputs["ipv4Address"] = resource.NewStringProperty(fmt.Sprintf("203.0.113.%d", m.nextID))
+		outputs["networks"] = resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.PropertyMap{
+			"ip": resource.NewStringProperty(fmt.Sprintf("10.0.1.%d", m.nextID)),
+		})})
+	case "tls:index/selfSignedCert:SelfSignedCert":
+		outputs["certPem"] = resource.NewStringProperty(testCACert())
+	case "tls:index/privateKey:PrivateKey":
+		for _, k := range []resource.PropertyKey{"privateKeyPem", "publicKeyPem", "privateKeyOpenssh", "publicKeyOpenssh"} {
+			outputs[k] = resource.NewStringProperty(string(k) + " of " + args.Name)
+		}
+	}
+	return strconv.Itoa(m.nextID), outputs, nil
+}
+
+var (
+	testCACertOnce sync.Once
+	testCACertPem  string
+)
+
+// self-signed certificate standing in for the CAs, the PKI hashes its public key
+func testCACert() string {
+	testCACertOnce.Do(func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "kubernetes"},
+			NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		if err != nil {
+			panic(err)
+		}
+		testCACertPem = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	})
+	return testCACertPem
+}
+
+func (m *mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	return args.Args, nil
+}
+
+// fake provisioner keeping the configuration deploy read
+type configProvisioner struct {
+	k8s.Provisioner
+	infraCfg *k8s.InfraConfig
+}
+
+func (p *configProvisioner) PrepareBastion(ctx *pulumi.Context, infraCfg *k8s.InfraConfig, core *k8s.Core, opts ...pulumi.ResourceOption) (pulumi.Resource, error) {
+	p.infraCfg = infraCfg
+	return p.Provisioner.PrepareBastion(ctx, infraCfg, core, opts...)
+}
+
+const testTopology = `clusters:
+  apps:
+    cri: containerd
+    kubernetes_version: "1.30.2"
+    control_plane:
+      node_count: 1
+    worker:
+      node_count: 2
+    cni:
+      name: flannel
+`
+
+// stack configuration of the program, with the topology file written to the working directory
+func stackConfig(t *testing.T, extra map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "vars"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vars", "topology.yaml"), []byte(testTopology), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	config := map[string]string{
+		"project:workerFlavor": "cpx41", "project:masterFlavor": "cpx31", "project:lbType": "lb11",
+		"project:image": "ubuntu-24.04", "project:networkZone": "eu-central", "project:dataCenter": "fsn1-dc14",
+		"project:sshUser": "root", "project:topologyFile": "./vars/topology.yaml",
+		"project:ntp":  `{"primary":"ntp1.hetzner.de","secondary":"ntp2.hetzner.com"}`,
+		"hcloud:token": "hcloud token",
+	}
+	for k, v := range extra {
+		config[k] = v
+	}
+	out, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PULUMI_CONFIG", string(out))
+}
+
+func TestDeploy(t *testing.T) {
+	stackConfig(t, nil)
+	fake := k8s.NewFakeProvisioner()
+	p := &configProvisioner{Provisioner: fake}
+	m := &mocks{inputs: map[string]resource.PropertyMap{}}
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		return deploy(ctx, p)
+	}, pulumi.WithMocks("project", "stack", m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps := strings.Join(fake.Steps(), ","); steps != "bastion:,nodes:apps,init:apps,join:apps,addons:apps,kubeconfig:apps" {
+		t.Errorf("steps %s", steps)
+	}
+	if p.infraCfg == nil {
+		t.Fatal("the bastion was not prepared")
+	}
+	if p.infraCfg.Image != "ubuntu-24.04" || p.infraCfg.HcloudToken != "hcloud token" || p.infraCfg.Ntp.Secondary != "ntp2.hetzner.com" {
+		t.Errorf("configuration %+v", p.infraCfg)
+	}
+	for _, name := range []string{"jump-server", "control-plane-apps-0", "worker-apps-0", "worker-apps-1"} {
+		if m.inputs[name] == nil {
+			t.Errorf("no server %s", name)
+		}
+	}
+	if image := m.inputs["worker-apps-1"]["image"]; image.StringValue() != "ubuntu-24.04" {
+		t.Errorf("worker image %v", image)
+	}
+}
+
+func TestDeployConfigErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config map[string]string
+		err    string
+	}{
+		"missing topology": {map[string]string{"project:topologyFile": "./vars/missing.yaml"}, "cannot open topology file"},
+		"ntp format":       {map[string]string{"project:ntp": `"ntp1.hetzner.de"`}, "cannot read ntp configuration"},
+		"backup format":    {map[string]string{"project:backup": `[1]`}, "cannot read backup configuration"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			stackConfig(t, tc.config)
+			fake := k8s.NewFakeProvisioner()
+			err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+				return deploy(ctx, fake)
+			}, pulumi.WithMocks("project", "stack", &mocks{inputs: map[string]resource.PropertyMap{}}))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("err = %v, want %q", err, tc.err)
+			}
+			if steps := fake.Steps(); len(steps) != 0 {
+				t.Errorf("steps %v", steps)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"gopkg.in/yaml.v2"
+
+	"pulumi-hcloud-kubeadm/k8s"
+)
+
+// send the clusters of a topology through the schema and the wire format of the engine and back,
+// the options of the clusters must come back unchanged and every option must be in the schema
+func RoundTrip(topology *k8s.Topology) error {
+	schema, err := Schema()
+	if err != nil {
+		return err
+	}
+	var spec struct {
+		Types     map[string]map[string]interface{} `json:"types"`
+		Resources map[string]map[string]interface{} `json:"resources"`
+	}
+	err = json.Unmarshal(schema, &spec)
+	if err != nil {
+		return err
+	}
+	clusters := map[string]k8s.Cluster{}
+	for name, cluster := range topology.Clusters {
+		clusters[name] = cluster
+	}
+	// the topology sets a few options only
+	var every k8s.Cluster
+	fill(reflect.ValueOf(&every).Elem())
+	clusters["every-option"] = every
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cluster := clusters[name]
+		props, err := encodeCluster(cluster)
+		if err != nil {
+			return fmt.Errorf("cluster %s: %w", name, err)
+		}
+		inputs := spec.Resources[k8s.ClusterType]["inputProperties"].(map[string]interface{})
+		err = checkProperties(spec.Types, inputs, props, name)
+		if err != nil {
+			return err
+		}
+		wire, err := plugin.MarshalProperties(resource.NewPropertyMapFromMap(props), plugin.MarshalOptions{})
+		if err != nil {
+			return fmt.Errorf("cluster %s: %w", name, err)
+		}
+		received, err := plugin.UnmarshalProperties(wire, plugin.MarshalOptions{})
+		if err != nil {
+			return fmt.Errorf("cluster %s: %w", name, err)
+		}
+		decoded, err := decodeCluster(received.Mappable(), name)
+		if err != nil {
+			return fmt.Errorf("cluster %s: %w", name, err)
+		}
+		// empty and missing maps are the same option
+		before, _ := yaml.Marshal(cluster)
+		after, _ := yaml.Marshal(decoded)
+		if string(before) != string(after) {
+			return fmt.Errorf("cluster %s changed in the round trip:\n%s\n%s", name, before, after)
+		}
+	}
+	return nil
+}
+
+// set every field of v, lists and maps get one element
+func fill(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem())
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0))
+	case reflect.Map:
+		e := reflect.New(v.Type().Elem()).Elem()
+		fill(e)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(reflect.ValueOf("key"), e)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i))
+			}
+		}
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(map[string]interface{}{"key": []interface{}{"value", 1, true}}))
+	}
+}
+
+// every property of the value is in the schema properties, following the object types of the package
+func checkProperties(types map[string]map[string]interface{}, properties map[string]interface{}, v map[string]interface{}, path string) error {
+	for key, value := range v {
+		spec, ok := properties[key].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s.%s is not in the schema", path, key)
+		}
+		err := checkValue(types, spec, value, path+"."+key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkValue(types map[string]map[string]interface{}, spec map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := spec["$ref"].(string); ok && strings.HasPrefix(ref, "#/types/") {
+		t, ok := types[strings.TrimPrefix(ref, "#/types/")]
+		if !ok {
+			return fmt.Errorf("%s: type %s is not in the schema", path, ref)
+		}
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object of type %s, got %T", path, ref, value)
+		}
+		properties, _ := t["properties"].(map[string]interface{})
+		return checkProperties(types, properties, m, path)
+	}
+	if items, ok := spec["items"].(map[string]interface{}); ok {
+		a, _ := value.([]interface{})
+		for i, e := range a {
+			err := checkValue(types, items, e, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if elem, ok := spec["additionalProperties"].(map[string]interface{}); ok {
+		m, _ := value.(map[string]interface{})
+		for k, e := range m {
+			err := checkValue(types, elem, e, path+"."+k)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
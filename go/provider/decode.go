@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"pulumi-hcloud-kubeadm/k8s"
+)
+
+// converts between the inputs of the components and the topology, keys of the structs are renamed following t:
+// property names to yaml keys when toYAML is set, yaml keys to property names otherwise
+func convert(t reflect.Type, v interface{}, toYAML bool, path string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, err := stringMap(v, path)
+		if err != nil {
+			return nil, err
+		}
+		out := map[string]interface{}{}
+		known := map[string]bool{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			key, skip := fieldKey(field, "yaml")
+			if skip {
+				continue
+			}
+			from, to := key, propertyName(key)
+			if toYAML {
+				from, to = to, from
+			}
+			known[from] = true
+			if fv, ok := m[from]; ok {
+				out[to], err = convert(field.Type, fv, toYAML, path+"."+from)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		// typos would silently drop an option
+		for _, k := range sortedKeys(m) {
+			if !known[k] {
+				return nil, fmt.Errorf("%s: unknown property %s", path, k)
+			}
+		}
+		return out, nil
+	case reflect.Map:
+		m, err := stringMap(v, path)
+		if err != nil {
+			return nil, err
+		}
+		out := map[string]interface{}{}
+		for k, e := range m {
+			out[k], err = convert(t.Elem(), e, toYAML, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case reflect.Slice:
+		a, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected a list, got %T", path, v)
+		}
+		out := make([]interface{}, len(a))
+		for i, e := range a {
+			var err error
+			out[i], err = convert(t.Elem(), e, toYAML, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		// numbers of the engine are floats
+		if f, ok := v.(float64); ok {
+			if f != math.Trunc(f) {
+				return nil, fmt.Errorf("%s: expected an integer, got %v", path, f)
+			}
+			return int64(f), nil
+		}
+		return v, nil
+	case reflect.Interface:
+		if toYAML {
+			return v, nil
+		}
+		return plainValue(v), nil
+	default:
+		return v, nil
+	}
+}
+
+// mapping of yaml or of the engine with string keys
+func stringMap(v interface{}, path string) (map[string]interface{}, error) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, nil
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			out[fmt.Sprint(k)] = e
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("%s: expected an object, got %T", path, v)
+	}
+}
+
+// yaml value with string keys, as the engine passes it
+func plainValue(v interface{}) interface{} {
+	switch e := v.(type) {
+	case map[interface{}]interface{}, map[string]interface{}:
+		m, _ := stringMap(e, "")
+		out := make(map[string]interface{}, len(m))
+		for k, x := range m {
+			out[k] = plainValue(x)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(e))
+		for i, x := range e {
+			out[i] = plainValue(x)
+		}
+		return out
+	case int:
+		return float64(e)
+	default:
+		return v
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// cluster of the topology from the options of a component
+func decodeCluster(props map[string]interface{}, path string) (cluster k8s.Cluster, err error) {
+	v, err := convert(reflect.TypeOf(cluster), props, true, path)
+	if err != nil {
+		return
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(out, &cluster)
+	return
+}
+
+// time servers of the bastion, the ntp input of the network in the format of the ntp of a cluster
+func decodeNtp(props map[string]interface{}) (ntp k8s.NtpDef, err error) {
+	v, err := convert(reflect.TypeOf(ntp), props, true, "ntp")
+	if err != nil {
+		return
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(out, &ntp)
+	return
+}
+
+// options of a component from a cluster of the topology, the inverse of decodeCluster
+func encodeCluster(cluster k8s.Cluster) (map[string]interface{}, error) {
+	out, err := yaml.Marshal(cluster)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	err = yaml.Unmarshal(out, &v)
+	if err != nil {
+		return nil, err
+	}
+	props, err := convert(reflect.TypeOf(cluster), v, false, "cluster")
+	if err != nil {
+		return nil, err
+	}
+	return props.(map[string]interface{}), nil
+}
+
+// credentials of a cluster, in the json format of the stack configuration
+func decodeCredentials(props map[string]interface{}) (creds clusterCredentials, err error) {
+	out, err := json.Marshal(props)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(out, &creds)
+	return
+}
+
+// split the inputs of the cluster component into the options of the cluster and the rest
+func splitClusterInputs(inputs map[string]interface{}) (options, rest map[string]interface{}) {
+	options, rest = map[string]interface{}{}, map[string]interface{}{}
+	for k, v := range inputs {
+		switch k {
+		case "bastion", "clusterName", "credentials":
+			rest[k] = v
+		default:
+			options[k] = v
+		}
+	}
+	return
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// mocked resource monitor, it records the inputs of every resource by type and name
+type mocks struct {
+	mu     sync.Mutex
+	nextID int
+	inputs map[string]resource.PropertyMap
+}
+
+func newMocks() *mocks {
+	return &mocks{inputs: make(map[string]resource.PropertyMap)}
+}
+
+func (m *mocks) NewResource(args pulumi.MockResourceArgs) (string, resource.PropertyMap, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	m.inputs[args.TypeToken+"::"+args.Name] = args.Inputs
+	outputs := args.Inputs.Copy()
+	switch args.TypeToken {
+	case "hcloud:index/server:Server":
+		outputs["ipv4Address"] = resource.NewStringProperty(fmt.Sprintf("203.0.113.%d", m.nextID))
+		outputs["networks"] = resource.NewArrayProperty([]resource.PropertyValue{resource.NewObjectProperty(resource.PropertyMap{
+			"ip": resource.NewStringProperty(fmt.Sprintf("10.0.1.%d", m.nextID)),
+		})})
+	case "tls:index/selfSignedCert:SelfSignedCert":
+		outputs["certPem"] = resource.NewStringProperty(testCACert())
+	case "tls:index/privateKey:PrivateKey":
+		for _, k := range []resource.PropertyKey{"privateKeyPem", "publicKeyPem", "privateKeyOpenssh", "publicKeyOpenssh"} {
+			outputs[k] = resource.NewStringProperty(string(k) + " of " + args.Name)
+		}
+	}
+	return strconv.Itoa(m.nextID), outputs, nil
+}
+
+func (m *mocks) Call(args pulumi.MockCallArgs) (resource.PropertyMap, error) {
+	return args.Args, nil
+}
+
+// inputs of the resource of type typ registered with name, nil when there is none
+func (m *mocks) resource(typ, name string) resource.PropertyMap {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.inputs[typ+"::"+name]
+}
+
+var (
+	testCACertOnce sync.Once
+	testCACertPem  string
+)
+
+// self-signed certificate standing in for the CAs, the PKI hashes its public key
+func testCACert() string {
+	testCACertOnce.Do(func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "kubernetes"},
+			NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		if err != nil {
+			panic(err)
+		}
+		testCACertPem = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	})
+	return testCACertPem
+}
@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/internals"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/provider"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"pulumi-hcloud-kubeadm/k8s"
+)
+
+// network of a Network component, with the inputs the clusters need
+type networkEntry struct {
+	network *k8s.Network
+	infra   *k8s.InfraConfig
+}
+
+// bastion of a Bastion component, and the network it belongs to
+type bastionEntry struct {
+	bastion *k8s.Bastion
+	network *networkEntry
+}
+
+// serves the Network, Bastion and Cluster components to programs in any language
+type componentProvider struct {
+	pulumirpc.UnimplementedResourceProviderServer
+
+	engine *grpc.ClientConn
+	schema []byte
+	// installs kubernetes on the servers
+	provisioner k8s.Provisioner
+
+	mu sync.Mutex
+	// components constructed by this process by URN, a cluster finds the core of its bastion here
+	networks map[string]*networkEntry
+	bastions map[string]*bastionEntry
+}
+
+// serve the components to the engine at engineAddr, the port is printed for the engine to connect
+func Serve(engineAddr string, p k8s.Provisioner) (err error) {
+	schema, err := Schema()
+	if err != nil {
+		return
+	}
+	engine, err := grpc.Dial(engineAddr,
+		rpcutil.GrpcChannelOptions(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("cannot connect to the engine at %s: %w", engineAddr, err)
+	}
+	defer engine.Close()
+	server := &componentProvider{
+		engine:      engine,
+		schema:      schema,
+		provisioner: p,
+		networks:    map[string]*networkEntry{},
+		bastions:    map[string]*bastionEntry{},
+	}
+	handle, err := rpcutil.ServeWithOptions(rpcutil.ServeOptions{
+		Init: func(srv *grpc.Server) error {
+			pulumirpc.RegisterResourceProviderServer(srv, server)
+			return nil
+		},
+		Options: rpcutil.OpenTracingServerInterceptorOptions(nil),
+	})
+	if err != nil {
+		return
+	}
+	fmt.Printf("%d\n", handle.Port)
+	return <-handle.Done
+}
+
+func (p *componentProvider) GetPluginInfo(context.Context, *emptypb.Empty) (*pulumirpc.PluginInfo, error) {
+	return &pulumirpc.PluginInfo{Version: Version}, nil
+}
+
+func (p *componentProvider) GetSchema(context.Context, *pulumirpc.GetSchemaRequest) (*pulumirpc.GetSchemaResponse, error) {
+	return &pulumirpc.GetSchemaResponse{Schema: string(p.schema)}, nil
+}
+
+// the provider has no configuration, credentials are inputs of the components
+func (p *componentProvider) CheckConfig(_ context.Context, req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	return &pulumirpc.CheckResponse{Inputs: req.GetNews()}, nil
+}
+
+func (p *componentProvider) DiffConfig(context.Context, *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	return &pulumirpc.DiffResponse{}, nil
+}
+
+// the plugin runs in the work directory, the playbooks are read from its .ansible and the rendered files written to its vars
+func (p *componentProvider) Configure(_ context.Context, req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+	dir := os.Getenv(WorkDirEnv)
+	if v, ok := req.GetArgs().AsMap()["workDir"].(string); ok && v != "" {
+		dir = v
+	} else if v := req.GetVariables()[Name+":config:workDir"]; v != "" {
+		dir = v
+	}
+	if err := enterWorkDir(dir); err != nil {
+		return nil, err
+	}
+	return &pulumirpc.ConfigureResponse{
+		AcceptSecrets:   true,
+		SupportsPreview: true,
+		AcceptResources: true,
+		AcceptOutputs:   true,
+	}, nil
+}
+
+func (p *componentProvider) Cancel(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (p *componentProvider) Construct(ctx context.Context, req *pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error) {
+	return provider.Construct(ctx, req, p.engine, p.construct)
+}
+
+func (p *componentProvider) construct(ctx *pulumi.Context, typ, name string, inputs provider.ConstructInputs,
+	options pulumi.ResourceOption,
+) (*provider.ConstructResult, error) {
+	m, err := inputs.Map()
+	if err != nil {
+		return nil, err
+	}
+	props, err := awaitInputs(ctx, m)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", typ, name, err)
+	}
+	var component pulumi.ComponentResource
+	switch typ {
+	case k8s.NetworkType:
+		component, err = p.constructNetwork(ctx, name, props, options)
+	case k8s.BastionType:
+		component, err = p.constructBastion(ctx, name, props, options)
+	case k8s.ClusterType:
+		component, err = p.constructCluster(ctx, name, props, options)
+	default:
+		return nil, fmt.Errorf("unknown component type %s", typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return provider.NewConstructResult(component)
+}
+
+// plain values of the inputs, the topology decides which resources are created and must be known
+func awaitInputs(ctx *pulumi.Context, m pulumi.Map) (map[string]interface{}, error) {
+	result, err := internals.UnsafeAwaitOutput(ctx.Context(), m.ToMapOutput())
+	if err != nil {
+		return nil, err
+	}
+	if !result.Known {
+		return nil, errors.New("inputs must be known during preview, they cannot depend on resources created in the same update")
+	}
+	return result.Value.(map[string]interface{}), nil
+}
+
+// change to dir, the current directory when empty, it must hold the playbooks in .ansible
+func enterWorkDir(dir string) error {
+	if dir != "" {
+		if err := os.Chdir(dir); err != nil {
+			return fmt.Errorf("work directory of the provider: %w", err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(wd, ".ansible", "install.yaml")); err != nil {
+		return fmt.Errorf("no playbooks in %s, set the workDir of the provider or %s to the directory holding .ansible and vars", filepath.Join(wd, ".ansible"), WorkDirEnv)
+	}
+	return os.MkdirAll(filepath.Join(wd, "vars"), 0755)
+}
+
+// URN of a resource reference input
+func referenceURN(ctx *pulumi.Context, v interface{}, property string) (string, error) {
+	res, ok := v.(pulumi.Resource)
+	if !ok {
+		return "", fmt.Errorf("%s must be a resource of the %s package", property, Name)
+	}
+	result, err := internals.UnsafeAwaitOutput(ctx.Context(), res.URN())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(result.Value), nil
+}
+
+func (p *componentProvider) constructNetwork(ctx *pulumi.Context, name string, props map[string]interface{},
+	options pulumi.ResourceOption,
+) (*k8s.Network, error) {
+	infra := &k8s.InfraConfig{}
+	for key, field := range map[string]*string{
+		"workerFlavor": &infra.WorkerFlavor,
+		"masterFlavor": &infra.MasterFlavor,
+		"lbType":       &infra.LbType,
+		"image":        &infra.Image,
+		"networkZone":  &infra.NetworkZone,
+		"dataCenter":   &infra.DataCenter,
+		"sshUser":      &infra.SSHUser,
+		"hcloudToken":  &infra.HcloudToken,
+	} {
+		if v, ok := props[key].(string); ok {
+			*field = v
+		}
+	}
+	var mesh []string
+	if v, ok := props["clusterMesh"].([]interface{}); ok {
+		for i, member := range v {
+			s, ok := member.(string)
+			if !ok {
+				return nil, fmt.Errorf("network %s: clusterMesh[%d] must be a string", name, i)
+			}
+			mesh = append(mesh, s)
+		}
+	}
+	if v, ok := props["ntp"].(map[string]interface{}); ok {
+		ntp, err := decodeNtp(v)
+		if err != nil {
+			return nil, fmt.Errorf("network %s: %w", name, err)
+		}
+		infra.Ntp = ntp
+	}
+	network, err := k8s.NewNetwork(ctx, name, &k8s.NetworkArgs{Infra: infra, ClusterMesh: mesh}, options)
+	if err != nil {
+		return nil, err
+	}
+	urn, err := referenceURN(ctx, network, "network")
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.networks[urn] = &networkEntry{network: network, infra: infra}
+	return network, nil
+}
+
+func (p *componentProvider) constructBastion(ctx *pulumi.Context, name string, props map[string]interface{},
+	options pulumi.ResourceOption,
+) (*k8s.Bastion, error) {
+	urn, err := referenceURN(ctx, props["network"], "network")
+	if err != nil {
+		return nil, fmt.Errorf("bastion %s: %w", name, err)
+	}
+	p.mu.Lock()
+	network, ok := p.networks[urn]
+	p.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("bastion %s: network %s was not created by this provider", name, urn)
+	}
+	bastion, err := k8s.NewBastion(ctx, name, &k8s.BastionArgs{Network: network.network, Provisioner: p.provisioner}, options)
+	if err != nil {
+		return nil, err
+	}
+	urn, err = referenceURN(ctx, bastion, "bastion")
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bastions[urn] = &bastionEntry{bastion: bastion, network: network}
+	return bastion, nil
+}
+
+func (p *componentProvider) constructCluster(ctx *pulumi.Context, name string, props map[string]interface{},
+	options pulumi.ResourceOption,
+) (*k8s.K8sCluster, error) {
+	clusterOptions, rest := splitClusterInputs(props)
+	urn, err := referenceURN(ctx, rest["bastion"], "bastion")
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %w", name, err)
+	}
+	p.mu.Lock()
+	bastion, ok := p.bastions[urn]
+	p.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("cluster %s: bastion %s was not created by this provider", name, urn)
+	}
+	clusterName := name
+	if v, ok := rest["clusterName"].(string); ok && v != "" {
+		clusterName = v
+	}
+	cluster, err := decodeCluster(clusterOptions, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %w", name, err)
+	}
+	credentials, _ := rest["credentials"].(map[string]interface{})
+	creds, err := decodeCredentials(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: %w", name, err)
+	}
+	// the credentials of the stack configuration are keyed by cluster name
+	infra := *bastion.network.infra
+	infra.Backups = map[string]k8s.BackupCredentials{clusterName: creds.Backup}
+	infra.Registries = map[string]map[string]k8s.RegistryCredentials{clusterName: creds.Registries}
+	// a deploy key without a gitops block is rejected, like in the stack configuration
+	if creds.GitOps != (k8s.GitOpsCredentials{}) {
+		infra.GitOps = map[string]k8s.GitOpsCredentials{clusterName: creds.GitOps}
+	}
+	args := &k8s.K8sClusterArgs{Cluster: cluster, Infra: &infra, Core: bastion.bastion.Core()}
+	return k8s.NewK8sClusterOfType(ctx, k8s.ClusterType, clusterName, args, options)
+}
@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"pulumi-hcloud-kubeadm/k8s"
+)
+
+// package of the plugin and of the generated SDKs
+const Name = "hcloudkubeadm"
+
+// environment variable with the work directory of the plugin, the workDir of the provider wins over it
+const WorkDirEnv = "HCLOUDKUBEADM_WORKDIR"
+
+// version of the plugin, set at build time with -ldflags "-X pulumi-hcloud-kubeadm/provider.Version=..."
+var Version = "0.1.0"
+
+// credentials of a cluster, the same as its entries in the backup, registries and gitops stack configuration of the topology program
+type clusterCredentials struct {
+	Backup     k8s.BackupCredentials              `json:"backup,omitempty"`
+	Registries map[string]k8s.RegistryCredentials `json:"registries,omitempty"`
+	GitOps     k8s.GitOpsCredentials              `json:"gitops,omitempty"`
+}
+
+// inputs of the network besides the clusters, the same as the stack configuration of the topology program
+var infraInputs = map[string]string{
+	"workerFlavor": "server type of the workers, e.g. cx31",
+	"masterFlavor": "server type of the control plane nodes, e.g. cx21",
+	"lbType":       "load balancer type, e.g. lb11",
+	"image":        "server image, e.g. ubuntu-22.04",
+	"networkZone":  "network zone, e.g. eu-central",
+	"dataCenter":   "data center of the servers, e.g. fsn1-dc14",
+	"sshUser":      "user the playbooks connect as",
+	"hcloudToken":  "hetzner cloud API token, used by the cluster autoscaler and the cloud controller manager",
+}
+
+// generates the package schema from the yaml tags of the topology types
+type schemaBuilder struct {
+	types map[string]interface{}
+	// yaml for the topology types, json for the credentials
+	tag string
+}
+
+// property name of a yaml key, e.g. kubernetes_version -> kubernetesVersion
+func propertyName(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// key of a struct field in the topology or the configuration, skip is set for fields not in them
+func fieldKey(field reflect.StructField, tagName string) (key string, skip bool) {
+	tag, ok := field.Tag.Lookup(tagName)
+	if !ok || tag == "-" || !field.IsExported() {
+		return "", true
+	}
+	return strings.Split(tag, ",")[0], false
+}
+
+// object types named other than their go type, the Cluster token is the component
+var typeNames = map[reflect.Type]string{
+	reflect.TypeOf(k8s.Cluster{}):        "ClusterOptions",
+	reflect.TypeOf(clusterCredentials{}): "ClusterCredentials",
+}
+
+// type token of a struct, the Def suffix of the topology types is dropped
+func typeToken(t reflect.Type, fallback string) string {
+	name, ok := typeNames[t]
+	if !ok {
+		name = strings.TrimSuffix(t.Name(), "Def")
+	}
+	if name == "" {
+		name = fallback
+	}
+	return Name + ":index:" + name
+}
+
+// schema properties of the yaml fields of a struct, anonymous structs are named after prefix and the field
+func (b *schemaBuilder) properties(t reflect.Type, prefix string) map[string]interface{} {
+	props := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, skip := fieldKey(field, b.tag)
+		if skip {
+			continue
+		}
+		props[propertyName(key)] = b.typeSpec(field.Type, prefix+field.Name)
+	}
+	return props
+}
+
+// schema type of a go type, structs become object types of the package
+func (b *schemaBuilder) typeSpec(t reflect.Type, name string) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": b.typeSpec(t.Elem(), name)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.typeSpec(t.Elem(), name)}
+	case reflect.Struct:
+		token := typeToken(t, name)
+		if _, ok := b.types[token]; !ok {
+			b.types[token] = map[string]interface{}{
+				"type":       "object",
+				"properties": b.properties(t, name),
+			}
+		}
+		return map[string]interface{}{"$ref": "#/types/" + token}
+	default:
+		// helm values, kubelet configuration and patches are passed as is
+		return map[string]interface{}{"$ref": "pulumi.json#/Any"}
+	}
+}
+
+// resource reference to a component of the package
+func resourceRef(typ string, description string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/resources/" + typ, "description": description}
+}
+
+// inputs of the cluster component, the options of a cluster of the topology, its credentials and its bastion
+func (b *schemaBuilder) clusterInputs() map[string]interface{} {
+	inputs := b.properties(reflect.TypeOf(k8s.Cluster{}), "")
+	credentials := &schemaBuilder{types: b.types, tag: "json"}
+	creds := credentials.typeSpec(reflect.TypeOf(clusterCredentials{}), "")
+	creds["secret"] = true
+	creds["description"] = "backup, registry and gitops credentials of the cluster, as in the stack configuration of the topology program"
+	inputs["credentials"] = creds
+	inputs["bastion"] = resourceRef(k8s.BastionType, "bastion of the network the cluster is created on")
+	inputs["clusterName"] = map[string]interface{}{
+		"type":        "string",
+		"description": "name of the cluster in the clusters of the network, defaults to the resource name",
+	}
+	return inputs
+}
+
+// package schema of the Network, Bastion and Cluster components
+func Schema() ([]byte, error) {
+	b := &schemaBuilder{types: map[string]interface{}{}, tag: "yaml"}
+
+	networkInputs := map[string]interface{}{
+		"clusterMesh": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "names of the clusters in the cilium cluster mesh, the mesh is connected once they are all created",
+		},
+		"ntp": map[string]interface{}{
+			"$ref":        "#/types/" + Name + ":index:Ntp",
+			"description": "time servers of the bastion, the distribution defaults when unset",
+		},
+	}
+	for name, description := range infraInputs {
+		networkInputs[name] = map[string]interface{}{"type": "string", "description": description}
+	}
+	networkInputs["hcloudToken"].(map[string]interface{})["secret"] = true
+
+	workDir := map[string]interface{}{
+		"type":        "string",
+		"description": "directory holding the playbooks in .ansible and the rendered files in vars, defaults to " + WorkDirEnv + " or the directory the plugin is started in",
+	}
+	clusterInputs := b.clusterInputs()
+	output := func(typ string, description string) map[string]interface{} {
+		return map[string]interface{}{"type": typ, "description": description}
+	}
+
+	spec := map[string]interface{}{
+		"name":        Name,
+		"version":     Version,
+		"displayName": "Hetzner Cloud kubeadm",
+		"description": "Kubernetes clusters on Hetzner Cloud, installed with kubeadm",
+		"keywords":    []string{"pulumi", "hcloud", "kubernetes", "kubeadm", "category/cloud", "kind/component"},
+		"types":       b.types,
+		"config": map[string]interface{}{
+			"variables": map[string]interface{}{"workDir": workDir},
+		},
+		"provider": map[string]interface{}{
+			"description":     "The provider type for the hcloudkubeadm package.",
+			"inputProperties": map[string]interface{}{"workDir": workDir},
+		},
+		"resources": map[string]interface{}{
+			k8s.NetworkType: map[string]interface{}{
+				"isComponent":     true,
+				"description":     "Private network, firewalls and ssh key shared by the clusters.",
+				"inputProperties": networkInputs,
+				"requiredInputs":  []string{"dataCenter", "image", "lbType", "masterFlavor", "networkZone", "sshUser", "workerFlavor"},
+				"properties": map[string]interface{}{
+					"networkId":  output("string", "id of the private network"),
+					"privateKey": map[string]interface{}{"type": "string", "secret": true, "description": "private key of the ssh user of the servers"},
+				},
+				"required": []string{"networkId", "privateKey"},
+			},
+			k8s.BastionType: map[string]interface{}{
+				"isComponent": true,
+				"description": "Bastion and NAT gateway of a network, the playbooks run through it.",
+				"inputProperties": map[string]interface{}{
+					"network": resourceRef(k8s.NetworkType, "network of the bastion"),
+				},
+				"requiredInputs": []string{"network"},
+				"properties": map[string]interface{}{
+					"publicIp": output("string", "public IP of the bastion"),
+				},
+				"required": []string{"publicIp"},
+			},
+			k8s.ClusterType: map[string]interface{}{
+				"isComponent":     true,
+				"description":     "Kubernetes cluster of the network of a bastion, with its servers, load balancer and PKI.",
+				"inputProperties": clusterInputs,
+				"requiredInputs":  []string{"bastion"},
+				"properties": map[string]interface{}{
+					"kubeconfig":        map[string]interface{}{"type": "string", "secret": true, "description": "admin kubeconfig"},
+					"apiServer":         output("string", "API server URL"),
+					"endpoints":         map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}, "description": "public addresses of the API server and the node ports, and their type"},
+					"controlPlaneNodes": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "private IPs of the control plane nodes"},
+					"workerNodes":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "private IPs of the workers"},
+					"provisioning":      map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"$ref": "pulumi.json#/Any"}, "description": "hosts changed, failed or unreachable by each provisioning step"},
+					"config":            map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"$ref": "pulumi.json#/Any"}, "secret": true, "description": "entry of the cluster in the clusters output of the topology program"},
+				},
+				"required": []string{"apiServer", "config", "controlPlaneNodes", "endpoints", "kubeconfig", "provisioning", "workerNodes"},
+			},
+		},
+		"language": map[string]interface{}{
+			"go": map[string]interface{}{
+				"importBasePath":                 "pulumi-hcloud-kubeadm/sdk/go/hcloudkubeadm",
+				"generateResourceContainerTypes": true,
+			},
+			"nodejs": map[string]interface{}{
+				"packageName": "@pulumi-hcloud-kubeadm/hcloudkubeadm",
+			},
+			"python": map[string]interface{}{
+				"packageName": "pulumi_hcloudkubeadm",
+			},
+		},
+	}
+	return json.MarshalIndent(spec, "", "  ")
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"gopkg.in/yaml.v2"
+
+	"pulumi-hcloud-kubeadm/k8s"
+	"pulumi-hcloud-kubeadm/sdk/go/hcloudkubeadm"
+)
+
+// components registered by a program using the generated Go SDK, by type
+func sdkProgram(t *testing.T) map[string]resource.PropertyMap {
+	t.Helper()
+	m := newMocks()
+	err := pulumi.RunErr(func(ctx *pulumi.Context) error {
+		network, err := hcloudkubeadm.NewNetwork(ctx, "network", &hcloudkubeadm.NetworkArgs{
+			WorkerFlavor: pulumi.String("cpx41"), MasterFlavor: pulumi.String("cpx31"), LbType: pulumi.String("lb11"),
+			Image: pulumi.String("ubuntu-22.04"), NetworkZone: pulumi.String("eu-central"), DataCenter: pulumi.String("fsn1-dc14"),
+			SshUser: pulumi.String("root"), HcloudToken: pulumi.String("token"),
+			Ntp: &hcloudkubeadm.NtpArgs{Primary: pulumi.String("ntp1.hetzner.de")},
+		})
+		if err != nil {
+			return err
+		}
+		bastion, err := hcloudkubeadm.NewBastion(ctx, "bastion", &hcloudkubeadm.BastionArgs{Network: network})
+		if err != nil {
+			return err
+		}
+		_, err = hcloudkubeadm.NewCluster(ctx, "apps", &hcloudkubeadm.ClusterArgs{
+			Bastion:           bastion,
+			Cri:               pulumi.String("containerd"),
+			KubernetesVersion: pulumi.String("1.30.2"),
+			Cni:               &hcloudkubeadm.CNIArgs{Name: pulumi.String("calico")},
+			ControlPlane:      &hcloudkubeadm.ControlPlaneArgs{NodeCount: pulumi.Int(1)},
+			Worker:            &hcloudkubeadm.WorkerArgs{NodeCount: pulumi.Int(1), Labels: pulumi.StringMap{"tier": pulumi.String("apps")}},
+			Registries: &hcloudkubeadm.RegistriesArgs{Hosts: hcloudkubeadm.RegistryMap{
+				"registry.example.com": &hcloudkubeadm.RegistryArgs{Mirrors: pulumi.StringArray{pulumi.String("https://mirror.example.com")}},
+			}},
+			Credentials: &hcloudkubeadm.ClusterCredentialsArgs{Registries: hcloudkubeadm.RegistryCredentialsMap{
+				"registry.example.com": &hcloudkubeadm.RegistryCredentialsArgs{Username: pulumi.String("user"), Password: pulumi.String("password")},
+			}},
+		})
+		return err
+	}, pulumi.WithMocks("project", "stack", m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]resource.PropertyMap{
+		k8s.NetworkType: m.resource(k8s.NetworkType, "network"),
+		k8s.BastionType: m.resource(k8s.BastionType, "bastion"),
+		k8s.ClusterType: m.resource(k8s.ClusterType, "apps"),
+	}
+}
+
+// plain inputs as the provider gets them, the resource references are replaced by refs
+func plainInputs(t *testing.T, inputs resource.PropertyMap, refs map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	props := map[string]interface{}{}
+	for k, v := range inputs {
+		if v.IsResourceReference() {
+			ref, ok := refs[string(v.ResourceReferenceValue().URN.Name())]
+			if !ok {
+				t.Fatalf("%s references %s", k, v.ResourceReferenceValue().URN)
+			}
+			props[string(k)] = ref
+			continue
+		}
+		if v.IsSecret() {
+			v = v.SecretValue().Element
+		}
+		props[string(k)] = v.Mappable()
+	}
+	return props
+}
+
+// the components of the generated SDK are constructed by the provider into the resources of the k8s package
+func TestSDKComponents(t *testing.T) {
+	components := sdkProgram(t)
+	for typ, inputs := range components {
+		if inputs == nil {
+			t.Fatalf("no %s registered through the SDK", typ)
+		}
+	}
+	if !components[k8s.ClusterType]["credentials"].IsSecret() {
+		t.Error("the SDK does not send the credentials as a secret")
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "vars"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	fake := k8s.NewFakeProvisioner()
+	p := &componentProvider{provisioner: fake, networks: map[string]*networkEntry{}, bastions: map[string]*bastionEntry{}}
+	m := newMocks()
+	var cluster *k8s.K8sCluster
+	err = pulumi.RunErr(func(ctx *pulumi.Context) error {
+		network, err := p.constructNetwork(ctx, "network", plainInputs(t, components[k8s.NetworkType], nil), pulumi.Composite())
+		if err != nil {
+			return err
+		}
+		refs := map[string]interface{}{"network": network}
+		bastion, err := p.constructBastion(ctx, "bastion", plainInputs(t, components[k8s.BastionType], refs), pulumi.Composite())
+		if err != nil {
+			return err
+		}
+		refs["bastion"] = bastion
+		cluster, err = p.constructCluster(ctx, "apps", plainInputs(t, components[k8s.ClusterType], refs), pulumi.Composite())
+		return err
+	}, pulumi.WithMocks("project", "stack", m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster == nil {
+		t.Fatal("no cluster")
+	}
+	for _, network := range p.networks {
+		if network.infra.Ntp.Primary != "ntp1.hetzner.de" {
+			t.Errorf("ntp of the bastion %+v", network.infra.Ntp)
+		}
+	}
+	if steps := strings.Join(fake.Steps(), ","); steps != "bastion:,nodes:apps,init:apps,join:apps,addons:apps,kubeconfig:apps" {
+		t.Errorf("steps %s", steps)
+	}
+	server := m.resource("hcloud:index/server:Server", "worker-apps-0")
+	if server == nil || server["serverType"].StringValue() != "cpx41" || server["image"].StringValue() != "ubuntu-22.04" {
+		t.Errorf("worker server %v", server)
+	}
+	// calico BGP is opened by the firewall of the cluster
+	firewall := m.resource("hcloud:index/firewall:Firewall", "worker-firewall-apps")
+	if firewall == nil || !strings.Contains(fmt.Sprint(firewall.Mappable()), "179") {
+		t.Errorf("worker firewall %v", firewall)
+	}
+}
+
+// the options sent by the SDK decode into the cluster of the topology
+func TestSDKClusterOptions(t *testing.T) {
+	inputs := sdkProgram(t)[k8s.ClusterType]
+	options, rest := splitClusterInputs(plainInputs(t, inputs, map[string]interface{}{"bastion": nil}))
+	decoded, err := decodeCluster(options, "apps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := k8s.Cluster{Cri: "containerd", KubernetesVersion: "1.30.2", Cni: k8s.CNIDef{Name: "calico"}}
+	want.ControlPlane.NodeCount = 1
+	want.Worker.NodeCount = 1
+	want.Worker.Labels = map[string]string{"tier": "apps"}
+	want.Registries.Hosts = map[string]k8s.RegistryDef{"registry.example.com": {Mirrors: []string{"https://mirror.example.com"}}}
+	got, _ := yaml.Marshal(decoded)
+	expected, _ := yaml.Marshal(want)
+	if string(got) != string(expected) {
+		t.Errorf("decoded cluster:\n%s\nwant:\n%s", got, expected)
+	}
+	creds, err := decodeCredentials(rest["credentials"].(map[string]interface{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.Registries["registry.example.com"].Password != "password" {
+		t.Errorf("credentials %+v", creds)
+	}
+}
+
+// the playbooks and the generated variables are found in the work directory, not in the directory pulumi runs the program in
+func TestEnterWorkDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	dir := t.TempDir()
+	if err := enterWorkDir(dir); err == nil || !strings.Contains(err.Error(), WorkDirEnv) {
+		t.Errorf("a work directory without playbooks: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".ansible"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".ansible", "install.yaml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := enterWorkDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "vars")); err != nil {
+		t.Error(err)
+	}
+	// relative paths are resolved against the work directory
+	if _, err := os.Stat(filepath.Join(".ansible", "install.yaml")); err != nil {
+		t.Error(err)
+	}
+}
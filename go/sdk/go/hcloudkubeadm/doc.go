@@ -0,0 +1,2 @@
+// Kubernetes clusters on Hetzner Cloud, installed with kubeadm
+package hcloudkubeadm
@@ -0,0 +1,70 @@
+// Code generated by pulumi-hcloud-kubeadm-sdkgen DO NOT EDIT.
+// *** WARNING: Do not edit by hand unless you're certain you know what you are doing! ***
+
+package hcloudkubeadm
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"pulumi-hcloud-kubeadm/sdk/go/hcloudkubeadm/internal"
+)
+
+type module struct {
+	version semver.Version
+}
+
+func (m *module) Version() semver.Version {
+	return m.version
+}
+
+func (m *module) Construct(ctx *pulumi.Context, name, typ, urn string) (r pulumi.Resource, err error) {
+	switch typ {
+	case "hcloudkubeadm:index:Bastion":
+		r = &Bastion{}
+	case "hcloudkubeadm:index:Cluster":
+		r = &Cluster{}
+	case "hcloudkubeadm:index:Network":
+		r = &Network{}
+	default:
+		return nil, fmt.Errorf("unknown resource type: %s", typ)
+	}
+
+	err = ctx.RegisterResource(typ, name, nil, r, pulumi.URN_(urn))
+	return
+}
+
+type pkg struct {
+	version semver.Version
+}
+
+func (p *pkg) Version() semver.Version {
+	return p.version
+}
+
+func (p *pkg) ConstructProvider(ctx *pulumi.Context, name, typ, urn string) (pulumi.ProviderResource, error) {
+	if typ != "pulumi:providers:hcloudkubeadm" {
+		return nil, fmt.Errorf("unknown provider type: %s", typ)
+	}
+
+	r := &Provider{}
+	err := ctx.RegisterResource(typ, name, nil, r, pulumi.URN_(urn))
+	return r, err
+}
+
+func init() {
+	version, err := internal.PkgVersion()
+	if err != nil {
+		version = semver.Version{Major: 1}
+	}
+	pulumi.RegisterResourceModule(
+		"hcloudkubeadm",
+		"index",
+		&module{version},
+	)
+	pulumi.RegisterResourcePackage(
+		"hcloudkubeadm",
+		&pkg{version},
+	)
+}
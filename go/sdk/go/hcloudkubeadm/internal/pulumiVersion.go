@@ -0,0 +1,11 @@
+// Code generated by pulumi-hcloud-kubeadm-sdkgen DO NOT EDIT.
+// *** WARNING: Do not edit by hand unless you're certain you know what you are doing! ***
+
+package internal
+
+import (
+	"github.com/blang/semver"
+)
+
+var SdkVersion semver.Version = semver.Version{}
+var pluginDownloadURL string = ""
@@ -0,0 +1,260 @@
+// Code generated by pulumi-hcloud-kubeadm-sdkgen DO NOT EDIT.
+// *** WARNING: Do not edit by hand unless you're certain you know what you are doing! ***
+
+package hcloudkubeadm
+
+import (
+	"context"
+	"reflect"
+
+	"errors"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"pulumi-hcloud-kubeadm/sdk/go/hcloudkubeadm/internal"
+)
+
+// Private network, firewalls and ssh key shared by the clusters.
+type Network struct {
+	pulumi.ResourceState
+
+	// id of the private network
+	NetworkId pulumi.StringOutput `pulumi:"networkId"`
+	// private key of the ssh user of the servers
+	PrivateKey pulumi.StringOutput `pulumi:"privateKey"`
+}
+
+// NewNetwork registers a new resource with the given unique name, arguments, and options.
+func NewNetwork(ctx *pulumi.Context,
+	name string, args *NetworkArgs, opts ...pulumi.ResourceOption) (*Network, error) {
+	if args == nil {
+		return nil, errors.New("missing one or more required arguments")
+	}
+
+	if args.DataCenter == nil {
+		return nil, errors.New("invalid value for required argument 'DataCenter'")
+	}
+	if args.Image == nil {
+		return nil, errors.New("invalid value for required argument 'Image'")
+	}
+	if args.LbType == nil {
+		return nil, errors.New("invalid value for required argument 'LbType'")
+	}
+	if args.MasterFlavor == nil {
+		return nil, errors.New("invalid value for required argument 'MasterFlavor'")
+	}
+	if args.NetworkZone == nil {
+		return nil, errors.New("invalid value for required argument 'NetworkZone'")
+	}
+	if args.SshUser == nil {
+		return nil, errors.New("invalid value for required argument 'SshUser'")
+	}
+	if args.WorkerFlavor == nil {
+		return nil, errors.New("invalid value for required argument 'WorkerFlavor'")
+	}
+	if args.HcloudToken != nil {
+		args.HcloudToken = pulumi.ToSecret(args.HcloudToken).(pulumi.StringPtrInput)
+	}
+	secrets := pulumi.AdditionalSecretOutputs([]string{
+		"privateKey",
+	})
+	opts = append(opts, secrets)
+	opts = internal.PkgResourceDefaultOpts(opts)
+	var resource Network
+	err := ctx.RegisterRemoteComponentResource("hcloudkubeadm:index:Network", name, args, &resource, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+type networkArgs struct {
+	// names of the clusters in the cilium cluster mesh, the mesh is connected once they are all created
+	ClusterMesh []string `pulumi:"clusterMesh"`
+	// data center of the servers, e.g. fsn1-dc14
+	DataCenter string `pulumi:"dataCenter"`
+	// hetzner cloud API token, used by the cluster autoscaler and the cloud controller manager
+	HcloudToken *string `pulumi:"hcloudToken"`
+	// server image, e.g. ubuntu-22.04
+	Image string `pulumi:"image"`
+	// load balancer type, e.g. lb11
+	LbType string `pulumi:"lbType"`
+	// server type of the control plane nodes, e.g. cx21
+	MasterFlavor string `pulumi:"masterFlavor"`
+	// network zone, e.g. eu-central
+	NetworkZone string `pulumi:"networkZone"`
+	// time servers of the bastion, the distribution defaults when unset
+	Ntp *Ntp `pulumi:"ntp"`
+	// user the playbooks connect as
+	SshUser string `pulumi:"sshUser"`
+	// server type of the workers, e.g. cx31
+	WorkerFlavor string `pulumi:"workerFlavor"`
+}
+
+// The set of arguments for constructing a Network resource.
+type NetworkArgs struct {
+	// names of the clusters in the cilium cluster mesh, the mesh is connected once they are all created
+	ClusterMesh pulumi.StringArrayInput
+	// data center of the servers, e.g. fsn1-dc14
+	DataCenter pulumi.StringInput
+	// hetzner cloud API token, used by the cluster autoscaler and the cloud controller manager
+	HcloudToken pulumi.StringPtrInput
+	// server image, e.g. ubuntu-22.04
+	Image pulumi.StringInput
+	// load balancer type, e.g. lb11
+	LbType pulumi.StringInput
+	// server type of the control plane nodes, e.g. cx21
+	MasterFlavor pulumi.StringInput
+	// network zone, e.g. eu-central
+	NetworkZone pulumi.StringInput
+	// time servers of the bastion, the distribution defaults when unset
+	Ntp NtpPtrInput
+	// user the playbooks connect as
+	SshUser pulumi.StringInput
+	// server type of the workers, e.g. cx31
+	WorkerFlavor pulumi.StringInput
+}
+
+func (NetworkArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*networkArgs)(nil)).Elem()
+}
+
+type NetworkInput interface {
+	pulumi.Input
+
+	ToNetworkOutput() NetworkOutput
+	ToNetworkOutputWithContext(ctx context.Context) NetworkOutput
+}
+
+func (*Network) ElementType() reflect.Type {
+	return reflect.TypeOf((**Network)(nil)).Elem()
+}
+
+func (i *Network) ToNetworkOutput() NetworkOutput {
+	return i.ToNetworkOutputWithContext(context.Background())
+}
+
+func (i *Network) ToNetworkOutputWithContext(ctx context.Context) NetworkOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NetworkOutput)
+}
+
+// NetworkArrayInput is an input type that accepts NetworkArray and NetworkArrayOutput values.
+// You can construct a concrete instance of `NetworkArrayInput` via:
+//
+//	NetworkArray{ NetworkArgs{...} }
+type NetworkArrayInput interface {
+	pulumi.Input
+
+	ToNetworkArrayOutput() NetworkArrayOutput
+	ToNetworkArrayOutputWithContext(context.Context) NetworkArrayOutput
+}
+
+type NetworkArray []NetworkInput
+
+func (NetworkArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]*Network)(nil)).Elem()
+}
+
+func (i NetworkArray) ToNetworkArrayOutput() NetworkArrayOutput {
+	return i.ToNetworkArrayOutputWithContext(context.Background())
+}
+
+func (i NetworkArray) ToNetworkArrayOutputWithContext(ctx context.Context) NetworkArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NetworkArrayOutput)
+}
+
+// NetworkMapInput is an input type that accepts NetworkMap and NetworkMapOutput values.
+// You can construct a concrete instance of `NetworkMapInput` via:
+//
+//	NetworkMap{ "key": NetworkArgs{...} }
+type NetworkMapInput interface {
+	pulumi.Input
+
+	ToNetworkMapOutput() NetworkMapOutput
+	ToNetworkMapOutputWithContext(context.Context) NetworkMapOutput
+}
+
+type NetworkMap map[string]NetworkInput
+
+func (NetworkMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]*Network)(nil)).Elem()
+}
+
+func (i NetworkMap) ToNetworkMapOutput() NetworkMapOutput {
+	return i.ToNetworkMapOutputWithContext(context.Background())
+}
+
+func (i NetworkMap) ToNetworkMapOutputWithContext(ctx context.Context) NetworkMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NetworkMapOutput)
+}
+
+type NetworkOutput struct{ *pulumi.OutputState }
+
+func (NetworkOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Network)(nil)).Elem()
+}
+
+func (o NetworkOutput) ToNetworkOutput() NetworkOutput {
+	return o
+}
+
+func (o NetworkOutput) ToNetworkOutputWithContext(ctx context.Context) NetworkOutput {
+	return o
+}
+
+// id of the private network
+func (o NetworkOutput) NetworkId() pulumi.StringOutput {
+	return o.ApplyT(func(v *Network) pulumi.StringOutput { return v.NetworkId }).(pulumi.StringOutput)
+}
+
+// private key of the ssh user of the servers
+func (o NetworkOutput) PrivateKey() pulumi.StringOutput {
+	return o.ApplyT(func(v *Network) pulumi.StringOutput { return v.PrivateKey }).(pulumi.StringOutput)
+}
+
+type NetworkArrayOutput struct{ *pulumi.OutputState }
+
+func (NetworkArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]*Network)(nil)).Elem()
+}
+
+func (o NetworkArrayOutput) ToNetworkArrayOutput() NetworkArrayOutput {
+	return o
+}
+
+func (o NetworkArrayOutput) ToNetworkArrayOutputWithContext(ctx context.Context) NetworkArrayOutput {
+	return o
+}
+
+func (o NetworkArrayOutput) Index(i pulumi.IntInput) NetworkOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) *Network {
+		return vs[0].([]*Network)[vs[1].(int)]
+	}).(NetworkOutput)
+}
+
+type NetworkMapOutput struct{ *pulumi.OutputState }
+
+func (NetworkMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]*Network)(nil)).Elem()
+}
+
+func (o NetworkMapOutput) ToNetworkMapOutput() NetworkMapOutput {
+	return o
+}
+
+func (o NetworkMapOutput) ToNetworkMapOutputWithContext(ctx context.Context) NetworkMapOutput {
+	return o
+}
+
+func (o NetworkMapOutput) MapIndex(k pulumi.StringInput) NetworkOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) *Network {
+		return vs[0].(map[string]*Network)[vs[1].(string)]
+	}).(NetworkOutput)
+}
+
+func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*NetworkInput)(nil)).Elem(), &Network{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NetworkArrayInput)(nil)).Elem(), NetworkArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NetworkMapInput)(nil)).Elem(), NetworkMap{})
+	pulumi.RegisterOutputType(NetworkOutput{})
+	pulumi.RegisterOutputType(NetworkArrayOutput{})
+	pulumi.RegisterOutputType(NetworkMapOutput{})
+}
@@ -0,0 +1,4838 @@
+// Code generated by pulumi-hcloud-kubeadm-sdkgen DO NOT EDIT.
+// *** WARNING: Do not edit by hand unless you're certain you know what you are doing! ***
+
+package hcloudkubeadm
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"pulumi-hcloud-kubeadm/sdk/go/hcloudkubeadm/internal"
+)
+
+var _ = internal.GetEnvOrDefault
+
+type Addon struct {
+	Enabled    *bool                  `pulumi:"enabled"`
+	Values     map[string]interface{} `pulumi:"values"`
+	ValuesFile *string                `pulumi:"valuesFile"`
+	Version    *string                `pulumi:"version"`
+}
+
+// AddonInput is an input type that accepts AddonArgs and AddonOutput values.
+// You can construct a concrete instance of `AddonInput` via:
+//
+//	AddonArgs{...}
+type AddonInput interface {
+	pulumi.Input
+
+	ToAddonOutput() AddonOutput
+	ToAddonOutputWithContext(context.Context) AddonOutput
+}
+
+type AddonArgs struct {
+	Enabled    pulumi.BoolPtrInput   `pulumi:"enabled"`
+	Values     pulumi.MapInput       `pulumi:"values"`
+	ValuesFile pulumi.StringPtrInput `pulumi:"valuesFile"`
+	Version    pulumi.StringPtrInput `pulumi:"version"`
+}
+
+func (AddonArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Addon)(nil)).Elem()
+}
+
+func (i AddonArgs) ToAddonOutput() AddonOutput {
+	return i.ToAddonOutputWithContext(context.Background())
+}
+
+func (i AddonArgs) ToAddonOutputWithContext(ctx context.Context) AddonOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(AddonOutput)
+}
+
+// AddonMapInput is an input type that accepts AddonMap and AddonMapOutput values.
+// You can construct a concrete instance of `AddonMapInput` via:
+//
+//	AddonMap{ "key": AddonArgs{...} }
+type AddonMapInput interface {
+	pulumi.Input
+
+	ToAddonMapOutput() AddonMapOutput
+	ToAddonMapOutputWithContext(context.Context) AddonMapOutput
+}
+
+type AddonMap map[string]AddonInput
+
+func (AddonMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]Addon)(nil)).Elem()
+}
+
+func (i AddonMap) ToAddonMapOutput() AddonMapOutput {
+	return i.ToAddonMapOutputWithContext(context.Background())
+}
+
+func (i AddonMap) ToAddonMapOutputWithContext(ctx context.Context) AddonMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(AddonMapOutput)
+}
+
+type AddonOutput struct{ *pulumi.OutputState }
+
+func (AddonOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Addon)(nil)).Elem()
+}
+
+func (o AddonOutput) ToAddonOutput() AddonOutput {
+	return o
+}
+
+func (o AddonOutput) ToAddonOutputWithContext(ctx context.Context) AddonOutput {
+	return o
+}
+
+func (o AddonOutput) Enabled() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v Addon) *bool { return v.Enabled }).(pulumi.BoolPtrOutput)
+}
+
+func (o AddonOutput) Values() pulumi.MapOutput {
+	return o.ApplyT(func(v Addon) map[string]interface{} { return v.Values }).(pulumi.MapOutput)
+}
+
+func (o AddonOutput) ValuesFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Addon) *string { return v.ValuesFile }).(pulumi.StringPtrOutput)
+}
+
+func (o AddonOutput) Version() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Addon) *string { return v.Version }).(pulumi.StringPtrOutput)
+}
+
+type AddonMapOutput struct{ *pulumi.OutputState }
+
+func (AddonMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]Addon)(nil)).Elem()
+}
+
+func (o AddonMapOutput) ToAddonMapOutput() AddonMapOutput {
+	return o
+}
+
+func (o AddonMapOutput) ToAddonMapOutputWithContext(ctx context.Context) AddonMapOutput {
+	return o
+}
+
+func (o AddonMapOutput) MapIndex(k pulumi.StringInput) AddonOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) Addon {
+		return vs[0].(map[string]Addon)[vs[1].(string)]
+	}).(AddonOutput)
+}
+
+type Audit struct {
+	MaxAge     *int    `pulumi:"maxAge"`
+	MaxBackup  *int    `pulumi:"maxBackup"`
+	MaxSize    *int    `pulumi:"maxSize"`
+	PolicyFile *string `pulumi:"policyFile"`
+	Profile    *string `pulumi:"profile"`
+}
+
+// AuditInput is an input type that accepts AuditArgs and AuditOutput values.
+// You can construct a concrete instance of `AuditInput` via:
+//
+//	AuditArgs{...}
+type AuditInput interface {
+	pulumi.Input
+
+	ToAuditOutput() AuditOutput
+	ToAuditOutputWithContext(context.Context) AuditOutput
+}
+
+type AuditArgs struct {
+	MaxAge     pulumi.IntPtrInput    `pulumi:"maxAge"`
+	MaxBackup  pulumi.IntPtrInput    `pulumi:"maxBackup"`
+	MaxSize    pulumi.IntPtrInput    `pulumi:"maxSize"`
+	PolicyFile pulumi.StringPtrInput `pulumi:"policyFile"`
+	Profile    pulumi.StringPtrInput `pulumi:"profile"`
+}
+
+func (AuditArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Audit)(nil)).Elem()
+}
+
+func (i AuditArgs) ToAuditOutput() AuditOutput {
+	return i.ToAuditOutputWithContext(context.Background())
+}
+
+func (i AuditArgs) ToAuditOutputWithContext(ctx context.Context) AuditOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(AuditOutput)
+}
+
+func (i AuditArgs) ToAuditPtrOutput() AuditPtrOutput {
+	return i.ToAuditPtrOutputWithContext(context.Background())
+}
+
+func (i AuditArgs) ToAuditPtrOutputWithContext(ctx context.Context) AuditPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(AuditOutput).ToAuditPtrOutputWithContext(ctx)
+}
+
+// AuditPtrInput is an input type that accepts AuditArgs, AuditPtr and AuditPtrOutput values.
+// You can construct a concrete instance of `AuditPtrInput` via:
+//
+//	        AuditArgs{...}
+//
+//	or:
+//
+//	        nil
+type AuditPtrInput interface {
+	pulumi.Input
+
+	ToAuditPtrOutput() AuditPtrOutput
+	ToAuditPtrOutputWithContext(context.Context) AuditPtrOutput
+}
+
+type auditPtrType AuditArgs
+
+func AuditPtr(v *AuditArgs) AuditPtrInput {
+	return (*auditPtrType)(v)
+}
+
+func (*auditPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Audit)(nil)).Elem()
+}
+
+func (i *auditPtrType) ToAuditPtrOutput() AuditPtrOutput {
+	return i.ToAuditPtrOutputWithContext(context.Background())
+}
+
+func (i *auditPtrType) ToAuditPtrOutputWithContext(ctx context.Context) AuditPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(AuditPtrOutput)
+}
+
+type AuditOutput struct{ *pulumi.OutputState }
+
+func (AuditOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Audit)(nil)).Elem()
+}
+
+func (o AuditOutput) ToAuditOutput() AuditOutput {
+	return o
+}
+
+func (o AuditOutput) ToAuditOutputWithContext(ctx context.Context) AuditOutput {
+	return o
+}
+
+func (o AuditOutput) ToAuditPtrOutput() AuditPtrOutput {
+	return o.ToAuditPtrOutputWithContext(context.Background())
+}
+
+func (o AuditOutput) ToAuditPtrOutputWithContext(ctx context.Context) AuditPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Audit) *Audit {
+		return &v
+	}).(AuditPtrOutput)
+}
+
+func (o AuditOutput) MaxAge() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Audit) *int { return v.MaxAge }).(pulumi.IntPtrOutput)
+}
+
+func (o AuditOutput) MaxBackup() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Audit) *int { return v.MaxBackup }).(pulumi.IntPtrOutput)
+}
+
+func (o AuditOutput) MaxSize() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Audit) *int { return v.MaxSize }).(pulumi.IntPtrOutput)
+}
+
+func (o AuditOutput) PolicyFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Audit) *string { return v.PolicyFile }).(pulumi.StringPtrOutput)
+}
+
+func (o AuditOutput) Profile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Audit) *string { return v.Profile }).(pulumi.StringPtrOutput)
+}
+
+type AuditPtrOutput struct{ *pulumi.OutputState }
+
+func (AuditPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Audit)(nil)).Elem()
+}
+
+func (o AuditPtrOutput) ToAuditPtrOutput() AuditPtrOutput {
+	return o
+}
+
+func (o AuditPtrOutput) ToAuditPtrOutputWithContext(ctx context.Context) AuditPtrOutput {
+	return o
+}
+
+func (o AuditPtrOutput) Elem() AuditOutput {
+	return o.ApplyT(func(v *Audit) Audit {
+		if v != nil {
+			return *v
+		}
+		var ret Audit
+		return ret
+	}).(AuditOutput)
+}
+
+func (o AuditPtrOutput) MaxAge() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Audit) *int {
+		if v == nil {
+			return nil
+		}
+		return v.MaxAge
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o AuditPtrOutput) MaxBackup() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Audit) *int {
+		if v == nil {
+			return nil
+		}
+		return v.MaxBackup
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o AuditPtrOutput) MaxSize() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Audit) *int {
+		if v == nil {
+			return nil
+		}
+		return v.MaxSize
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o AuditPtrOutput) PolicyFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Audit) *string {
+		if v == nil {
+			return nil
+		}
+		return v.PolicyFile
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o AuditPtrOutput) Profile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Audit) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Profile
+	}).(pulumi.StringPtrOutput)
+}
+
+type Backup struct {
+	Enabled       *bool   `pulumi:"enabled"`
+	Endpoint      *string `pulumi:"endpoint"`
+	Restore       *string `pulumi:"restore"`
+	RetentionDays *int    `pulumi:"retentionDays"`
+	Schedule      *string `pulumi:"schedule"`
+}
+
+// BackupInput is an input type that accepts BackupArgs and BackupOutput values.
+// You can construct a concrete instance of `BackupInput` via:
+//
+//	BackupArgs{...}
+type BackupInput interface {
+	pulumi.Input
+
+	ToBackupOutput() BackupOutput
+	ToBackupOutputWithContext(context.Context) BackupOutput
+}
+
+type BackupArgs struct {
+	Enabled       pulumi.BoolPtrInput   `pulumi:"enabled"`
+	Endpoint      pulumi.StringPtrInput `pulumi:"endpoint"`
+	Restore       pulumi.StringPtrInput `pulumi:"restore"`
+	RetentionDays pulumi.IntPtrInput    `pulumi:"retentionDays"`
+	Schedule      pulumi.StringPtrInput `pulumi:"schedule"`
+}
+
+func (BackupArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Backup)(nil)).Elem()
+}
+
+func (i BackupArgs) ToBackupOutput() BackupOutput {
+	return i.ToBackupOutputWithContext(context.Background())
+}
+
+func (i BackupArgs) ToBackupOutputWithContext(ctx context.Context) BackupOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(BackupOutput)
+}
+
+func (i BackupArgs) ToBackupPtrOutput() BackupPtrOutput {
+	return i.ToBackupPtrOutputWithContext(context.Background())
+}
+
+func (i BackupArgs) ToBackupPtrOutputWithContext(ctx context.Context) BackupPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(BackupOutput).ToBackupPtrOutputWithContext(ctx)
+}
+
+// BackupPtrInput is an input type that accepts BackupArgs, BackupPtr and BackupPtrOutput values.
+// You can construct a concrete instance of `BackupPtrInput` via:
+//
+//	        BackupArgs{...}
+//
+//	or:
+//
+//	        nil
+type BackupPtrInput interface {
+	pulumi.Input
+
+	ToBackupPtrOutput() BackupPtrOutput
+	ToBackupPtrOutputWithContext(context.Context) BackupPtrOutput
+}
+
+type backupPtrType BackupArgs
+
+func BackupPtr(v *BackupArgs) BackupPtrInput {
+	return (*backupPtrType)(v)
+}
+
+func (*backupPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Backup)(nil)).Elem()
+}
+
+func (i *backupPtrType) ToBackupPtrOutput() BackupPtrOutput {
+	return i.ToBackupPtrOutputWithContext(context.Background())
+}
+
+func (i *backupPtrType) ToBackupPtrOutputWithContext(ctx context.Context) BackupPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(BackupPtrOutput)
+}
+
+type BackupOutput struct{ *pulumi.OutputState }
+
+func (BackupOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Backup)(nil)).Elem()
+}
+
+func (o BackupOutput) ToBackupOutput() BackupOutput {
+	return o
+}
+
+func (o BackupOutput) ToBackupOutputWithContext(ctx context.Context) BackupOutput {
+	return o
+}
+
+func (o BackupOutput) ToBackupPtrOutput() BackupPtrOutput {
+	return o.ToBackupPtrOutputWithContext(context.Background())
+}
+
+func (o BackupOutput) ToBackupPtrOutputWithContext(ctx context.Context) BackupPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Backup) *Backup {
+		return &v
+	}).(BackupPtrOutput)
+}
+
+func (o BackupOutput) Enabled() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v Backup) *bool { return v.Enabled }).(pulumi.BoolPtrOutput)
+}
+
+func (o BackupOutput) Endpoint() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Backup) *string { return v.Endpoint }).(pulumi.StringPtrOutput)
+}
+
+func (o BackupOutput) Restore() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Backup) *string { return v.Restore }).(pulumi.StringPtrOutput)
+}
+
+func (o BackupOutput) RetentionDays() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Backup) *int { return v.RetentionDays }).(pulumi.IntPtrOutput)
+}
+
+func (o BackupOutput) Schedule() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Backup) *string { return v.Schedule }).(pulumi.StringPtrOutput)
+}
+
+type BackupPtrOutput struct{ *pulumi.OutputState }
+
+func (BackupPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Backup)(nil)).Elem()
+}
+
+func (o BackupPtrOutput) ToBackupPtrOutput() BackupPtrOutput {
+	return o
+}
+
+func (o BackupPtrOutput) ToBackupPtrOutputWithContext(ctx context.Context) BackupPtrOutput {
+	return o
+}
+
+func (o BackupPtrOutput) Elem() BackupOutput {
+	return o.ApplyT(func(v *Backup) Backup {
+		if v != nil {
+			return *v
+		}
+		var ret Backup
+		return ret
+	}).(BackupOutput)
+}
+
+func (o BackupPtrOutput) Enabled() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v *Backup) *bool {
+		if v == nil {
+			return nil
+		}
+		return v.Enabled
+	}).(pulumi.BoolPtrOutput)
+}
+
+func (o BackupPtrOutput) Endpoint() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Backup) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Endpoint
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o BackupPtrOutput) Restore() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Backup) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Restore
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o BackupPtrOutput) RetentionDays() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Backup) *int {
+		if v == nil {
+			return nil
+		}
+		return v.RetentionDays
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o BackupPtrOutput) Schedule() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Backup) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Schedule
+	}).(pulumi.StringPtrOutput)
+}
+
+type BackupCredentials struct {
+	AccessKey *string `pulumi:"accessKey"`
+	Bucket    *string `pulumi:"bucket"`
+	SecretKey *string `pulumi:"secretKey"`
+}
+
+// BackupCredentialsInput is an input type that accepts BackupCredentialsArgs and BackupCredentialsOutput values.
+// You can construct a concrete instance of `BackupCredentialsInput` via:
+//
+//	BackupCredentialsArgs{...}
+type BackupCredentialsInput interface {
+	pulumi.Input
+
+	ToBackupCredentialsOutput() BackupCredentialsOutput
+	ToBackupCredentialsOutputWithContext(context.Context) BackupCredentialsOutput
+}
+
+type BackupCredentialsArgs struct {
+	AccessKey pulumi.StringPtrInput `pulumi:"accessKey"`
+	Bucket    pulumi.StringPtrInput `pulumi:"bucket"`
+	SecretKey pulumi.StringPtrInput `pulumi:"secretKey"`
+}
+
+func (BackupCredentialsArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*BackupCredentials)(nil)).Elem()
+}
+
+func (i BackupCredentialsArgs) ToBackupCredentialsOutput() BackupCredentialsOutput {
+	return i.ToBackupCredentialsOutputWithContext(context.Background())
+}
+
+func (i BackupCredentialsArgs) ToBackupCredentialsOutputWithContext(ctx context.Context) BackupCredentialsOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(BackupCredentialsOutput)
+}
+
+func (i BackupCredentialsArgs) ToBackupCredentialsPtrOutput() BackupCredentialsPtrOutput {
+	return i.ToBackupCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (i BackupCredentialsArgs) ToBackupCredentialsPtrOutputWithContext(ctx context.Context) BackupCredentialsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(BackupCredentialsOutput).ToBackupCredentialsPtrOutputWithContext(ctx)
+}
+
+// BackupCredentialsPtrInput is an input type that accepts BackupCredentialsArgs, BackupCredentialsPtr and BackupCredentialsPtrOutput values.
+// You can construct a concrete instance of `BackupCredentialsPtrInput` via:
+//
+//	        BackupCredentialsArgs{...}
+//
+//	or:
+//
+//	        nil
+type BackupCredentialsPtrInput interface {
+	pulumi.Input
+
+	ToBackupCredentialsPtrOutput() BackupCredentialsPtrOutput
+	ToBackupCredentialsPtrOutputWithContext(context.Context) BackupCredentialsPtrOutput
+}
+
+type backupCredentialsPtrType BackupCredentialsArgs
+
+func BackupCredentialsPtr(v *BackupCredentialsArgs) BackupCredentialsPtrInput {
+	return (*backupCredentialsPtrType)(v)
+}
+
+func (*backupCredentialsPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**BackupCredentials)(nil)).Elem()
+}
+
+func (i *backupCredentialsPtrType) ToBackupCredentialsPtrOutput() BackupCredentialsPtrOutput {
+	return i.ToBackupCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (i *backupCredentialsPtrType) ToBackupCredentialsPtrOutputWithContext(ctx context.Context) BackupCredentialsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(BackupCredentialsPtrOutput)
+}
+
+type BackupCredentialsOutput struct{ *pulumi.OutputState }
+
+func (BackupCredentialsOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*BackupCredentials)(nil)).Elem()
+}
+
+func (o BackupCredentialsOutput) ToBackupCredentialsOutput() BackupCredentialsOutput {
+	return o
+}
+
+func (o BackupCredentialsOutput) ToBackupCredentialsOutputWithContext(ctx context.Context) BackupCredentialsOutput {
+	return o
+}
+
+func (o BackupCredentialsOutput) ToBackupCredentialsPtrOutput() BackupCredentialsPtrOutput {
+	return o.ToBackupCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (o BackupCredentialsOutput) ToBackupCredentialsPtrOutputWithContext(ctx context.Context) BackupCredentialsPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v BackupCredentials) *BackupCredentials {
+		return &v
+	}).(BackupCredentialsPtrOutput)
+}
+
+func (o BackupCredentialsOutput) AccessKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v BackupCredentials) *string { return v.AccessKey }).(pulumi.StringPtrOutput)
+}
+
+func (o BackupCredentialsOutput) Bucket() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v BackupCredentials) *string { return v.Bucket }).(pulumi.StringPtrOutput)
+}
+
+func (o BackupCredentialsOutput) SecretKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v BackupCredentials) *string { return v.SecretKey }).(pulumi.StringPtrOutput)
+}
+
+type BackupCredentialsPtrOutput struct{ *pulumi.OutputState }
+
+func (BackupCredentialsPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**BackupCredentials)(nil)).Elem()
+}
+
+func (o BackupCredentialsPtrOutput) ToBackupCredentialsPtrOutput() BackupCredentialsPtrOutput {
+	return o
+}
+
+func (o BackupCredentialsPtrOutput) ToBackupCredentialsPtrOutputWithContext(ctx context.Context) BackupCredentialsPtrOutput {
+	return o
+}
+
+func (o BackupCredentialsPtrOutput) Elem() BackupCredentialsOutput {
+	return o.ApplyT(func(v *BackupCredentials) BackupCredentials {
+		if v != nil {
+			return *v
+		}
+		var ret BackupCredentials
+		return ret
+	}).(BackupCredentialsOutput)
+}
+
+func (o BackupCredentialsPtrOutput) AccessKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *BackupCredentials) *string {
+		if v == nil {
+			return nil
+		}
+		return v.AccessKey
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o BackupCredentialsPtrOutput) Bucket() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *BackupCredentials) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Bucket
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o BackupCredentialsPtrOutput) SecretKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *BackupCredentials) *string {
+		if v == nil {
+			return nil
+		}
+		return v.SecretKey
+	}).(pulumi.StringPtrOutput)
+}
+
+type CNI struct {
+	Name       *string                `pulumi:"name"`
+	Values     map[string]interface{} `pulumi:"values"`
+	ValuesFile *string                `pulumi:"valuesFile"`
+	Version    *string                `pulumi:"version"`
+}
+
+// CNIInput is an input type that accepts CNIArgs and CNIOutput values.
+// You can construct a concrete instance of `CNIInput` via:
+//
+//	CNIArgs{...}
+type CNIInput interface {
+	pulumi.Input
+
+	ToCNIOutput() CNIOutput
+	ToCNIOutputWithContext(context.Context) CNIOutput
+}
+
+type CNIArgs struct {
+	Name       pulumi.StringPtrInput `pulumi:"name"`
+	Values     pulumi.MapInput       `pulumi:"values"`
+	ValuesFile pulumi.StringPtrInput `pulumi:"valuesFile"`
+	Version    pulumi.StringPtrInput `pulumi:"version"`
+}
+
+func (CNIArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*CNI)(nil)).Elem()
+}
+
+func (i CNIArgs) ToCNIOutput() CNIOutput {
+	return i.ToCNIOutputWithContext(context.Background())
+}
+
+func (i CNIArgs) ToCNIOutputWithContext(ctx context.Context) CNIOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CNIOutput)
+}
+
+func (i CNIArgs) ToCNIPtrOutput() CNIPtrOutput {
+	return i.ToCNIPtrOutputWithContext(context.Background())
+}
+
+func (i CNIArgs) ToCNIPtrOutputWithContext(ctx context.Context) CNIPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CNIOutput).ToCNIPtrOutputWithContext(ctx)
+}
+
+// CNIPtrInput is an input type that accepts CNIArgs, CNIPtr and CNIPtrOutput values.
+// You can construct a concrete instance of `CNIPtrInput` via:
+//
+//	        CNIArgs{...}
+//
+//	or:
+//
+//	        nil
+type CNIPtrInput interface {
+	pulumi.Input
+
+	ToCNIPtrOutput() CNIPtrOutput
+	ToCNIPtrOutputWithContext(context.Context) CNIPtrOutput
+}
+
+type cniPtrType CNIArgs
+
+func CNIPtr(v *CNIArgs) CNIPtrInput {
+	return (*cniPtrType)(v)
+}
+
+func (*cniPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**CNI)(nil)).Elem()
+}
+
+func (i *cniPtrType) ToCNIPtrOutput() CNIPtrOutput {
+	return i.ToCNIPtrOutputWithContext(context.Background())
+}
+
+func (i *cniPtrType) ToCNIPtrOutputWithContext(ctx context.Context) CNIPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CNIPtrOutput)
+}
+
+type CNIOutput struct{ *pulumi.OutputState }
+
+func (CNIOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*CNI)(nil)).Elem()
+}
+
+func (o CNIOutput) ToCNIOutput() CNIOutput {
+	return o
+}
+
+func (o CNIOutput) ToCNIOutputWithContext(ctx context.Context) CNIOutput {
+	return o
+}
+
+func (o CNIOutput) ToCNIPtrOutput() CNIPtrOutput {
+	return o.ToCNIPtrOutputWithContext(context.Background())
+}
+
+func (o CNIOutput) ToCNIPtrOutputWithContext(ctx context.Context) CNIPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v CNI) *CNI {
+		return &v
+	}).(CNIPtrOutput)
+}
+
+func (o CNIOutput) Name() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v CNI) *string { return v.Name }).(pulumi.StringPtrOutput)
+}
+
+func (o CNIOutput) Values() pulumi.MapOutput {
+	return o.ApplyT(func(v CNI) map[string]interface{} { return v.Values }).(pulumi.MapOutput)
+}
+
+func (o CNIOutput) ValuesFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v CNI) *string { return v.ValuesFile }).(pulumi.StringPtrOutput)
+}
+
+func (o CNIOutput) Version() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v CNI) *string { return v.Version }).(pulumi.StringPtrOutput)
+}
+
+type CNIPtrOutput struct{ *pulumi.OutputState }
+
+func (CNIPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**CNI)(nil)).Elem()
+}
+
+func (o CNIPtrOutput) ToCNIPtrOutput() CNIPtrOutput {
+	return o
+}
+
+func (o CNIPtrOutput) ToCNIPtrOutputWithContext(ctx context.Context) CNIPtrOutput {
+	return o
+}
+
+func (o CNIPtrOutput) Elem() CNIOutput {
+	return o.ApplyT(func(v *CNI) CNI {
+		if v != nil {
+			return *v
+		}
+		var ret CNI
+		return ret
+	}).(CNIOutput)
+}
+
+func (o CNIPtrOutput) Name() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *CNI) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Name
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o CNIPtrOutput) Values() pulumi.MapOutput {
+	return o.ApplyT(func(v *CNI) map[string]interface{} {
+		if v == nil {
+			return nil
+		}
+		return v.Values
+	}).(pulumi.MapOutput)
+}
+
+func (o CNIPtrOutput) ValuesFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *CNI) *string {
+		if v == nil {
+			return nil
+		}
+		return v.ValuesFile
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o CNIPtrOutput) Version() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *CNI) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Version
+	}).(pulumi.StringPtrOutput)
+}
+
+type Certificates struct {
+	Refresh *string `pulumi:"refresh"`
+	Rotate  *string `pulumi:"rotate"`
+}
+
+// CertificatesInput is an input type that accepts CertificatesArgs and CertificatesOutput values.
+// You can construct a concrete instance of `CertificatesInput` via:
+//
+//	CertificatesArgs{...}
+type CertificatesInput interface {
+	pulumi.Input
+
+	ToCertificatesOutput() CertificatesOutput
+	ToCertificatesOutputWithContext(context.Context) CertificatesOutput
+}
+
+type CertificatesArgs struct {
+	Refresh pulumi.StringPtrInput `pulumi:"refresh"`
+	Rotate  pulumi.StringPtrInput `pulumi:"rotate"`
+}
+
+func (CertificatesArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Certificates)(nil)).Elem()
+}
+
+func (i CertificatesArgs) ToCertificatesOutput() CertificatesOutput {
+	return i.ToCertificatesOutputWithContext(context.Background())
+}
+
+func (i CertificatesArgs) ToCertificatesOutputWithContext(ctx context.Context) CertificatesOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CertificatesOutput)
+}
+
+func (i CertificatesArgs) ToCertificatesPtrOutput() CertificatesPtrOutput {
+	return i.ToCertificatesPtrOutputWithContext(context.Background())
+}
+
+func (i CertificatesArgs) ToCertificatesPtrOutputWithContext(ctx context.Context) CertificatesPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CertificatesOutput).ToCertificatesPtrOutputWithContext(ctx)
+}
+
+// CertificatesPtrInput is an input type that accepts CertificatesArgs, CertificatesPtr and CertificatesPtrOutput values.
+// You can construct a concrete instance of `CertificatesPtrInput` via:
+//
+//	        CertificatesArgs{...}
+//
+//	or:
+//
+//	        nil
+type CertificatesPtrInput interface {
+	pulumi.Input
+
+	ToCertificatesPtrOutput() CertificatesPtrOutput
+	ToCertificatesPtrOutputWithContext(context.Context) CertificatesPtrOutput
+}
+
+type certificatesPtrType CertificatesArgs
+
+func CertificatesPtr(v *CertificatesArgs) CertificatesPtrInput {
+	return (*certificatesPtrType)(v)
+}
+
+func (*certificatesPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Certificates)(nil)).Elem()
+}
+
+func (i *certificatesPtrType) ToCertificatesPtrOutput() CertificatesPtrOutput {
+	return i.ToCertificatesPtrOutputWithContext(context.Background())
+}
+
+func (i *certificatesPtrType) ToCertificatesPtrOutputWithContext(ctx context.Context) CertificatesPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CertificatesPtrOutput)
+}
+
+type CertificatesOutput struct{ *pulumi.OutputState }
+
+func (CertificatesOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Certificates)(nil)).Elem()
+}
+
+func (o CertificatesOutput) ToCertificatesOutput() CertificatesOutput {
+	return o
+}
+
+func (o CertificatesOutput) ToCertificatesOutputWithContext(ctx context.Context) CertificatesOutput {
+	return o
+}
+
+func (o CertificatesOutput) ToCertificatesPtrOutput() CertificatesPtrOutput {
+	return o.ToCertificatesPtrOutputWithContext(context.Background())
+}
+
+func (o CertificatesOutput) ToCertificatesPtrOutputWithContext(ctx context.Context) CertificatesPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Certificates) *Certificates {
+		return &v
+	}).(CertificatesPtrOutput)
+}
+
+func (o CertificatesOutput) Refresh() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Certificates) *string { return v.Refresh }).(pulumi.StringPtrOutput)
+}
+
+func (o CertificatesOutput) Rotate() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Certificates) *string { return v.Rotate }).(pulumi.StringPtrOutput)
+}
+
+type CertificatesPtrOutput struct{ *pulumi.OutputState }
+
+func (CertificatesPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Certificates)(nil)).Elem()
+}
+
+func (o CertificatesPtrOutput) ToCertificatesPtrOutput() CertificatesPtrOutput {
+	return o
+}
+
+func (o CertificatesPtrOutput) ToCertificatesPtrOutputWithContext(ctx context.Context) CertificatesPtrOutput {
+	return o
+}
+
+func (o CertificatesPtrOutput) Elem() CertificatesOutput {
+	return o.ApplyT(func(v *Certificates) Certificates {
+		if v != nil {
+			return *v
+		}
+		var ret Certificates
+		return ret
+	}).(CertificatesOutput)
+}
+
+func (o CertificatesPtrOutput) Refresh() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Certificates) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Refresh
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o CertificatesPtrOutput) Rotate() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Certificates) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Rotate
+	}).(pulumi.StringPtrOutput)
+}
+
+type Cilium struct {
+	ClusterMesh   *ClusterMesh `pulumi:"clusterMesh"`
+	Hubble        *Hubble      `pulumi:"hubble"`
+	NativeRouting *bool        `pulumi:"nativeRouting"`
+	PodCidr       *string      `pulumi:"podCidr"`
+	Wireguard     *bool        `pulumi:"wireguard"`
+}
+
+// CiliumInput is an input type that accepts CiliumArgs and CiliumOutput values.
+// You can construct a concrete instance of `CiliumInput` via:
+//
+//	CiliumArgs{...}
+type CiliumInput interface {
+	pulumi.Input
+
+	ToCiliumOutput() CiliumOutput
+	ToCiliumOutputWithContext(context.Context) CiliumOutput
+}
+
+type CiliumArgs struct {
+	ClusterMesh   ClusterMeshPtrInput   `pulumi:"clusterMesh"`
+	Hubble        HubblePtrInput        `pulumi:"hubble"`
+	NativeRouting pulumi.BoolPtrInput   `pulumi:"nativeRouting"`
+	PodCidr       pulumi.StringPtrInput `pulumi:"podCidr"`
+	Wireguard     pulumi.BoolPtrInput   `pulumi:"wireguard"`
+}
+
+func (CiliumArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Cilium)(nil)).Elem()
+}
+
+func (i CiliumArgs) ToCiliumOutput() CiliumOutput {
+	return i.ToCiliumOutputWithContext(context.Background())
+}
+
+func (i CiliumArgs) ToCiliumOutputWithContext(ctx context.Context) CiliumOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CiliumOutput)
+}
+
+func (i CiliumArgs) ToCiliumPtrOutput() CiliumPtrOutput {
+	return i.ToCiliumPtrOutputWithContext(context.Background())
+}
+
+func (i CiliumArgs) ToCiliumPtrOutputWithContext(ctx context.Context) CiliumPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CiliumOutput).ToCiliumPtrOutputWithContext(ctx)
+}
+
+// CiliumPtrInput is an input type that accepts CiliumArgs, CiliumPtr and CiliumPtrOutput values.
+// You can construct a concrete instance of `CiliumPtrInput` via:
+//
+//	        CiliumArgs{...}
+//
+//	or:
+//
+//	        nil
+type CiliumPtrInput interface {
+	pulumi.Input
+
+	ToCiliumPtrOutput() CiliumPtrOutput
+	ToCiliumPtrOutputWithContext(context.Context) CiliumPtrOutput
+}
+
+type ciliumPtrType CiliumArgs
+
+func CiliumPtr(v *CiliumArgs) CiliumPtrInput {
+	return (*ciliumPtrType)(v)
+}
+
+func (*ciliumPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Cilium)(nil)).Elem()
+}
+
+func (i *ciliumPtrType) ToCiliumPtrOutput() CiliumPtrOutput {
+	return i.ToCiliumPtrOutputWithContext(context.Background())
+}
+
+func (i *ciliumPtrType) ToCiliumPtrOutputWithContext(ctx context.Context) CiliumPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(CiliumPtrOutput)
+}
+
+type CiliumOutput struct{ *pulumi.OutputState }
+
+func (CiliumOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Cilium)(nil)).Elem()
+}
+
+func (o CiliumOutput) ToCiliumOutput() CiliumOutput {
+	return o
+}
+
+func (o CiliumOutput) ToCiliumOutputWithContext(ctx context.Context) CiliumOutput {
+	return o
+}
+
+func (o CiliumOutput) ToCiliumPtrOutput() CiliumPtrOutput {
+	return o.ToCiliumPtrOutputWithContext(context.Background())
+}
+
+func (o CiliumOutput) ToCiliumPtrOutputWithContext(ctx context.Context) CiliumPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Cilium) *Cilium {
+		return &v
+	}).(CiliumPtrOutput)
+}
+
+func (o CiliumOutput) ClusterMesh() ClusterMeshPtrOutput {
+	return o.ApplyT(func(v Cilium) *ClusterMesh { return v.ClusterMesh }).(ClusterMeshPtrOutput)
+}
+
+func (o CiliumOutput) Hubble() HubblePtrOutput {
+	return o.ApplyT(func(v Cilium) *Hubble { return v.Hubble }).(HubblePtrOutput)
+}
+
+func (o CiliumOutput) NativeRouting() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v Cilium) *bool { return v.NativeRouting }).(pulumi.BoolPtrOutput)
+}
+
+func (o CiliumOutput) PodCidr() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Cilium) *string { return v.PodCidr }).(pulumi.StringPtrOutput)
+}
+
+func (o CiliumOutput) Wireguard() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v Cilium) *bool { return v.Wireguard }).(pulumi.BoolPtrOutput)
+}
+
+type CiliumPtrOutput struct{ *pulumi.OutputState }
+
+func (CiliumPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Cilium)(nil)).Elem()
+}
+
+func (o CiliumPtrOutput) ToCiliumPtrOutput() CiliumPtrOutput {
+	return o
+}
+
+func (o CiliumPtrOutput) ToCiliumPtrOutputWithContext(ctx context.Context) CiliumPtrOutput {
+	return o
+}
+
+func (o CiliumPtrOutput) Elem() CiliumOutput {
+	return o.ApplyT(func(v *Cilium) Cilium {
+		if v != nil {
+			return *v
+		}
+		var ret Cilium
+		return ret
+	}).(CiliumOutput)
+}
+
+func (o CiliumPtrOutput) ClusterMesh() ClusterMeshPtrOutput {
+	return o.ApplyT(func(v *Cilium) *ClusterMesh {
+		if v == nil {
+			return nil
+		}
+		return v.ClusterMesh
+	}).(ClusterMeshPtrOutput)
+}
+
+func (o CiliumPtrOutput) Hubble() HubblePtrOutput {
+	return o.ApplyT(func(v *Cilium) *Hubble {
+		if v == nil {
+			return nil
+		}
+		return v.Hubble
+	}).(HubblePtrOutput)
+}
+
+func (o CiliumPtrOutput) NativeRouting() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v *Cilium) *bool {
+		if v == nil {
+			return nil
+		}
+		return v.NativeRouting
+	}).(pulumi.BoolPtrOutput)
+}
+
+func (o CiliumPtrOutput) PodCidr() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Cilium) *string {
+		if v == nil {
+			return nil
+		}
+		return v.PodCidr
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o CiliumPtrOutput) Wireguard() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v *Cilium) *bool {
+		if v == nil {
+			return nil
+		}
+		return v.Wireguard
+	}).(pulumi.BoolPtrOutput)
+}
+
+type ClusterCredentials struct {
+	Backup     *BackupCredentials             `pulumi:"backup"`
+	Gitops     *GitOpsCredentials             `pulumi:"gitops"`
+	Registries map[string]RegistryCredentials `pulumi:"registries"`
+}
+
+// ClusterCredentialsInput is an input type that accepts ClusterCredentialsArgs and ClusterCredentialsOutput values.
+// You can construct a concrete instance of `ClusterCredentialsInput` via:
+//
+//	ClusterCredentialsArgs{...}
+type ClusterCredentialsInput interface {
+	pulumi.Input
+
+	ToClusterCredentialsOutput() ClusterCredentialsOutput
+	ToClusterCredentialsOutputWithContext(context.Context) ClusterCredentialsOutput
+}
+
+type ClusterCredentialsArgs struct {
+	Backup     BackupCredentialsPtrInput   `pulumi:"backup"`
+	Gitops     GitOpsCredentialsPtrInput   `pulumi:"gitops"`
+	Registries RegistryCredentialsMapInput `pulumi:"registries"`
+}
+
+func (ClusterCredentialsArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*ClusterCredentials)(nil)).Elem()
+}
+
+func (i ClusterCredentialsArgs) ToClusterCredentialsOutput() ClusterCredentialsOutput {
+	return i.ToClusterCredentialsOutputWithContext(context.Background())
+}
+
+func (i ClusterCredentialsArgs) ToClusterCredentialsOutputWithContext(ctx context.Context) ClusterCredentialsOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterCredentialsOutput)
+}
+
+func (i ClusterCredentialsArgs) ToClusterCredentialsPtrOutput() ClusterCredentialsPtrOutput {
+	return i.ToClusterCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (i ClusterCredentialsArgs) ToClusterCredentialsPtrOutputWithContext(ctx context.Context) ClusterCredentialsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterCredentialsOutput).ToClusterCredentialsPtrOutputWithContext(ctx)
+}
+
+// ClusterCredentialsPtrInput is an input type that accepts ClusterCredentialsArgs, ClusterCredentialsPtr and ClusterCredentialsPtrOutput values.
+// You can construct a concrete instance of `ClusterCredentialsPtrInput` via:
+//
+//	        ClusterCredentialsArgs{...}
+//
+//	or:
+//
+//	        nil
+type ClusterCredentialsPtrInput interface {
+	pulumi.Input
+
+	ToClusterCredentialsPtrOutput() ClusterCredentialsPtrOutput
+	ToClusterCredentialsPtrOutputWithContext(context.Context) ClusterCredentialsPtrOutput
+}
+
+type clusterCredentialsPtrType ClusterCredentialsArgs
+
+func ClusterCredentialsPtr(v *ClusterCredentialsArgs) ClusterCredentialsPtrInput {
+	return (*clusterCredentialsPtrType)(v)
+}
+
+func (*clusterCredentialsPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**ClusterCredentials)(nil)).Elem()
+}
+
+func (i *clusterCredentialsPtrType) ToClusterCredentialsPtrOutput() ClusterCredentialsPtrOutput {
+	return i.ToClusterCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (i *clusterCredentialsPtrType) ToClusterCredentialsPtrOutputWithContext(ctx context.Context) ClusterCredentialsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterCredentialsPtrOutput)
+}
+
+type ClusterCredentialsOutput struct{ *pulumi.OutputState }
+
+func (ClusterCredentialsOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*ClusterCredentials)(nil)).Elem()
+}
+
+func (o ClusterCredentialsOutput) ToClusterCredentialsOutput() ClusterCredentialsOutput {
+	return o
+}
+
+func (o ClusterCredentialsOutput) ToClusterCredentialsOutputWithContext(ctx context.Context) ClusterCredentialsOutput {
+	return o
+}
+
+func (o ClusterCredentialsOutput) ToClusterCredentialsPtrOutput() ClusterCredentialsPtrOutput {
+	return o.ToClusterCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (o ClusterCredentialsOutput) ToClusterCredentialsPtrOutputWithContext(ctx context.Context) ClusterCredentialsPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v ClusterCredentials) *ClusterCredentials {
+		return &v
+	}).(ClusterCredentialsPtrOutput)
+}
+
+func (o ClusterCredentialsOutput) Backup() BackupCredentialsPtrOutput {
+	return o.ApplyT(func(v ClusterCredentials) *BackupCredentials { return v.Backup }).(BackupCredentialsPtrOutput)
+}
+
+func (o ClusterCredentialsOutput) Gitops() GitOpsCredentialsPtrOutput {
+	return o.ApplyT(func(v ClusterCredentials) *GitOpsCredentials { return v.Gitops }).(GitOpsCredentialsPtrOutput)
+}
+
+func (o ClusterCredentialsOutput) Registries() RegistryCredentialsMapOutput {
+	return o.ApplyT(func(v ClusterCredentials) map[string]RegistryCredentials { return v.Registries }).(RegistryCredentialsMapOutput)
+}
+
+type ClusterCredentialsPtrOutput struct{ *pulumi.OutputState }
+
+func (ClusterCredentialsPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**ClusterCredentials)(nil)).Elem()
+}
+
+func (o ClusterCredentialsPtrOutput) ToClusterCredentialsPtrOutput() ClusterCredentialsPtrOutput {
+	return o
+}
+
+func (o ClusterCredentialsPtrOutput) ToClusterCredentialsPtrOutputWithContext(ctx context.Context) ClusterCredentialsPtrOutput {
+	return o
+}
+
+func (o ClusterCredentialsPtrOutput) Elem() ClusterCredentialsOutput {
+	return o.ApplyT(func(v *ClusterCredentials) ClusterCredentials {
+		if v != nil {
+			return *v
+		}
+		var ret ClusterCredentials
+		return ret
+	}).(ClusterCredentialsOutput)
+}
+
+func (o ClusterCredentialsPtrOutput) Backup() BackupCredentialsPtrOutput {
+	return o.ApplyT(func(v *ClusterCredentials) *BackupCredentials {
+		if v == nil {
+			return nil
+		}
+		return v.Backup
+	}).(BackupCredentialsPtrOutput)
+}
+
+func (o ClusterCredentialsPtrOutput) Gitops() GitOpsCredentialsPtrOutput {
+	return o.ApplyT(func(v *ClusterCredentials) *GitOpsCredentials {
+		if v == nil {
+			return nil
+		}
+		return v.Gitops
+	}).(GitOpsCredentialsPtrOutput)
+}
+
+func (o ClusterCredentialsPtrOutput) Registries() RegistryCredentialsMapOutput {
+	return o.ApplyT(func(v *ClusterCredentials) map[string]RegistryCredentials {
+		if v == nil {
+			return nil
+		}
+		return v.Registries
+	}).(RegistryCredentialsMapOutput)
+}
+
+type ClusterMesh struct {
+	ClusterId *int `pulumi:"clusterId"`
+}
+
+// ClusterMeshInput is an input type that accepts ClusterMeshArgs and ClusterMeshOutput values.
+// You can construct a concrete instance of `ClusterMeshInput` via:
+//
+//	ClusterMeshArgs{...}
+type ClusterMeshInput interface {
+	pulumi.Input
+
+	ToClusterMeshOutput() ClusterMeshOutput
+	ToClusterMeshOutputWithContext(context.Context) ClusterMeshOutput
+}
+
+type ClusterMeshArgs struct {
+	ClusterId pulumi.IntPtrInput `pulumi:"clusterId"`
+}
+
+func (ClusterMeshArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*ClusterMesh)(nil)).Elem()
+}
+
+func (i ClusterMeshArgs) ToClusterMeshOutput() ClusterMeshOutput {
+	return i.ToClusterMeshOutputWithContext(context.Background())
+}
+
+func (i ClusterMeshArgs) ToClusterMeshOutputWithContext(ctx context.Context) ClusterMeshOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterMeshOutput)
+}
+
+func (i ClusterMeshArgs) ToClusterMeshPtrOutput() ClusterMeshPtrOutput {
+	return i.ToClusterMeshPtrOutputWithContext(context.Background())
+}
+
+func (i ClusterMeshArgs) ToClusterMeshPtrOutputWithContext(ctx context.Context) ClusterMeshPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterMeshOutput).ToClusterMeshPtrOutputWithContext(ctx)
+}
+
+// ClusterMeshPtrInput is an input type that accepts ClusterMeshArgs, ClusterMeshPtr and ClusterMeshPtrOutput values.
+// You can construct a concrete instance of `ClusterMeshPtrInput` via:
+//
+//	        ClusterMeshArgs{...}
+//
+//	or:
+//
+//	        nil
+type ClusterMeshPtrInput interface {
+	pulumi.Input
+
+	ToClusterMeshPtrOutput() ClusterMeshPtrOutput
+	ToClusterMeshPtrOutputWithContext(context.Context) ClusterMeshPtrOutput
+}
+
+type clusterMeshPtrType ClusterMeshArgs
+
+func ClusterMeshPtr(v *ClusterMeshArgs) ClusterMeshPtrInput {
+	return (*clusterMeshPtrType)(v)
+}
+
+func (*clusterMeshPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**ClusterMesh)(nil)).Elem()
+}
+
+func (i *clusterMeshPtrType) ToClusterMeshPtrOutput() ClusterMeshPtrOutput {
+	return i.ToClusterMeshPtrOutputWithContext(context.Background())
+}
+
+func (i *clusterMeshPtrType) ToClusterMeshPtrOutputWithContext(ctx context.Context) ClusterMeshPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ClusterMeshPtrOutput)
+}
+
+type ClusterMeshOutput struct{ *pulumi.OutputState }
+
+func (ClusterMeshOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*ClusterMesh)(nil)).Elem()
+}
+
+func (o ClusterMeshOutput) ToClusterMeshOutput() ClusterMeshOutput {
+	return o
+}
+
+func (o ClusterMeshOutput) ToClusterMeshOutputWithContext(ctx context.Context) ClusterMeshOutput {
+	return o
+}
+
+func (o ClusterMeshOutput) ToClusterMeshPtrOutput() ClusterMeshPtrOutput {
+	return o.ToClusterMeshPtrOutputWithContext(context.Background())
+}
+
+func (o ClusterMeshOutput) ToClusterMeshPtrOutputWithContext(ctx context.Context) ClusterMeshPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v ClusterMesh) *ClusterMesh {
+		return &v
+	}).(ClusterMeshPtrOutput)
+}
+
+func (o ClusterMeshOutput) ClusterId() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v ClusterMesh) *int { return v.ClusterId }).(pulumi.IntPtrOutput)
+}
+
+type ClusterMeshPtrOutput struct{ *pulumi.OutputState }
+
+func (ClusterMeshPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**ClusterMesh)(nil)).Elem()
+}
+
+func (o ClusterMeshPtrOutput) ToClusterMeshPtrOutput() ClusterMeshPtrOutput {
+	return o
+}
+
+func (o ClusterMeshPtrOutput) ToClusterMeshPtrOutputWithContext(ctx context.Context) ClusterMeshPtrOutput {
+	return o
+}
+
+func (o ClusterMeshPtrOutput) Elem() ClusterMeshOutput {
+	return o.ApplyT(func(v *ClusterMesh) ClusterMesh {
+		if v != nil {
+			return *v
+		}
+		var ret ClusterMesh
+		return ret
+	}).(ClusterMeshOutput)
+}
+
+func (o ClusterMeshPtrOutput) ClusterId() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *ClusterMesh) *int {
+		if v == nil {
+			return nil
+		}
+		return v.ClusterId
+	}).(pulumi.IntPtrOutput)
+}
+
+type Component struct {
+	ExtraArgs map[string]string `pulumi:"extraArgs"`
+}
+
+// ComponentInput is an input type that accepts ComponentArgs and ComponentOutput values.
+// You can construct a concrete instance of `ComponentInput` via:
+//
+//	ComponentArgs{...}
+type ComponentInput interface {
+	pulumi.Input
+
+	ToComponentOutput() ComponentOutput
+	ToComponentOutputWithContext(context.Context) ComponentOutput
+}
+
+type ComponentArgs struct {
+	ExtraArgs pulumi.StringMapInput `pulumi:"extraArgs"`
+}
+
+func (ComponentArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Component)(nil)).Elem()
+}
+
+func (i ComponentArgs) ToComponentOutput() ComponentOutput {
+	return i.ToComponentOutputWithContext(context.Background())
+}
+
+func (i ComponentArgs) ToComponentOutputWithContext(ctx context.Context) ComponentOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ComponentOutput)
+}
+
+func (i ComponentArgs) ToComponentPtrOutput() ComponentPtrOutput {
+	return i.ToComponentPtrOutputWithContext(context.Background())
+}
+
+func (i ComponentArgs) ToComponentPtrOutputWithContext(ctx context.Context) ComponentPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ComponentOutput).ToComponentPtrOutputWithContext(ctx)
+}
+
+// ComponentPtrInput is an input type that accepts ComponentArgs, ComponentPtr and ComponentPtrOutput values.
+// You can construct a concrete instance of `ComponentPtrInput` via:
+//
+//	        ComponentArgs{...}
+//
+//	or:
+//
+//	        nil
+type ComponentPtrInput interface {
+	pulumi.Input
+
+	ToComponentPtrOutput() ComponentPtrOutput
+	ToComponentPtrOutputWithContext(context.Context) ComponentPtrOutput
+}
+
+type componentPtrType ComponentArgs
+
+func ComponentPtr(v *ComponentArgs) ComponentPtrInput {
+	return (*componentPtrType)(v)
+}
+
+func (*componentPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Component)(nil)).Elem()
+}
+
+func (i *componentPtrType) ToComponentPtrOutput() ComponentPtrOutput {
+	return i.ToComponentPtrOutputWithContext(context.Background())
+}
+
+func (i *componentPtrType) ToComponentPtrOutputWithContext(ctx context.Context) ComponentPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ComponentPtrOutput)
+}
+
+type ComponentOutput struct{ *pulumi.OutputState }
+
+func (ComponentOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Component)(nil)).Elem()
+}
+
+func (o ComponentOutput) ToComponentOutput() ComponentOutput {
+	return o
+}
+
+func (o ComponentOutput) ToComponentOutputWithContext(ctx context.Context) ComponentOutput {
+	return o
+}
+
+func (o ComponentOutput) ToComponentPtrOutput() ComponentPtrOutput {
+	return o.ToComponentPtrOutputWithContext(context.Background())
+}
+
+func (o ComponentOutput) ToComponentPtrOutputWithContext(ctx context.Context) ComponentPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Component) *Component {
+		return &v
+	}).(ComponentPtrOutput)
+}
+
+func (o ComponentOutput) ExtraArgs() pulumi.StringMapOutput {
+	return o.ApplyT(func(v Component) map[string]string { return v.ExtraArgs }).(pulumi.StringMapOutput)
+}
+
+type ComponentPtrOutput struct{ *pulumi.OutputState }
+
+func (ComponentPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Component)(nil)).Elem()
+}
+
+func (o ComponentPtrOutput) ToComponentPtrOutput() ComponentPtrOutput {
+	return o
+}
+
+func (o ComponentPtrOutput) ToComponentPtrOutputWithContext(ctx context.Context) ComponentPtrOutput {
+	return o
+}
+
+func (o ComponentPtrOutput) Elem() ComponentOutput {
+	return o.ApplyT(func(v *Component) Component {
+		if v != nil {
+			return *v
+		}
+		var ret Component
+		return ret
+	}).(ComponentOutput)
+}
+
+func (o ComponentPtrOutput) ExtraArgs() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *Component) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.ExtraArgs
+	}).(pulumi.StringMapOutput)
+}
+
+type ControlPlane struct {
+	NodeCount *int `pulumi:"nodeCount"`
+}
+
+// ControlPlaneInput is an input type that accepts ControlPlaneArgs and ControlPlaneOutput values.
+// You can construct a concrete instance of `ControlPlaneInput` via:
+//
+//	ControlPlaneArgs{...}
+type ControlPlaneInput interface {
+	pulumi.Input
+
+	ToControlPlaneOutput() ControlPlaneOutput
+	ToControlPlaneOutputWithContext(context.Context) ControlPlaneOutput
+}
+
+type ControlPlaneArgs struct {
+	NodeCount pulumi.IntPtrInput `pulumi:"nodeCount"`
+}
+
+func (ControlPlaneArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*ControlPlane)(nil)).Elem()
+}
+
+func (i ControlPlaneArgs) ToControlPlaneOutput() ControlPlaneOutput {
+	return i.ToControlPlaneOutputWithContext(context.Background())
+}
+
+func (i ControlPlaneArgs) ToControlPlaneOutputWithContext(ctx context.Context) ControlPlaneOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ControlPlaneOutput)
+}
+
+func (i ControlPlaneArgs) ToControlPlanePtrOutput() ControlPlanePtrOutput {
+	return i.ToControlPlanePtrOutputWithContext(context.Background())
+}
+
+func (i ControlPlaneArgs) ToControlPlanePtrOutputWithContext(ctx context.Context) ControlPlanePtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ControlPlaneOutput).ToControlPlanePtrOutputWithContext(ctx)
+}
+
+// ControlPlanePtrInput is an input type that accepts ControlPlaneArgs, ControlPlanePtr and ControlPlanePtrOutput values.
+// You can construct a concrete instance of `ControlPlanePtrInput` via:
+//
+//	        ControlPlaneArgs{...}
+//
+//	or:
+//
+//	        nil
+type ControlPlanePtrInput interface {
+	pulumi.Input
+
+	ToControlPlanePtrOutput() ControlPlanePtrOutput
+	ToControlPlanePtrOutputWithContext(context.Context) ControlPlanePtrOutput
+}
+
+type controlPlanePtrType ControlPlaneArgs
+
+func ControlPlanePtr(v *ControlPlaneArgs) ControlPlanePtrInput {
+	return (*controlPlanePtrType)(v)
+}
+
+func (*controlPlanePtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**ControlPlane)(nil)).Elem()
+}
+
+func (i *controlPlanePtrType) ToControlPlanePtrOutput() ControlPlanePtrOutput {
+	return i.ToControlPlanePtrOutputWithContext(context.Background())
+}
+
+func (i *controlPlanePtrType) ToControlPlanePtrOutputWithContext(ctx context.Context) ControlPlanePtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ControlPlanePtrOutput)
+}
+
+type ControlPlaneOutput struct{ *pulumi.OutputState }
+
+func (ControlPlaneOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*ControlPlane)(nil)).Elem()
+}
+
+func (o ControlPlaneOutput) ToControlPlaneOutput() ControlPlaneOutput {
+	return o
+}
+
+func (o ControlPlaneOutput) ToControlPlaneOutputWithContext(ctx context.Context) ControlPlaneOutput {
+	return o
+}
+
+func (o ControlPlaneOutput) ToControlPlanePtrOutput() ControlPlanePtrOutput {
+	return o.ToControlPlanePtrOutputWithContext(context.Background())
+}
+
+func (o ControlPlaneOutput) ToControlPlanePtrOutputWithContext(ctx context.Context) ControlPlanePtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v ControlPlane) *ControlPlane {
+		return &v
+	}).(ControlPlanePtrOutput)
+}
+
+func (o ControlPlaneOutput) NodeCount() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v ControlPlane) *int { return v.NodeCount }).(pulumi.IntPtrOutput)
+}
+
+type ControlPlanePtrOutput struct{ *pulumi.OutputState }
+
+func (ControlPlanePtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**ControlPlane)(nil)).Elem()
+}
+
+func (o ControlPlanePtrOutput) ToControlPlanePtrOutput() ControlPlanePtrOutput {
+	return o
+}
+
+func (o ControlPlanePtrOutput) ToControlPlanePtrOutputWithContext(ctx context.Context) ControlPlanePtrOutput {
+	return o
+}
+
+func (o ControlPlanePtrOutput) Elem() ControlPlaneOutput {
+	return o.ApplyT(func(v *ControlPlane) ControlPlane {
+		if v != nil {
+			return *v
+		}
+		var ret ControlPlane
+		return ret
+	}).(ControlPlaneOutput)
+}
+
+func (o ControlPlanePtrOutput) NodeCount() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *ControlPlane) *int {
+		if v == nil {
+			return nil
+		}
+		return v.NodeCount
+	}).(pulumi.IntPtrOutput)
+}
+
+type Encryption struct {
+	Enabled   *bool    `pulumi:"enabled"`
+	Provider  *string  `pulumi:"provider"`
+	Resources []string `pulumi:"resources"`
+}
+
+// EncryptionInput is an input type that accepts EncryptionArgs and EncryptionOutput values.
+// You can construct a concrete instance of `EncryptionInput` via:
+//
+//	EncryptionArgs{...}
+type EncryptionInput interface {
+	pulumi.Input
+
+	ToEncryptionOutput() EncryptionOutput
+	ToEncryptionOutputWithContext(context.Context) EncryptionOutput
+}
+
+type EncryptionArgs struct {
+	Enabled   pulumi.BoolPtrInput     `pulumi:"enabled"`
+	Provider  pulumi.StringPtrInput   `pulumi:"provider"`
+	Resources pulumi.StringArrayInput `pulumi:"resources"`
+}
+
+func (EncryptionArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Encryption)(nil)).Elem()
+}
+
+func (i EncryptionArgs) ToEncryptionOutput() EncryptionOutput {
+	return i.ToEncryptionOutputWithContext(context.Background())
+}
+
+func (i EncryptionArgs) ToEncryptionOutputWithContext(ctx context.Context) EncryptionOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(EncryptionOutput)
+}
+
+func (i EncryptionArgs) ToEncryptionPtrOutput() EncryptionPtrOutput {
+	return i.ToEncryptionPtrOutputWithContext(context.Background())
+}
+
+func (i EncryptionArgs) ToEncryptionPtrOutputWithContext(ctx context.Context) EncryptionPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(EncryptionOutput).ToEncryptionPtrOutputWithContext(ctx)
+}
+
+// EncryptionPtrInput is an input type that accepts EncryptionArgs, EncryptionPtr and EncryptionPtrOutput values.
+// You can construct a concrete instance of `EncryptionPtrInput` via:
+//
+//	        EncryptionArgs{...}
+//
+//	or:
+//
+//	        nil
+type EncryptionPtrInput interface {
+	pulumi.Input
+
+	ToEncryptionPtrOutput() EncryptionPtrOutput
+	ToEncryptionPtrOutputWithContext(context.Context) EncryptionPtrOutput
+}
+
+type encryptionPtrType EncryptionArgs
+
+func EncryptionPtr(v *EncryptionArgs) EncryptionPtrInput {
+	return (*encryptionPtrType)(v)
+}
+
+func (*encryptionPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Encryption)(nil)).Elem()
+}
+
+func (i *encryptionPtrType) ToEncryptionPtrOutput() EncryptionPtrOutput {
+	return i.ToEncryptionPtrOutputWithContext(context.Background())
+}
+
+func (i *encryptionPtrType) ToEncryptionPtrOutputWithContext(ctx context.Context) EncryptionPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(EncryptionPtrOutput)
+}
+
+type EncryptionOutput struct{ *pulumi.OutputState }
+
+func (EncryptionOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Encryption)(nil)).Elem()
+}
+
+func (o EncryptionOutput) ToEncryptionOutput() EncryptionOutput {
+	return o
+}
+
+func (o EncryptionOutput) ToEncryptionOutputWithContext(ctx context.Context) EncryptionOutput {
+	return o
+}
+
+func (o EncryptionOutput) ToEncryptionPtrOutput() EncryptionPtrOutput {
+	return o.ToEncryptionPtrOutputWithContext(context.Background())
+}
+
+func (o EncryptionOutput) ToEncryptionPtrOutputWithContext(ctx context.Context) EncryptionPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Encryption) *Encryption {
+		return &v
+	}).(EncryptionPtrOutput)
+}
+
+func (o EncryptionOutput) Enabled() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v Encryption) *bool { return v.Enabled }).(pulumi.BoolPtrOutput)
+}
+
+func (o EncryptionOutput) Provider() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Encryption) *string { return v.Provider }).(pulumi.StringPtrOutput)
+}
+
+func (o EncryptionOutput) Resources() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Encryption) []string { return v.Resources }).(pulumi.StringArrayOutput)
+}
+
+type EncryptionPtrOutput struct{ *pulumi.OutputState }
+
+func (EncryptionPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Encryption)(nil)).Elem()
+}
+
+func (o EncryptionPtrOutput) ToEncryptionPtrOutput() EncryptionPtrOutput {
+	return o
+}
+
+func (o EncryptionPtrOutput) ToEncryptionPtrOutputWithContext(ctx context.Context) EncryptionPtrOutput {
+	return o
+}
+
+func (o EncryptionPtrOutput) Elem() EncryptionOutput {
+	return o.ApplyT(func(v *Encryption) Encryption {
+		if v != nil {
+			return *v
+		}
+		var ret Encryption
+		return ret
+	}).(EncryptionOutput)
+}
+
+func (o EncryptionPtrOutput) Enabled() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v *Encryption) *bool {
+		if v == nil {
+			return nil
+		}
+		return v.Enabled
+	}).(pulumi.BoolPtrOutput)
+}
+
+func (o EncryptionPtrOutput) Provider() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Encryption) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Provider
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o EncryptionPtrOutput) Resources() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v *Encryption) []string {
+		if v == nil {
+			return nil
+		}
+		return v.Resources
+	}).(pulumi.StringArrayOutput)
+}
+
+type GitOps struct {
+	Branch  *string `pulumi:"branch"`
+	Engine  *string `pulumi:"engine"`
+	Path    *string `pulumi:"path"`
+	Repo    *string `pulumi:"repo"`
+	Version *string `pulumi:"version"`
+}
+
+// GitOpsInput is an input type that accepts GitOpsArgs and GitOpsOutput values.
+// You can construct a concrete instance of `GitOpsInput` via:
+//
+//	GitOpsArgs{...}
+type GitOpsInput interface {
+	pulumi.Input
+
+	ToGitOpsOutput() GitOpsOutput
+	ToGitOpsOutputWithContext(context.Context) GitOpsOutput
+}
+
+type GitOpsArgs struct {
+	Branch  pulumi.StringPtrInput `pulumi:"branch"`
+	Engine  pulumi.StringPtrInput `pulumi:"engine"`
+	Path    pulumi.StringPtrInput `pulumi:"path"`
+	Repo    pulumi.StringPtrInput `pulumi:"repo"`
+	Version pulumi.StringPtrInput `pulumi:"version"`
+}
+
+func (GitOpsArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*GitOps)(nil)).Elem()
+}
+
+func (i GitOpsArgs) ToGitOpsOutput() GitOpsOutput {
+	return i.ToGitOpsOutputWithContext(context.Background())
+}
+
+func (i GitOpsArgs) ToGitOpsOutputWithContext(ctx context.Context) GitOpsOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(GitOpsOutput)
+}
+
+func (i GitOpsArgs) ToGitOpsPtrOutput() GitOpsPtrOutput {
+	return i.ToGitOpsPtrOutputWithContext(context.Background())
+}
+
+func (i GitOpsArgs) ToGitOpsPtrOutputWithContext(ctx context.Context) GitOpsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(GitOpsOutput).ToGitOpsPtrOutputWithContext(ctx)
+}
+
+// GitOpsPtrInput is an input type that accepts GitOpsArgs, GitOpsPtr and GitOpsPtrOutput values.
+// You can construct a concrete instance of `GitOpsPtrInput` via:
+//
+//	        GitOpsArgs{...}
+//
+//	or:
+//
+//	        nil
+type GitOpsPtrInput interface {
+	pulumi.Input
+
+	ToGitOpsPtrOutput() GitOpsPtrOutput
+	ToGitOpsPtrOutputWithContext(context.Context) GitOpsPtrOutput
+}
+
+type gitOpsPtrType GitOpsArgs
+
+func GitOpsPtr(v *GitOpsArgs) GitOpsPtrInput {
+	return (*gitOpsPtrType)(v)
+}
+
+func (*gitOpsPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**GitOps)(nil)).Elem()
+}
+
+func (i *gitOpsPtrType) ToGitOpsPtrOutput() GitOpsPtrOutput {
+	return i.ToGitOpsPtrOutputWithContext(context.Background())
+}
+
+func (i *gitOpsPtrType) ToGitOpsPtrOutputWithContext(ctx context.Context) GitOpsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(GitOpsPtrOutput)
+}
+
+type GitOpsOutput struct{ *pulumi.OutputState }
+
+func (GitOpsOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*GitOps)(nil)).Elem()
+}
+
+func (o GitOpsOutput) ToGitOpsOutput() GitOpsOutput {
+	return o
+}
+
+func (o GitOpsOutput) ToGitOpsOutputWithContext(ctx context.Context) GitOpsOutput {
+	return o
+}
+
+func (o GitOpsOutput) ToGitOpsPtrOutput() GitOpsPtrOutput {
+	return o.ToGitOpsPtrOutputWithContext(context.Background())
+}
+
+func (o GitOpsOutput) ToGitOpsPtrOutputWithContext(ctx context.Context) GitOpsPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v GitOps) *GitOps {
+		return &v
+	}).(GitOpsPtrOutput)
+}
+
+func (o GitOpsOutput) Branch() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v GitOps) *string { return v.Branch }).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsOutput) Engine() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v GitOps) *string { return v.Engine }).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsOutput) Path() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v GitOps) *string { return v.Path }).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsOutput) Repo() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v GitOps) *string { return v.Repo }).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsOutput) Version() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v GitOps) *string { return v.Version }).(pulumi.StringPtrOutput)
+}
+
+type GitOpsPtrOutput struct{ *pulumi.OutputState }
+
+func (GitOpsPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**GitOps)(nil)).Elem()
+}
+
+func (o GitOpsPtrOutput) ToGitOpsPtrOutput() GitOpsPtrOutput {
+	return o
+}
+
+func (o GitOpsPtrOutput) ToGitOpsPtrOutputWithContext(ctx context.Context) GitOpsPtrOutput {
+	return o
+}
+
+func (o GitOpsPtrOutput) Elem() GitOpsOutput {
+	return o.ApplyT(func(v *GitOps) GitOps {
+		if v != nil {
+			return *v
+		}
+		var ret GitOps
+		return ret
+	}).(GitOpsOutput)
+}
+
+func (o GitOpsPtrOutput) Branch() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *GitOps) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Branch
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsPtrOutput) Engine() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *GitOps) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Engine
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsPtrOutput) Path() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *GitOps) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Path
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsPtrOutput) Repo() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *GitOps) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Repo
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsPtrOutput) Version() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *GitOps) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Version
+	}).(pulumi.StringPtrOutput)
+}
+
+type GitOpsCredentials struct {
+	DeployKey  *string `pulumi:"deployKey"`
+	KnownHosts *string `pulumi:"knownHosts"`
+}
+
+// GitOpsCredentialsInput is an input type that accepts GitOpsCredentialsArgs and GitOpsCredentialsOutput values.
+// You can construct a concrete instance of `GitOpsCredentialsInput` via:
+//
+//	GitOpsCredentialsArgs{...}
+type GitOpsCredentialsInput interface {
+	pulumi.Input
+
+	ToGitOpsCredentialsOutput() GitOpsCredentialsOutput
+	ToGitOpsCredentialsOutputWithContext(context.Context) GitOpsCredentialsOutput
+}
+
+type GitOpsCredentialsArgs struct {
+	DeployKey  pulumi.StringPtrInput `pulumi:"deployKey"`
+	KnownHosts pulumi.StringPtrInput `pulumi:"knownHosts"`
+}
+
+func (GitOpsCredentialsArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*GitOpsCredentials)(nil)).Elem()
+}
+
+func (i GitOpsCredentialsArgs) ToGitOpsCredentialsOutput() GitOpsCredentialsOutput {
+	return i.ToGitOpsCredentialsOutputWithContext(context.Background())
+}
+
+func (i GitOpsCredentialsArgs) ToGitOpsCredentialsOutputWithContext(ctx context.Context) GitOpsCredentialsOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(GitOpsCredentialsOutput)
+}
+
+func (i GitOpsCredentialsArgs) ToGitOpsCredentialsPtrOutput() GitOpsCredentialsPtrOutput {
+	return i.ToGitOpsCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (i GitOpsCredentialsArgs) ToGitOpsCredentialsPtrOutputWithContext(ctx context.Context) GitOpsCredentialsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(GitOpsCredentialsOutput).ToGitOpsCredentialsPtrOutputWithContext(ctx)
+}
+
+// GitOpsCredentialsPtrInput is an input type that accepts GitOpsCredentialsArgs, GitOpsCredentialsPtr and GitOpsCredentialsPtrOutput values.
+// You can construct a concrete instance of `GitOpsCredentialsPtrInput` via:
+//
+//	        GitOpsCredentialsArgs{...}
+//
+//	or:
+//
+//	        nil
+type GitOpsCredentialsPtrInput interface {
+	pulumi.Input
+
+	ToGitOpsCredentialsPtrOutput() GitOpsCredentialsPtrOutput
+	ToGitOpsCredentialsPtrOutputWithContext(context.Context) GitOpsCredentialsPtrOutput
+}
+
+type gitOpsCredentialsPtrType GitOpsCredentialsArgs
+
+func GitOpsCredentialsPtr(v *GitOpsCredentialsArgs) GitOpsCredentialsPtrInput {
+	return (*gitOpsCredentialsPtrType)(v)
+}
+
+func (*gitOpsCredentialsPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**GitOpsCredentials)(nil)).Elem()
+}
+
+func (i *gitOpsCredentialsPtrType) ToGitOpsCredentialsPtrOutput() GitOpsCredentialsPtrOutput {
+	return i.ToGitOpsCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (i *gitOpsCredentialsPtrType) ToGitOpsCredentialsPtrOutputWithContext(ctx context.Context) GitOpsCredentialsPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(GitOpsCredentialsPtrOutput)
+}
+
+type GitOpsCredentialsOutput struct{ *pulumi.OutputState }
+
+func (GitOpsCredentialsOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*GitOpsCredentials)(nil)).Elem()
+}
+
+func (o GitOpsCredentialsOutput) ToGitOpsCredentialsOutput() GitOpsCredentialsOutput {
+	return o
+}
+
+func (o GitOpsCredentialsOutput) ToGitOpsCredentialsOutputWithContext(ctx context.Context) GitOpsCredentialsOutput {
+	return o
+}
+
+func (o GitOpsCredentialsOutput) ToGitOpsCredentialsPtrOutput() GitOpsCredentialsPtrOutput {
+	return o.ToGitOpsCredentialsPtrOutputWithContext(context.Background())
+}
+
+func (o GitOpsCredentialsOutput) ToGitOpsCredentialsPtrOutputWithContext(ctx context.Context) GitOpsCredentialsPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v GitOpsCredentials) *GitOpsCredentials {
+		return &v
+	}).(GitOpsCredentialsPtrOutput)
+}
+
+func (o GitOpsCredentialsOutput) DeployKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v GitOpsCredentials) *string { return v.DeployKey }).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsCredentialsOutput) KnownHosts() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v GitOpsCredentials) *string { return v.KnownHosts }).(pulumi.StringPtrOutput)
+}
+
+type GitOpsCredentialsPtrOutput struct{ *pulumi.OutputState }
+
+func (GitOpsCredentialsPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**GitOpsCredentials)(nil)).Elem()
+}
+
+func (o GitOpsCredentialsPtrOutput) ToGitOpsCredentialsPtrOutput() GitOpsCredentialsPtrOutput {
+	return o
+}
+
+func (o GitOpsCredentialsPtrOutput) ToGitOpsCredentialsPtrOutputWithContext(ctx context.Context) GitOpsCredentialsPtrOutput {
+	return o
+}
+
+func (o GitOpsCredentialsPtrOutput) Elem() GitOpsCredentialsOutput {
+	return o.ApplyT(func(v *GitOpsCredentials) GitOpsCredentials {
+		if v != nil {
+			return *v
+		}
+		var ret GitOpsCredentials
+		return ret
+	}).(GitOpsCredentialsOutput)
+}
+
+func (o GitOpsCredentialsPtrOutput) DeployKey() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *GitOpsCredentials) *string {
+		if v == nil {
+			return nil
+		}
+		return v.DeployKey
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o GitOpsCredentialsPtrOutput) KnownHosts() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *GitOpsCredentials) *string {
+		if v == nil {
+			return nil
+		}
+		return v.KnownHosts
+	}).(pulumi.StringPtrOutput)
+}
+
+type Hubble struct {
+	Relay *bool `pulumi:"relay"`
+	Ui    *bool `pulumi:"ui"`
+}
+
+// HubbleInput is an input type that accepts HubbleArgs and HubbleOutput values.
+// You can construct a concrete instance of `HubbleInput` via:
+//
+//	HubbleArgs{...}
+type HubbleInput interface {
+	pulumi.Input
+
+	ToHubbleOutput() HubbleOutput
+	ToHubbleOutputWithContext(context.Context) HubbleOutput
+}
+
+type HubbleArgs struct {
+	Relay pulumi.BoolPtrInput `pulumi:"relay"`
+	Ui    pulumi.BoolPtrInput `pulumi:"ui"`
+}
+
+func (HubbleArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Hubble)(nil)).Elem()
+}
+
+func (i HubbleArgs) ToHubbleOutput() HubbleOutput {
+	return i.ToHubbleOutputWithContext(context.Background())
+}
+
+func (i HubbleArgs) ToHubbleOutputWithContext(ctx context.Context) HubbleOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(HubbleOutput)
+}
+
+func (i HubbleArgs) ToHubblePtrOutput() HubblePtrOutput {
+	return i.ToHubblePtrOutputWithContext(context.Background())
+}
+
+func (i HubbleArgs) ToHubblePtrOutputWithContext(ctx context.Context) HubblePtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(HubbleOutput).ToHubblePtrOutputWithContext(ctx)
+}
+
+// HubblePtrInput is an input type that accepts HubbleArgs, HubblePtr and HubblePtrOutput values.
+// You can construct a concrete instance of `HubblePtrInput` via:
+//
+//	        HubbleArgs{...}
+//
+//	or:
+//
+//	        nil
+type HubblePtrInput interface {
+	pulumi.Input
+
+	ToHubblePtrOutput() HubblePtrOutput
+	ToHubblePtrOutputWithContext(context.Context) HubblePtrOutput
+}
+
+type hubblePtrType HubbleArgs
+
+func HubblePtr(v *HubbleArgs) HubblePtrInput {
+	return (*hubblePtrType)(v)
+}
+
+func (*hubblePtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Hubble)(nil)).Elem()
+}
+
+func (i *hubblePtrType) ToHubblePtrOutput() HubblePtrOutput {
+	return i.ToHubblePtrOutputWithContext(context.Background())
+}
+
+func (i *hubblePtrType) ToHubblePtrOutputWithContext(ctx context.Context) HubblePtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(HubblePtrOutput)
+}
+
+type HubbleOutput struct{ *pulumi.OutputState }
+
+func (HubbleOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Hubble)(nil)).Elem()
+}
+
+func (o HubbleOutput) ToHubbleOutput() HubbleOutput {
+	return o
+}
+
+func (o HubbleOutput) ToHubbleOutputWithContext(ctx context.Context) HubbleOutput {
+	return o
+}
+
+func (o HubbleOutput) ToHubblePtrOutput() HubblePtrOutput {
+	return o.ToHubblePtrOutputWithContext(context.Background())
+}
+
+func (o HubbleOutput) ToHubblePtrOutputWithContext(ctx context.Context) HubblePtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Hubble) *Hubble {
+		return &v
+	}).(HubblePtrOutput)
+}
+
+func (o HubbleOutput) Relay() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v Hubble) *bool { return v.Relay }).(pulumi.BoolPtrOutput)
+}
+
+func (o HubbleOutput) Ui() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v Hubble) *bool { return v.Ui }).(pulumi.BoolPtrOutput)
+}
+
+type HubblePtrOutput struct{ *pulumi.OutputState }
+
+func (HubblePtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Hubble)(nil)).Elem()
+}
+
+func (o HubblePtrOutput) ToHubblePtrOutput() HubblePtrOutput {
+	return o
+}
+
+func (o HubblePtrOutput) ToHubblePtrOutputWithContext(ctx context.Context) HubblePtrOutput {
+	return o
+}
+
+func (o HubblePtrOutput) Elem() HubbleOutput {
+	return o.ApplyT(func(v *Hubble) Hubble {
+		if v != nil {
+			return *v
+		}
+		var ret Hubble
+		return ret
+	}).(HubbleOutput)
+}
+
+func (o HubblePtrOutput) Relay() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v *Hubble) *bool {
+		if v == nil {
+			return nil
+		}
+		return v.Relay
+	}).(pulumi.BoolPtrOutput)
+}
+
+func (o HubblePtrOutput) Ui() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v *Hubble) *bool {
+		if v == nil {
+			return nil
+		}
+		return v.Ui
+	}).(pulumi.BoolPtrOutput)
+}
+
+type KubeProxy struct {
+	Config map[string]interface{} `pulumi:"config"`
+}
+
+// KubeProxyInput is an input type that accepts KubeProxyArgs and KubeProxyOutput values.
+// You can construct a concrete instance of `KubeProxyInput` via:
+//
+//	KubeProxyArgs{...}
+type KubeProxyInput interface {
+	pulumi.Input
+
+	ToKubeProxyOutput() KubeProxyOutput
+	ToKubeProxyOutputWithContext(context.Context) KubeProxyOutput
+}
+
+type KubeProxyArgs struct {
+	Config pulumi.MapInput `pulumi:"config"`
+}
+
+func (KubeProxyArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*KubeProxy)(nil)).Elem()
+}
+
+func (i KubeProxyArgs) ToKubeProxyOutput() KubeProxyOutput {
+	return i.ToKubeProxyOutputWithContext(context.Background())
+}
+
+func (i KubeProxyArgs) ToKubeProxyOutputWithContext(ctx context.Context) KubeProxyOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeProxyOutput)
+}
+
+func (i KubeProxyArgs) ToKubeProxyPtrOutput() KubeProxyPtrOutput {
+	return i.ToKubeProxyPtrOutputWithContext(context.Background())
+}
+
+func (i KubeProxyArgs) ToKubeProxyPtrOutputWithContext(ctx context.Context) KubeProxyPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeProxyOutput).ToKubeProxyPtrOutputWithContext(ctx)
+}
+
+// KubeProxyPtrInput is an input type that accepts KubeProxyArgs, KubeProxyPtr and KubeProxyPtrOutput values.
+// You can construct a concrete instance of `KubeProxyPtrInput` via:
+//
+//	        KubeProxyArgs{...}
+//
+//	or:
+//
+//	        nil
+type KubeProxyPtrInput interface {
+	pulumi.Input
+
+	ToKubeProxyPtrOutput() KubeProxyPtrOutput
+	ToKubeProxyPtrOutputWithContext(context.Context) KubeProxyPtrOutput
+}
+
+type kubeProxyPtrType KubeProxyArgs
+
+func KubeProxyPtr(v *KubeProxyArgs) KubeProxyPtrInput {
+	return (*kubeProxyPtrType)(v)
+}
+
+func (*kubeProxyPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**KubeProxy)(nil)).Elem()
+}
+
+func (i *kubeProxyPtrType) ToKubeProxyPtrOutput() KubeProxyPtrOutput {
+	return i.ToKubeProxyPtrOutputWithContext(context.Background())
+}
+
+func (i *kubeProxyPtrType) ToKubeProxyPtrOutputWithContext(ctx context.Context) KubeProxyPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeProxyPtrOutput)
+}
+
+type KubeProxyOutput struct{ *pulumi.OutputState }
+
+func (KubeProxyOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*KubeProxy)(nil)).Elem()
+}
+
+func (o KubeProxyOutput) ToKubeProxyOutput() KubeProxyOutput {
+	return o
+}
+
+func (o KubeProxyOutput) ToKubeProxyOutputWithContext(ctx context.Context) KubeProxyOutput {
+	return o
+}
+
+func (o KubeProxyOutput) ToKubeProxyPtrOutput() KubeProxyPtrOutput {
+	return o.ToKubeProxyPtrOutputWithContext(context.Background())
+}
+
+func (o KubeProxyOutput) ToKubeProxyPtrOutputWithContext(ctx context.Context) KubeProxyPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v KubeProxy) *KubeProxy {
+		return &v
+	}).(KubeProxyPtrOutput)
+}
+
+func (o KubeProxyOutput) Config() pulumi.MapOutput {
+	return o.ApplyT(func(v KubeProxy) map[string]interface{} { return v.Config }).(pulumi.MapOutput)
+}
+
+type KubeProxyPtrOutput struct{ *pulumi.OutputState }
+
+func (KubeProxyPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**KubeProxy)(nil)).Elem()
+}
+
+func (o KubeProxyPtrOutput) ToKubeProxyPtrOutput() KubeProxyPtrOutput {
+	return o
+}
+
+func (o KubeProxyPtrOutput) ToKubeProxyPtrOutputWithContext(ctx context.Context) KubeProxyPtrOutput {
+	return o
+}
+
+func (o KubeProxyPtrOutput) Elem() KubeProxyOutput {
+	return o.ApplyT(func(v *KubeProxy) KubeProxy {
+		if v != nil {
+			return *v
+		}
+		var ret KubeProxy
+		return ret
+	}).(KubeProxyOutput)
+}
+
+func (o KubeProxyPtrOutput) Config() pulumi.MapOutput {
+	return o.ApplyT(func(v *KubeProxy) map[string]interface{} {
+		if v == nil {
+			return nil
+		}
+		return v.Config
+	}).(pulumi.MapOutput)
+}
+
+type Kubeadm struct {
+	ApiServer         *Component                        `pulumi:"apiServer"`
+	ControllerManager *Component                        `pulumi:"controllerManager"`
+	Etcd              *Component                        `pulumi:"etcd"`
+	FeatureGates      map[string]bool                   `pulumi:"featureGates"`
+	KubeProxy         *KubeProxy                        `pulumi:"kubeProxy"`
+	Kubelet           *Kubelet                          `pulumi:"kubelet"`
+	Patches           map[string]map[string]interface{} `pulumi:"patches"`
+	Scheduler         *Component                        `pulumi:"scheduler"`
+}
+
+// KubeadmInput is an input type that accepts KubeadmArgs and KubeadmOutput values.
+// You can construct a concrete instance of `KubeadmInput` via:
+//
+//	KubeadmArgs{...}
+type KubeadmInput interface {
+	pulumi.Input
+
+	ToKubeadmOutput() KubeadmOutput
+	ToKubeadmOutputWithContext(context.Context) KubeadmOutput
+}
+
+type KubeadmArgs struct {
+	ApiServer         ComponentPtrInput   `pulumi:"apiServer"`
+	ControllerManager ComponentPtrInput   `pulumi:"controllerManager"`
+	Etcd              ComponentPtrInput   `pulumi:"etcd"`
+	FeatureGates      pulumi.BoolMapInput `pulumi:"featureGates"`
+	KubeProxy         KubeProxyPtrInput   `pulumi:"kubeProxy"`
+	Kubelet           KubeletPtrInput     `pulumi:"kubelet"`
+	Patches           pulumi.MapMapInput  `pulumi:"patches"`
+	Scheduler         ComponentPtrInput   `pulumi:"scheduler"`
+}
+
+func (KubeadmArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Kubeadm)(nil)).Elem()
+}
+
+func (i KubeadmArgs) ToKubeadmOutput() KubeadmOutput {
+	return i.ToKubeadmOutputWithContext(context.Background())
+}
+
+func (i KubeadmArgs) ToKubeadmOutputWithContext(ctx context.Context) KubeadmOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeadmOutput)
+}
+
+func (i KubeadmArgs) ToKubeadmPtrOutput() KubeadmPtrOutput {
+	return i.ToKubeadmPtrOutputWithContext(context.Background())
+}
+
+func (i KubeadmArgs) ToKubeadmPtrOutputWithContext(ctx context.Context) KubeadmPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeadmOutput).ToKubeadmPtrOutputWithContext(ctx)
+}
+
+// KubeadmPtrInput is an input type that accepts KubeadmArgs, KubeadmPtr and KubeadmPtrOutput values.
+// You can construct a concrete instance of `KubeadmPtrInput` via:
+//
+//	        KubeadmArgs{...}
+//
+//	or:
+//
+//	        nil
+type KubeadmPtrInput interface {
+	pulumi.Input
+
+	ToKubeadmPtrOutput() KubeadmPtrOutput
+	ToKubeadmPtrOutputWithContext(context.Context) KubeadmPtrOutput
+}
+
+type kubeadmPtrType KubeadmArgs
+
+func KubeadmPtr(v *KubeadmArgs) KubeadmPtrInput {
+	return (*kubeadmPtrType)(v)
+}
+
+func (*kubeadmPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Kubeadm)(nil)).Elem()
+}
+
+func (i *kubeadmPtrType) ToKubeadmPtrOutput() KubeadmPtrOutput {
+	return i.ToKubeadmPtrOutputWithContext(context.Background())
+}
+
+func (i *kubeadmPtrType) ToKubeadmPtrOutputWithContext(ctx context.Context) KubeadmPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeadmPtrOutput)
+}
+
+type KubeadmOutput struct{ *pulumi.OutputState }
+
+func (KubeadmOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Kubeadm)(nil)).Elem()
+}
+
+func (o KubeadmOutput) ToKubeadmOutput() KubeadmOutput {
+	return o
+}
+
+func (o KubeadmOutput) ToKubeadmOutputWithContext(ctx context.Context) KubeadmOutput {
+	return o
+}
+
+func (o KubeadmOutput) ToKubeadmPtrOutput() KubeadmPtrOutput {
+	return o.ToKubeadmPtrOutputWithContext(context.Background())
+}
+
+func (o KubeadmOutput) ToKubeadmPtrOutputWithContext(ctx context.Context) KubeadmPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Kubeadm) *Kubeadm {
+		return &v
+	}).(KubeadmPtrOutput)
+}
+
+func (o KubeadmOutput) ApiServer() ComponentPtrOutput {
+	return o.ApplyT(func(v Kubeadm) *Component { return v.ApiServer }).(ComponentPtrOutput)
+}
+
+func (o KubeadmOutput) ControllerManager() ComponentPtrOutput {
+	return o.ApplyT(func(v Kubeadm) *Component { return v.ControllerManager }).(ComponentPtrOutput)
+}
+
+func (o KubeadmOutput) Etcd() ComponentPtrOutput {
+	return o.ApplyT(func(v Kubeadm) *Component { return v.Etcd }).(ComponentPtrOutput)
+}
+
+func (o KubeadmOutput) FeatureGates() pulumi.BoolMapOutput {
+	return o.ApplyT(func(v Kubeadm) map[string]bool { return v.FeatureGates }).(pulumi.BoolMapOutput)
+}
+
+func (o KubeadmOutput) KubeProxy() KubeProxyPtrOutput {
+	return o.ApplyT(func(v Kubeadm) *KubeProxy { return v.KubeProxy }).(KubeProxyPtrOutput)
+}
+
+func (o KubeadmOutput) Kubelet() KubeletPtrOutput {
+	return o.ApplyT(func(v Kubeadm) *Kubelet { return v.Kubelet }).(KubeletPtrOutput)
+}
+
+func (o KubeadmOutput) Patches() pulumi.MapMapOutput {
+	return o.ApplyT(func(v Kubeadm) map[string]map[string]interface{} { return v.Patches }).(pulumi.MapMapOutput)
+}
+
+func (o KubeadmOutput) Scheduler() ComponentPtrOutput {
+	return o.ApplyT(func(v Kubeadm) *Component { return v.Scheduler }).(ComponentPtrOutput)
+}
+
+type KubeadmPtrOutput struct{ *pulumi.OutputState }
+
+func (KubeadmPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Kubeadm)(nil)).Elem()
+}
+
+func (o KubeadmPtrOutput) ToKubeadmPtrOutput() KubeadmPtrOutput {
+	return o
+}
+
+func (o KubeadmPtrOutput) ToKubeadmPtrOutputWithContext(ctx context.Context) KubeadmPtrOutput {
+	return o
+}
+
+func (o KubeadmPtrOutput) Elem() KubeadmOutput {
+	return o.ApplyT(func(v *Kubeadm) Kubeadm {
+		if v != nil {
+			return *v
+		}
+		var ret Kubeadm
+		return ret
+	}).(KubeadmOutput)
+}
+
+func (o KubeadmPtrOutput) ApiServer() ComponentPtrOutput {
+	return o.ApplyT(func(v *Kubeadm) *Component {
+		if v == nil {
+			return nil
+		}
+		return v.ApiServer
+	}).(ComponentPtrOutput)
+}
+
+func (o KubeadmPtrOutput) ControllerManager() ComponentPtrOutput {
+	return o.ApplyT(func(v *Kubeadm) *Component {
+		if v == nil {
+			return nil
+		}
+		return v.ControllerManager
+	}).(ComponentPtrOutput)
+}
+
+func (o KubeadmPtrOutput) Etcd() ComponentPtrOutput {
+	return o.ApplyT(func(v *Kubeadm) *Component {
+		if v == nil {
+			return nil
+		}
+		return v.Etcd
+	}).(ComponentPtrOutput)
+}
+
+func (o KubeadmPtrOutput) FeatureGates() pulumi.BoolMapOutput {
+	return o.ApplyT(func(v *Kubeadm) map[string]bool {
+		if v == nil {
+			return nil
+		}
+		return v.FeatureGates
+	}).(pulumi.BoolMapOutput)
+}
+
+func (o KubeadmPtrOutput) KubeProxy() KubeProxyPtrOutput {
+	return o.ApplyT(func(v *Kubeadm) *KubeProxy {
+		if v == nil {
+			return nil
+		}
+		return v.KubeProxy
+	}).(KubeProxyPtrOutput)
+}
+
+func (o KubeadmPtrOutput) Kubelet() KubeletPtrOutput {
+	return o.ApplyT(func(v *Kubeadm) *Kubelet {
+		if v == nil {
+			return nil
+		}
+		return v.Kubelet
+	}).(KubeletPtrOutput)
+}
+
+func (o KubeadmPtrOutput) Patches() pulumi.MapMapOutput {
+	return o.ApplyT(func(v *Kubeadm) map[string]map[string]interface{} {
+		if v == nil {
+			return nil
+		}
+		return v.Patches
+	}).(pulumi.MapMapOutput)
+}
+
+func (o KubeadmPtrOutput) Scheduler() ComponentPtrOutput {
+	return o.ApplyT(func(v *Kubeadm) *Component {
+		if v == nil {
+			return nil
+		}
+		return v.Scheduler
+	}).(ComponentPtrOutput)
+}
+
+type Kubelet struct {
+	Config    map[string]interface{} `pulumi:"config"`
+	ExtraArgs map[string]string      `pulumi:"extraArgs"`
+}
+
+// KubeletInput is an input type that accepts KubeletArgs and KubeletOutput values.
+// You can construct a concrete instance of `KubeletInput` via:
+//
+//	KubeletArgs{...}
+type KubeletInput interface {
+	pulumi.Input
+
+	ToKubeletOutput() KubeletOutput
+	ToKubeletOutputWithContext(context.Context) KubeletOutput
+}
+
+type KubeletArgs struct {
+	Config    pulumi.MapInput       `pulumi:"config"`
+	ExtraArgs pulumi.StringMapInput `pulumi:"extraArgs"`
+}
+
+func (KubeletArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Kubelet)(nil)).Elem()
+}
+
+func (i KubeletArgs) ToKubeletOutput() KubeletOutput {
+	return i.ToKubeletOutputWithContext(context.Background())
+}
+
+func (i KubeletArgs) ToKubeletOutputWithContext(ctx context.Context) KubeletOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeletOutput)
+}
+
+func (i KubeletArgs) ToKubeletPtrOutput() KubeletPtrOutput {
+	return i.ToKubeletPtrOutputWithContext(context.Background())
+}
+
+func (i KubeletArgs) ToKubeletPtrOutputWithContext(ctx context.Context) KubeletPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeletOutput).ToKubeletPtrOutputWithContext(ctx)
+}
+
+// KubeletPtrInput is an input type that accepts KubeletArgs, KubeletPtr and KubeletPtrOutput values.
+// You can construct a concrete instance of `KubeletPtrInput` via:
+//
+//	        KubeletArgs{...}
+//
+//	or:
+//
+//	        nil
+type KubeletPtrInput interface {
+	pulumi.Input
+
+	ToKubeletPtrOutput() KubeletPtrOutput
+	ToKubeletPtrOutputWithContext(context.Context) KubeletPtrOutput
+}
+
+type kubeletPtrType KubeletArgs
+
+func KubeletPtr(v *KubeletArgs) KubeletPtrInput {
+	return (*kubeletPtrType)(v)
+}
+
+func (*kubeletPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Kubelet)(nil)).Elem()
+}
+
+func (i *kubeletPtrType) ToKubeletPtrOutput() KubeletPtrOutput {
+	return i.ToKubeletPtrOutputWithContext(context.Background())
+}
+
+func (i *kubeletPtrType) ToKubeletPtrOutputWithContext(ctx context.Context) KubeletPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(KubeletPtrOutput)
+}
+
+type KubeletOutput struct{ *pulumi.OutputState }
+
+func (KubeletOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Kubelet)(nil)).Elem()
+}
+
+func (o KubeletOutput) ToKubeletOutput() KubeletOutput {
+	return o
+}
+
+func (o KubeletOutput) ToKubeletOutputWithContext(ctx context.Context) KubeletOutput {
+	return o
+}
+
+func (o KubeletOutput) ToKubeletPtrOutput() KubeletPtrOutput {
+	return o.ToKubeletPtrOutputWithContext(context.Background())
+}
+
+func (o KubeletOutput) ToKubeletPtrOutputWithContext(ctx context.Context) KubeletPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Kubelet) *Kubelet {
+		return &v
+	}).(KubeletPtrOutput)
+}
+
+func (o KubeletOutput) Config() pulumi.MapOutput {
+	return o.ApplyT(func(v Kubelet) map[string]interface{} { return v.Config }).(pulumi.MapOutput)
+}
+
+func (o KubeletOutput) ExtraArgs() pulumi.StringMapOutput {
+	return o.ApplyT(func(v Kubelet) map[string]string { return v.ExtraArgs }).(pulumi.StringMapOutput)
+}
+
+type KubeletPtrOutput struct{ *pulumi.OutputState }
+
+func (KubeletPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Kubelet)(nil)).Elem()
+}
+
+func (o KubeletPtrOutput) ToKubeletPtrOutput() KubeletPtrOutput {
+	return o
+}
+
+func (o KubeletPtrOutput) ToKubeletPtrOutputWithContext(ctx context.Context) KubeletPtrOutput {
+	return o
+}
+
+func (o KubeletPtrOutput) Elem() KubeletOutput {
+	return o.ApplyT(func(v *Kubelet) Kubelet {
+		if v != nil {
+			return *v
+		}
+		var ret Kubelet
+		return ret
+	}).(KubeletOutput)
+}
+
+func (o KubeletPtrOutput) Config() pulumi.MapOutput {
+	return o.ApplyT(func(v *Kubelet) map[string]interface{} {
+		if v == nil {
+			return nil
+		}
+		return v.Config
+	}).(pulumi.MapOutput)
+}
+
+func (o KubeletPtrOutput) ExtraArgs() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *Kubelet) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.ExtraArgs
+	}).(pulumi.StringMapOutput)
+}
+
+type LoadBalancer struct {
+	Create       *bool                  `pulumi:"create"`
+	PortMappings map[string]PortMapping `pulumi:"portMappings"`
+}
+
+// LoadBalancerInput is an input type that accepts LoadBalancerArgs and LoadBalancerOutput values.
+// You can construct a concrete instance of `LoadBalancerInput` via:
+//
+//	LoadBalancerArgs{...}
+type LoadBalancerInput interface {
+	pulumi.Input
+
+	ToLoadBalancerOutput() LoadBalancerOutput
+	ToLoadBalancerOutputWithContext(context.Context) LoadBalancerOutput
+}
+
+type LoadBalancerArgs struct {
+	Create       pulumi.BoolPtrInput `pulumi:"create"`
+	PortMappings PortMappingMapInput `pulumi:"portMappings"`
+}
+
+func (LoadBalancerArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*LoadBalancer)(nil)).Elem()
+}
+
+func (i LoadBalancerArgs) ToLoadBalancerOutput() LoadBalancerOutput {
+	return i.ToLoadBalancerOutputWithContext(context.Background())
+}
+
+func (i LoadBalancerArgs) ToLoadBalancerOutputWithContext(ctx context.Context) LoadBalancerOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LoadBalancerOutput)
+}
+
+func (i LoadBalancerArgs) ToLoadBalancerPtrOutput() LoadBalancerPtrOutput {
+	return i.ToLoadBalancerPtrOutputWithContext(context.Background())
+}
+
+func (i LoadBalancerArgs) ToLoadBalancerPtrOutputWithContext(ctx context.Context) LoadBalancerPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LoadBalancerOutput).ToLoadBalancerPtrOutputWithContext(ctx)
+}
+
+// LoadBalancerPtrInput is an input type that accepts LoadBalancerArgs, LoadBalancerPtr and LoadBalancerPtrOutput values.
+// You can construct a concrete instance of `LoadBalancerPtrInput` via:
+//
+//	        LoadBalancerArgs{...}
+//
+//	or:
+//
+//	        nil
+type LoadBalancerPtrInput interface {
+	pulumi.Input
+
+	ToLoadBalancerPtrOutput() LoadBalancerPtrOutput
+	ToLoadBalancerPtrOutputWithContext(context.Context) LoadBalancerPtrOutput
+}
+
+type loadBalancerPtrType LoadBalancerArgs
+
+func LoadBalancerPtr(v *LoadBalancerArgs) LoadBalancerPtrInput {
+	return (*loadBalancerPtrType)(v)
+}
+
+func (*loadBalancerPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**LoadBalancer)(nil)).Elem()
+}
+
+func (i *loadBalancerPtrType) ToLoadBalancerPtrOutput() LoadBalancerPtrOutput {
+	return i.ToLoadBalancerPtrOutputWithContext(context.Background())
+}
+
+func (i *loadBalancerPtrType) ToLoadBalancerPtrOutputWithContext(ctx context.Context) LoadBalancerPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(LoadBalancerPtrOutput)
+}
+
+type LoadBalancerOutput struct{ *pulumi.OutputState }
+
+func (LoadBalancerOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*LoadBalancer)(nil)).Elem()
+}
+
+func (o LoadBalancerOutput) ToLoadBalancerOutput() LoadBalancerOutput {
+	return o
+}
+
+func (o LoadBalancerOutput) ToLoadBalancerOutputWithContext(ctx context.Context) LoadBalancerOutput {
+	return o
+}
+
+func (o LoadBalancerOutput) ToLoadBalancerPtrOutput() LoadBalancerPtrOutput {
+	return o.ToLoadBalancerPtrOutputWithContext(context.Background())
+}
+
+func (o LoadBalancerOutput) ToLoadBalancerPtrOutputWithContext(ctx context.Context) LoadBalancerPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v LoadBalancer) *LoadBalancer {
+		return &v
+	}).(LoadBalancerPtrOutput)
+}
+
+func (o LoadBalancerOutput) Create() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v LoadBalancer) *bool { return v.Create }).(pulumi.BoolPtrOutput)
+}
+
+func (o LoadBalancerOutput) PortMappings() PortMappingMapOutput {
+	return o.ApplyT(func(v LoadBalancer) map[string]PortMapping { return v.PortMappings }).(PortMappingMapOutput)
+}
+
+type LoadBalancerPtrOutput struct{ *pulumi.OutputState }
+
+func (LoadBalancerPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**LoadBalancer)(nil)).Elem()
+}
+
+func (o LoadBalancerPtrOutput) ToLoadBalancerPtrOutput() LoadBalancerPtrOutput {
+	return o
+}
+
+func (o LoadBalancerPtrOutput) ToLoadBalancerPtrOutputWithContext(ctx context.Context) LoadBalancerPtrOutput {
+	return o
+}
+
+func (o LoadBalancerPtrOutput) Elem() LoadBalancerOutput {
+	return o.ApplyT(func(v *LoadBalancer) LoadBalancer {
+		if v != nil {
+			return *v
+		}
+		var ret LoadBalancer
+		return ret
+	}).(LoadBalancerOutput)
+}
+
+func (o LoadBalancerPtrOutput) Create() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v *LoadBalancer) *bool {
+		if v == nil {
+			return nil
+		}
+		return v.Create
+	}).(pulumi.BoolPtrOutput)
+}
+
+func (o LoadBalancerPtrOutput) PortMappings() PortMappingMapOutput {
+	return o.ApplyT(func(v *LoadBalancer) map[string]PortMapping {
+		if v == nil {
+			return nil
+		}
+		return v.PortMappings
+	}).(PortMappingMapOutput)
+}
+
+type Manifest struct {
+	Dir   *string  `pulumi:"dir"`
+	Name  *string  `pulumi:"name"`
+	Needs []string `pulumi:"needs"`
+}
+
+// ManifestInput is an input type that accepts ManifestArgs and ManifestOutput values.
+// You can construct a concrete instance of `ManifestInput` via:
+//
+//	ManifestArgs{...}
+type ManifestInput interface {
+	pulumi.Input
+
+	ToManifestOutput() ManifestOutput
+	ToManifestOutputWithContext(context.Context) ManifestOutput
+}
+
+type ManifestArgs struct {
+	Dir   pulumi.StringPtrInput   `pulumi:"dir"`
+	Name  pulumi.StringPtrInput   `pulumi:"name"`
+	Needs pulumi.StringArrayInput `pulumi:"needs"`
+}
+
+func (ManifestArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Manifest)(nil)).Elem()
+}
+
+func (i ManifestArgs) ToManifestOutput() ManifestOutput {
+	return i.ToManifestOutputWithContext(context.Background())
+}
+
+func (i ManifestArgs) ToManifestOutputWithContext(ctx context.Context) ManifestOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ManifestOutput)
+}
+
+// ManifestArrayInput is an input type that accepts ManifestArray and ManifestArrayOutput values.
+// You can construct a concrete instance of `ManifestArrayInput` via:
+//
+//	ManifestArray{ ManifestArgs{...} }
+type ManifestArrayInput interface {
+	pulumi.Input
+
+	ToManifestArrayOutput() ManifestArrayOutput
+	ToManifestArrayOutputWithContext(context.Context) ManifestArrayOutput
+}
+
+type ManifestArray []ManifestInput
+
+func (ManifestArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]Manifest)(nil)).Elem()
+}
+
+func (i ManifestArray) ToManifestArrayOutput() ManifestArrayOutput {
+	return i.ToManifestArrayOutputWithContext(context.Background())
+}
+
+func (i ManifestArray) ToManifestArrayOutputWithContext(ctx context.Context) ManifestArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ManifestArrayOutput)
+}
+
+type ManifestOutput struct{ *pulumi.OutputState }
+
+func (ManifestOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Manifest)(nil)).Elem()
+}
+
+func (o ManifestOutput) ToManifestOutput() ManifestOutput {
+	return o
+}
+
+func (o ManifestOutput) ToManifestOutputWithContext(ctx context.Context) ManifestOutput {
+	return o
+}
+
+func (o ManifestOutput) Dir() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Manifest) *string { return v.Dir }).(pulumi.StringPtrOutput)
+}
+
+func (o ManifestOutput) Name() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Manifest) *string { return v.Name }).(pulumi.StringPtrOutput)
+}
+
+func (o ManifestOutput) Needs() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Manifest) []string { return v.Needs }).(pulumi.StringArrayOutput)
+}
+
+type ManifestArrayOutput struct{ *pulumi.OutputState }
+
+func (ManifestArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]Manifest)(nil)).Elem()
+}
+
+func (o ManifestArrayOutput) ToManifestArrayOutput() ManifestArrayOutput {
+	return o
+}
+
+func (o ManifestArrayOutput) ToManifestArrayOutputWithContext(ctx context.Context) ManifestArrayOutput {
+	return o
+}
+
+func (o ManifestArrayOutput) Index(i pulumi.IntInput) ManifestOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) Manifest {
+		return vs[0].([]Manifest)[vs[1].(int)]
+	}).(ManifestOutput)
+}
+
+type Ntp struct {
+	MaxOffsetMs *int    `pulumi:"maxOffsetMs"`
+	Primary     *string `pulumi:"primary"`
+	Secondary   *string `pulumi:"secondary"`
+}
+
+// NtpInput is an input type that accepts NtpArgs and NtpOutput values.
+// You can construct a concrete instance of `NtpInput` via:
+//
+//	NtpArgs{...}
+type NtpInput interface {
+	pulumi.Input
+
+	ToNtpOutput() NtpOutput
+	ToNtpOutputWithContext(context.Context) NtpOutput
+}
+
+type NtpArgs struct {
+	MaxOffsetMs pulumi.IntPtrInput    `pulumi:"maxOffsetMs"`
+	Primary     pulumi.StringPtrInput `pulumi:"primary"`
+	Secondary   pulumi.StringPtrInput `pulumi:"secondary"`
+}
+
+func (NtpArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Ntp)(nil)).Elem()
+}
+
+func (i NtpArgs) ToNtpOutput() NtpOutput {
+	return i.ToNtpOutputWithContext(context.Background())
+}
+
+func (i NtpArgs) ToNtpOutputWithContext(ctx context.Context) NtpOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NtpOutput)
+}
+
+func (i NtpArgs) ToNtpPtrOutput() NtpPtrOutput {
+	return i.ToNtpPtrOutputWithContext(context.Background())
+}
+
+func (i NtpArgs) ToNtpPtrOutputWithContext(ctx context.Context) NtpPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NtpOutput).ToNtpPtrOutputWithContext(ctx)
+}
+
+// NtpPtrInput is an input type that accepts NtpArgs, NtpPtr and NtpPtrOutput values.
+// You can construct a concrete instance of `NtpPtrInput` via:
+//
+//	        NtpArgs{...}
+//
+//	or:
+//
+//	        nil
+type NtpPtrInput interface {
+	pulumi.Input
+
+	ToNtpPtrOutput() NtpPtrOutput
+	ToNtpPtrOutputWithContext(context.Context) NtpPtrOutput
+}
+
+type ntpPtrType NtpArgs
+
+func NtpPtr(v *NtpArgs) NtpPtrInput {
+	return (*ntpPtrType)(v)
+}
+
+func (*ntpPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Ntp)(nil)).Elem()
+}
+
+func (i *ntpPtrType) ToNtpPtrOutput() NtpPtrOutput {
+	return i.ToNtpPtrOutputWithContext(context.Background())
+}
+
+func (i *ntpPtrType) ToNtpPtrOutputWithContext(ctx context.Context) NtpPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(NtpPtrOutput)
+}
+
+type NtpOutput struct{ *pulumi.OutputState }
+
+func (NtpOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Ntp)(nil)).Elem()
+}
+
+func (o NtpOutput) ToNtpOutput() NtpOutput {
+	return o
+}
+
+func (o NtpOutput) ToNtpOutputWithContext(ctx context.Context) NtpOutput {
+	return o
+}
+
+func (o NtpOutput) ToNtpPtrOutput() NtpPtrOutput {
+	return o.ToNtpPtrOutputWithContext(context.Background())
+}
+
+func (o NtpOutput) ToNtpPtrOutputWithContext(ctx context.Context) NtpPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Ntp) *Ntp {
+		return &v
+	}).(NtpPtrOutput)
+}
+
+func (o NtpOutput) MaxOffsetMs() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Ntp) *int { return v.MaxOffsetMs }).(pulumi.IntPtrOutput)
+}
+
+func (o NtpOutput) Primary() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Ntp) *string { return v.Primary }).(pulumi.StringPtrOutput)
+}
+
+func (o NtpOutput) Secondary() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Ntp) *string { return v.Secondary }).(pulumi.StringPtrOutput)
+}
+
+type NtpPtrOutput struct{ *pulumi.OutputState }
+
+func (NtpPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Ntp)(nil)).Elem()
+}
+
+func (o NtpPtrOutput) ToNtpPtrOutput() NtpPtrOutput {
+	return o
+}
+
+func (o NtpPtrOutput) ToNtpPtrOutputWithContext(ctx context.Context) NtpPtrOutput {
+	return o
+}
+
+func (o NtpPtrOutput) Elem() NtpOutput {
+	return o.ApplyT(func(v *Ntp) Ntp {
+		if v != nil {
+			return *v
+		}
+		var ret Ntp
+		return ret
+	}).(NtpOutput)
+}
+
+func (o NtpPtrOutput) MaxOffsetMs() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Ntp) *int {
+		if v == nil {
+			return nil
+		}
+		return v.MaxOffsetMs
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o NtpPtrOutput) Primary() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Ntp) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Primary
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o NtpPtrOutput) Secondary() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Ntp) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Secondary
+	}).(pulumi.StringPtrOutput)
+}
+
+type OIDC struct {
+	CaFile         *string  `pulumi:"caFile"`
+	ClientId       *string  `pulumi:"clientId"`
+	ExtraScopes    []string `pulumi:"extraScopes"`
+	GroupsClaim    *string  `pulumi:"groupsClaim"`
+	GroupsPrefix   *string  `pulumi:"groupsPrefix"`
+	IssuerUrl      *string  `pulumi:"issuerUrl"`
+	UsernameClaim  *string  `pulumi:"usernameClaim"`
+	UsernamePrefix *string  `pulumi:"usernamePrefix"`
+}
+
+// OIDCInput is an input type that accepts OIDCArgs and OIDCOutput values.
+// You can construct a concrete instance of `OIDCInput` via:
+//
+//	OIDCArgs{...}
+type OIDCInput interface {
+	pulumi.Input
+
+	ToOIDCOutput() OIDCOutput
+	ToOIDCOutputWithContext(context.Context) OIDCOutput
+}
+
+type OIDCArgs struct {
+	CaFile         pulumi.StringPtrInput   `pulumi:"caFile"`
+	ClientId       pulumi.StringPtrInput   `pulumi:"clientId"`
+	ExtraScopes    pulumi.StringArrayInput `pulumi:"extraScopes"`
+	GroupsClaim    pulumi.StringPtrInput   `pulumi:"groupsClaim"`
+	GroupsPrefix   pulumi.StringPtrInput   `pulumi:"groupsPrefix"`
+	IssuerUrl      pulumi.StringPtrInput   `pulumi:"issuerUrl"`
+	UsernameClaim  pulumi.StringPtrInput   `pulumi:"usernameClaim"`
+	UsernamePrefix pulumi.StringPtrInput   `pulumi:"usernamePrefix"`
+}
+
+func (OIDCArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*OIDC)(nil)).Elem()
+}
+
+func (i OIDCArgs) ToOIDCOutput() OIDCOutput {
+	return i.ToOIDCOutputWithContext(context.Background())
+}
+
+func (i OIDCArgs) ToOIDCOutputWithContext(ctx context.Context) OIDCOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(OIDCOutput)
+}
+
+func (i OIDCArgs) ToOIDCPtrOutput() OIDCPtrOutput {
+	return i.ToOIDCPtrOutputWithContext(context.Background())
+}
+
+func (i OIDCArgs) ToOIDCPtrOutputWithContext(ctx context.Context) OIDCPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(OIDCOutput).ToOIDCPtrOutputWithContext(ctx)
+}
+
+// OIDCPtrInput is an input type that accepts OIDCArgs, OIDCPtr and OIDCPtrOutput values.
+// You can construct a concrete instance of `OIDCPtrInput` via:
+//
+//	        OIDCArgs{...}
+//
+//	or:
+//
+//	        nil
+type OIDCPtrInput interface {
+	pulumi.Input
+
+	ToOIDCPtrOutput() OIDCPtrOutput
+	ToOIDCPtrOutputWithContext(context.Context) OIDCPtrOutput
+}
+
+type oidcPtrType OIDCArgs
+
+func OIDCPtr(v *OIDCArgs) OIDCPtrInput {
+	return (*oidcPtrType)(v)
+}
+
+func (*oidcPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**OIDC)(nil)).Elem()
+}
+
+func (i *oidcPtrType) ToOIDCPtrOutput() OIDCPtrOutput {
+	return i.ToOIDCPtrOutputWithContext(context.Background())
+}
+
+func (i *oidcPtrType) ToOIDCPtrOutputWithContext(ctx context.Context) OIDCPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(OIDCPtrOutput)
+}
+
+type OIDCOutput struct{ *pulumi.OutputState }
+
+func (OIDCOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*OIDC)(nil)).Elem()
+}
+
+func (o OIDCOutput) ToOIDCOutput() OIDCOutput {
+	return o
+}
+
+func (o OIDCOutput) ToOIDCOutputWithContext(ctx context.Context) OIDCOutput {
+	return o
+}
+
+func (o OIDCOutput) ToOIDCPtrOutput() OIDCPtrOutput {
+	return o.ToOIDCPtrOutputWithContext(context.Background())
+}
+
+func (o OIDCOutput) ToOIDCPtrOutputWithContext(ctx context.Context) OIDCPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v OIDC) *OIDC {
+		return &v
+	}).(OIDCPtrOutput)
+}
+
+func (o OIDCOutput) CaFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.CaFile }).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCOutput) ClientId() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.ClientId }).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCOutput) ExtraScopes() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v OIDC) []string { return v.ExtraScopes }).(pulumi.StringArrayOutput)
+}
+
+func (o OIDCOutput) GroupsClaim() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.GroupsClaim }).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCOutput) GroupsPrefix() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.GroupsPrefix }).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCOutput) IssuerUrl() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.IssuerUrl }).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCOutput) UsernameClaim() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.UsernameClaim }).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCOutput) UsernamePrefix() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v OIDC) *string { return v.UsernamePrefix }).(pulumi.StringPtrOutput)
+}
+
+type OIDCPtrOutput struct{ *pulumi.OutputState }
+
+func (OIDCPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**OIDC)(nil)).Elem()
+}
+
+func (o OIDCPtrOutput) ToOIDCPtrOutput() OIDCPtrOutput {
+	return o
+}
+
+func (o OIDCPtrOutput) ToOIDCPtrOutputWithContext(ctx context.Context) OIDCPtrOutput {
+	return o
+}
+
+func (o OIDCPtrOutput) Elem() OIDCOutput {
+	return o.ApplyT(func(v *OIDC) OIDC {
+		if v != nil {
+			return *v
+		}
+		var ret OIDC
+		return ret
+	}).(OIDCOutput)
+}
+
+func (o OIDCPtrOutput) CaFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.CaFile
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCPtrOutput) ClientId() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.ClientId
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCPtrOutput) ExtraScopes() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v *OIDC) []string {
+		if v == nil {
+			return nil
+		}
+		return v.ExtraScopes
+	}).(pulumi.StringArrayOutput)
+}
+
+func (o OIDCPtrOutput) GroupsClaim() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.GroupsClaim
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCPtrOutput) GroupsPrefix() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.GroupsPrefix
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCPtrOutput) IssuerUrl() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.IssuerUrl
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCPtrOutput) UsernameClaim() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.UsernameClaim
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o OIDCPtrOutput) UsernamePrefix() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *OIDC) *string {
+		if v == nil {
+			return nil
+		}
+		return v.UsernamePrefix
+	}).(pulumi.StringPtrOutput)
+}
+
+type Phase struct {
+	Backoff *string `pulumi:"backoff"`
+	Retries *int    `pulumi:"retries"`
+	Timeout *string `pulumi:"timeout"`
+}
+
+// PhaseInput is an input type that accepts PhaseArgs and PhaseOutput values.
+// You can construct a concrete instance of `PhaseInput` via:
+//
+//	PhaseArgs{...}
+type PhaseInput interface {
+	pulumi.Input
+
+	ToPhaseOutput() PhaseOutput
+	ToPhaseOutputWithContext(context.Context) PhaseOutput
+}
+
+type PhaseArgs struct {
+	Backoff pulumi.StringPtrInput `pulumi:"backoff"`
+	Retries pulumi.IntPtrInput    `pulumi:"retries"`
+	Timeout pulumi.StringPtrInput `pulumi:"timeout"`
+}
+
+func (PhaseArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Phase)(nil)).Elem()
+}
+
+func (i PhaseArgs) ToPhaseOutput() PhaseOutput {
+	return i.ToPhaseOutputWithContext(context.Background())
+}
+
+func (i PhaseArgs) ToPhaseOutputWithContext(ctx context.Context) PhaseOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PhaseOutput)
+}
+
+func (i PhaseArgs) ToPhasePtrOutput() PhasePtrOutput {
+	return i.ToPhasePtrOutputWithContext(context.Background())
+}
+
+func (i PhaseArgs) ToPhasePtrOutputWithContext(ctx context.Context) PhasePtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PhaseOutput).ToPhasePtrOutputWithContext(ctx)
+}
+
+// PhasePtrInput is an input type that accepts PhaseArgs, PhasePtr and PhasePtrOutput values.
+// You can construct a concrete instance of `PhasePtrInput` via:
+//
+//	        PhaseArgs{...}
+//
+//	or:
+//
+//	        nil
+type PhasePtrInput interface {
+	pulumi.Input
+
+	ToPhasePtrOutput() PhasePtrOutput
+	ToPhasePtrOutputWithContext(context.Context) PhasePtrOutput
+}
+
+type phasePtrType PhaseArgs
+
+func PhasePtr(v *PhaseArgs) PhasePtrInput {
+	return (*phasePtrType)(v)
+}
+
+func (*phasePtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Phase)(nil)).Elem()
+}
+
+func (i *phasePtrType) ToPhasePtrOutput() PhasePtrOutput {
+	return i.ToPhasePtrOutputWithContext(context.Background())
+}
+
+func (i *phasePtrType) ToPhasePtrOutputWithContext(ctx context.Context) PhasePtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PhasePtrOutput)
+}
+
+type PhaseOutput struct{ *pulumi.OutputState }
+
+func (PhaseOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Phase)(nil)).Elem()
+}
+
+func (o PhaseOutput) ToPhaseOutput() PhaseOutput {
+	return o
+}
+
+func (o PhaseOutput) ToPhaseOutputWithContext(ctx context.Context) PhaseOutput {
+	return o
+}
+
+func (o PhaseOutput) ToPhasePtrOutput() PhasePtrOutput {
+	return o.ToPhasePtrOutputWithContext(context.Background())
+}
+
+func (o PhaseOutput) ToPhasePtrOutputWithContext(ctx context.Context) PhasePtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Phase) *Phase {
+		return &v
+	}).(PhasePtrOutput)
+}
+
+func (o PhaseOutput) Backoff() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Phase) *string { return v.Backoff }).(pulumi.StringPtrOutput)
+}
+
+func (o PhaseOutput) Retries() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Phase) *int { return v.Retries }).(pulumi.IntPtrOutput)
+}
+
+func (o PhaseOutput) Timeout() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Phase) *string { return v.Timeout }).(pulumi.StringPtrOutput)
+}
+
+type PhasePtrOutput struct{ *pulumi.OutputState }
+
+func (PhasePtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Phase)(nil)).Elem()
+}
+
+func (o PhasePtrOutput) ToPhasePtrOutput() PhasePtrOutput {
+	return o
+}
+
+func (o PhasePtrOutput) ToPhasePtrOutputWithContext(ctx context.Context) PhasePtrOutput {
+	return o
+}
+
+func (o PhasePtrOutput) Elem() PhaseOutput {
+	return o.ApplyT(func(v *Phase) Phase {
+		if v != nil {
+			return *v
+		}
+		var ret Phase
+		return ret
+	}).(PhaseOutput)
+}
+
+func (o PhasePtrOutput) Backoff() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Phase) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Backoff
+	}).(pulumi.StringPtrOutput)
+}
+
+func (o PhasePtrOutput) Retries() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Phase) *int {
+		if v == nil {
+			return nil
+		}
+		return v.Retries
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o PhasePtrOutput) Timeout() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v *Phase) *string {
+		if v == nil {
+			return nil
+		}
+		return v.Timeout
+	}).(pulumi.StringPtrOutput)
+}
+
+type PortMapping struct {
+	Source *int `pulumi:"source"`
+	Target *int `pulumi:"target"`
+}
+
+// PortMappingInput is an input type that accepts PortMappingArgs and PortMappingOutput values.
+// You can construct a concrete instance of `PortMappingInput` via:
+//
+//	PortMappingArgs{...}
+type PortMappingInput interface {
+	pulumi.Input
+
+	ToPortMappingOutput() PortMappingOutput
+	ToPortMappingOutputWithContext(context.Context) PortMappingOutput
+}
+
+type PortMappingArgs struct {
+	Source pulumi.IntPtrInput `pulumi:"source"`
+	Target pulumi.IntPtrInput `pulumi:"target"`
+}
+
+func (PortMappingArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*PortMapping)(nil)).Elem()
+}
+
+func (i PortMappingArgs) ToPortMappingOutput() PortMappingOutput {
+	return i.ToPortMappingOutputWithContext(context.Background())
+}
+
+func (i PortMappingArgs) ToPortMappingOutputWithContext(ctx context.Context) PortMappingOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PortMappingOutput)
+}
+
+// PortMappingMapInput is an input type that accepts PortMappingMap and PortMappingMapOutput values.
+// You can construct a concrete instance of `PortMappingMapInput` via:
+//
+//	PortMappingMap{ "key": PortMappingArgs{...} }
+type PortMappingMapInput interface {
+	pulumi.Input
+
+	ToPortMappingMapOutput() PortMappingMapOutput
+	ToPortMappingMapOutputWithContext(context.Context) PortMappingMapOutput
+}
+
+type PortMappingMap map[string]PortMappingInput
+
+func (PortMappingMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]PortMapping)(nil)).Elem()
+}
+
+func (i PortMappingMap) ToPortMappingMapOutput() PortMappingMapOutput {
+	return i.ToPortMappingMapOutputWithContext(context.Background())
+}
+
+func (i PortMappingMap) ToPortMappingMapOutputWithContext(ctx context.Context) PortMappingMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(PortMappingMapOutput)
+}
+
+type PortMappingOutput struct{ *pulumi.OutputState }
+
+func (PortMappingOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*PortMapping)(nil)).Elem()
+}
+
+func (o PortMappingOutput) ToPortMappingOutput() PortMappingOutput {
+	return o
+}
+
+func (o PortMappingOutput) ToPortMappingOutputWithContext(ctx context.Context) PortMappingOutput {
+	return o
+}
+
+func (o PortMappingOutput) Source() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v PortMapping) *int { return v.Source }).(pulumi.IntPtrOutput)
+}
+
+func (o PortMappingOutput) Target() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v PortMapping) *int { return v.Target }).(pulumi.IntPtrOutput)
+}
+
+type PortMappingMapOutput struct{ *pulumi.OutputState }
+
+func (PortMappingMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]PortMapping)(nil)).Elem()
+}
+
+func (o PortMappingMapOutput) ToPortMappingMapOutput() PortMappingMapOutput {
+	return o
+}
+
+func (o PortMappingMapOutput) ToPortMappingMapOutputWithContext(ctx context.Context) PortMappingMapOutput {
+	return o
+}
+
+func (o PortMappingMapOutput) MapIndex(k pulumi.StringInput) PortMappingOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) PortMapping {
+		return vs[0].(map[string]PortMapping)[vs[1].(string)]
+	}).(PortMappingOutput)
+}
+
+type Provisioning struct {
+	Addons            *Phase `pulumi:"addons"`
+	Init              *Phase `pulumi:"init"`
+	Join              *Phase `pulumi:"join"`
+	MaxFailPercentage *int   `pulumi:"maxFailPercentage"`
+	Nodes             *Phase `pulumi:"nodes"`
+}
+
+// ProvisioningInput is an input type that accepts ProvisioningArgs and ProvisioningOutput values.
+// You can construct a concrete instance of `ProvisioningInput` via:
+//
+//	ProvisioningArgs{...}
+type ProvisioningInput interface {
+	pulumi.Input
+
+	ToProvisioningOutput() ProvisioningOutput
+	ToProvisioningOutputWithContext(context.Context) ProvisioningOutput
+}
+
+type ProvisioningArgs struct {
+	Addons            PhasePtrInput      `pulumi:"addons"`
+	Init              PhasePtrInput      `pulumi:"init"`
+	Join              PhasePtrInput      `pulumi:"join"`
+	MaxFailPercentage pulumi.IntPtrInput `pulumi:"maxFailPercentage"`
+	Nodes             PhasePtrInput      `pulumi:"nodes"`
+}
+
+func (ProvisioningArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Provisioning)(nil)).Elem()
+}
+
+func (i ProvisioningArgs) ToProvisioningOutput() ProvisioningOutput {
+	return i.ToProvisioningOutputWithContext(context.Background())
+}
+
+func (i ProvisioningArgs) ToProvisioningOutputWithContext(ctx context.Context) ProvisioningOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ProvisioningOutput)
+}
+
+func (i ProvisioningArgs) ToProvisioningPtrOutput() ProvisioningPtrOutput {
+	return i.ToProvisioningPtrOutputWithContext(context.Background())
+}
+
+func (i ProvisioningArgs) ToProvisioningPtrOutputWithContext(ctx context.Context) ProvisioningPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ProvisioningOutput).ToProvisioningPtrOutputWithContext(ctx)
+}
+
+// ProvisioningPtrInput is an input type that accepts ProvisioningArgs, ProvisioningPtr and ProvisioningPtrOutput values.
+// You can construct a concrete instance of `ProvisioningPtrInput` via:
+//
+//	        ProvisioningArgs{...}
+//
+//	or:
+//
+//	        nil
+type ProvisioningPtrInput interface {
+	pulumi.Input
+
+	ToProvisioningPtrOutput() ProvisioningPtrOutput
+	ToProvisioningPtrOutputWithContext(context.Context) ProvisioningPtrOutput
+}
+
+type provisioningPtrType ProvisioningArgs
+
+func ProvisioningPtr(v *ProvisioningArgs) ProvisioningPtrInput {
+	return (*provisioningPtrType)(v)
+}
+
+func (*provisioningPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Provisioning)(nil)).Elem()
+}
+
+func (i *provisioningPtrType) ToProvisioningPtrOutput() ProvisioningPtrOutput {
+	return i.ToProvisioningPtrOutputWithContext(context.Background())
+}
+
+func (i *provisioningPtrType) ToProvisioningPtrOutputWithContext(ctx context.Context) ProvisioningPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ProvisioningPtrOutput)
+}
+
+type ProvisioningOutput struct{ *pulumi.OutputState }
+
+func (ProvisioningOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Provisioning)(nil)).Elem()
+}
+
+func (o ProvisioningOutput) ToProvisioningOutput() ProvisioningOutput {
+	return o
+}
+
+func (o ProvisioningOutput) ToProvisioningOutputWithContext(ctx context.Context) ProvisioningOutput {
+	return o
+}
+
+func (o ProvisioningOutput) ToProvisioningPtrOutput() ProvisioningPtrOutput {
+	return o.ToProvisioningPtrOutputWithContext(context.Background())
+}
+
+func (o ProvisioningOutput) ToProvisioningPtrOutputWithContext(ctx context.Context) ProvisioningPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Provisioning) *Provisioning {
+		return &v
+	}).(ProvisioningPtrOutput)
+}
+
+func (o ProvisioningOutput) Addons() PhasePtrOutput {
+	return o.ApplyT(func(v Provisioning) *Phase { return v.Addons }).(PhasePtrOutput)
+}
+
+func (o ProvisioningOutput) Init() PhasePtrOutput {
+	return o.ApplyT(func(v Provisioning) *Phase { return v.Init }).(PhasePtrOutput)
+}
+
+func (o ProvisioningOutput) Join() PhasePtrOutput {
+	return o.ApplyT(func(v Provisioning) *Phase { return v.Join }).(PhasePtrOutput)
+}
+
+func (o ProvisioningOutput) MaxFailPercentage() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Provisioning) *int { return v.MaxFailPercentage }).(pulumi.IntPtrOutput)
+}
+
+func (o ProvisioningOutput) Nodes() PhasePtrOutput {
+	return o.ApplyT(func(v Provisioning) *Phase { return v.Nodes }).(PhasePtrOutput)
+}
+
+type ProvisioningPtrOutput struct{ *pulumi.OutputState }
+
+func (ProvisioningPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Provisioning)(nil)).Elem()
+}
+
+func (o ProvisioningPtrOutput) ToProvisioningPtrOutput() ProvisioningPtrOutput {
+	return o
+}
+
+func (o ProvisioningPtrOutput) ToProvisioningPtrOutputWithContext(ctx context.Context) ProvisioningPtrOutput {
+	return o
+}
+
+func (o ProvisioningPtrOutput) Elem() ProvisioningOutput {
+	return o.ApplyT(func(v *Provisioning) Provisioning {
+		if v != nil {
+			return *v
+		}
+		var ret Provisioning
+		return ret
+	}).(ProvisioningOutput)
+}
+
+func (o ProvisioningPtrOutput) Addons() PhasePtrOutput {
+	return o.ApplyT(func(v *Provisioning) *Phase {
+		if v == nil {
+			return nil
+		}
+		return v.Addons
+	}).(PhasePtrOutput)
+}
+
+func (o ProvisioningPtrOutput) Init() PhasePtrOutput {
+	return o.ApplyT(func(v *Provisioning) *Phase {
+		if v == nil {
+			return nil
+		}
+		return v.Init
+	}).(PhasePtrOutput)
+}
+
+func (o ProvisioningPtrOutput) Join() PhasePtrOutput {
+	return o.ApplyT(func(v *Provisioning) *Phase {
+		if v == nil {
+			return nil
+		}
+		return v.Join
+	}).(PhasePtrOutput)
+}
+
+func (o ProvisioningPtrOutput) MaxFailPercentage() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Provisioning) *int {
+		if v == nil {
+			return nil
+		}
+		return v.MaxFailPercentage
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o ProvisioningPtrOutput) Nodes() PhasePtrOutput {
+	return o.ApplyT(func(v *Provisioning) *Phase {
+		if v == nil {
+			return nil
+		}
+		return v.Nodes
+	}).(PhasePtrOutput)
+}
+
+type Registries struct {
+	Hosts                map[string]Registry `pulumi:"hosts"`
+	PullSecretNamespaces []string            `pulumi:"pullSecretNamespaces"`
+}
+
+// RegistriesInput is an input type that accepts RegistriesArgs and RegistriesOutput values.
+// You can construct a concrete instance of `RegistriesInput` via:
+//
+//	RegistriesArgs{...}
+type RegistriesInput interface {
+	pulumi.Input
+
+	ToRegistriesOutput() RegistriesOutput
+	ToRegistriesOutputWithContext(context.Context) RegistriesOutput
+}
+
+type RegistriesArgs struct {
+	Hosts                RegistryMapInput        `pulumi:"hosts"`
+	PullSecretNamespaces pulumi.StringArrayInput `pulumi:"pullSecretNamespaces"`
+}
+
+func (RegistriesArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Registries)(nil)).Elem()
+}
+
+func (i RegistriesArgs) ToRegistriesOutput() RegistriesOutput {
+	return i.ToRegistriesOutputWithContext(context.Background())
+}
+
+func (i RegistriesArgs) ToRegistriesOutputWithContext(ctx context.Context) RegistriesOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistriesOutput)
+}
+
+func (i RegistriesArgs) ToRegistriesPtrOutput() RegistriesPtrOutput {
+	return i.ToRegistriesPtrOutputWithContext(context.Background())
+}
+
+func (i RegistriesArgs) ToRegistriesPtrOutputWithContext(ctx context.Context) RegistriesPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistriesOutput).ToRegistriesPtrOutputWithContext(ctx)
+}
+
+// RegistriesPtrInput is an input type that accepts RegistriesArgs, RegistriesPtr and RegistriesPtrOutput values.
+// You can construct a concrete instance of `RegistriesPtrInput` via:
+//
+//	        RegistriesArgs{...}
+//
+//	or:
+//
+//	        nil
+type RegistriesPtrInput interface {
+	pulumi.Input
+
+	ToRegistriesPtrOutput() RegistriesPtrOutput
+	ToRegistriesPtrOutputWithContext(context.Context) RegistriesPtrOutput
+}
+
+type registriesPtrType RegistriesArgs
+
+func RegistriesPtr(v *RegistriesArgs) RegistriesPtrInput {
+	return (*registriesPtrType)(v)
+}
+
+func (*registriesPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Registries)(nil)).Elem()
+}
+
+func (i *registriesPtrType) ToRegistriesPtrOutput() RegistriesPtrOutput {
+	return i.ToRegistriesPtrOutputWithContext(context.Background())
+}
+
+func (i *registriesPtrType) ToRegistriesPtrOutputWithContext(ctx context.Context) RegistriesPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistriesPtrOutput)
+}
+
+type RegistriesOutput struct{ *pulumi.OutputState }
+
+func (RegistriesOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Registries)(nil)).Elem()
+}
+
+func (o RegistriesOutput) ToRegistriesOutput() RegistriesOutput {
+	return o
+}
+
+func (o RegistriesOutput) ToRegistriesOutputWithContext(ctx context.Context) RegistriesOutput {
+	return o
+}
+
+func (o RegistriesOutput) ToRegistriesPtrOutput() RegistriesPtrOutput {
+	return o.ToRegistriesPtrOutputWithContext(context.Background())
+}
+
+func (o RegistriesOutput) ToRegistriesPtrOutputWithContext(ctx context.Context) RegistriesPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Registries) *Registries {
+		return &v
+	}).(RegistriesPtrOutput)
+}
+
+func (o RegistriesOutput) Hosts() RegistryMapOutput {
+	return o.ApplyT(func(v Registries) map[string]Registry { return v.Hosts }).(RegistryMapOutput)
+}
+
+func (o RegistriesOutput) PullSecretNamespaces() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Registries) []string { return v.PullSecretNamespaces }).(pulumi.StringArrayOutput)
+}
+
+type RegistriesPtrOutput struct{ *pulumi.OutputState }
+
+func (RegistriesPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Registries)(nil)).Elem()
+}
+
+func (o RegistriesPtrOutput) ToRegistriesPtrOutput() RegistriesPtrOutput {
+	return o
+}
+
+func (o RegistriesPtrOutput) ToRegistriesPtrOutputWithContext(ctx context.Context) RegistriesPtrOutput {
+	return o
+}
+
+func (o RegistriesPtrOutput) Elem() RegistriesOutput {
+	return o.ApplyT(func(v *Registries) Registries {
+		if v != nil {
+			return *v
+		}
+		var ret Registries
+		return ret
+	}).(RegistriesOutput)
+}
+
+func (o RegistriesPtrOutput) Hosts() RegistryMapOutput {
+	return o.ApplyT(func(v *Registries) map[string]Registry {
+		if v == nil {
+			return nil
+		}
+		return v.Hosts
+	}).(RegistryMapOutput)
+}
+
+func (o RegistriesPtrOutput) PullSecretNamespaces() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v *Registries) []string {
+		if v == nil {
+			return nil
+		}
+		return v.PullSecretNamespaces
+	}).(pulumi.StringArrayOutput)
+}
+
+type Registry struct {
+	CaFile   *string  `pulumi:"caFile"`
+	Insecure *bool    `pulumi:"insecure"`
+	Mirrors  []string `pulumi:"mirrors"`
+}
+
+// RegistryInput is an input type that accepts RegistryArgs and RegistryOutput values.
+// You can construct a concrete instance of `RegistryInput` via:
+//
+//	RegistryArgs{...}
+type RegistryInput interface {
+	pulumi.Input
+
+	ToRegistryOutput() RegistryOutput
+	ToRegistryOutputWithContext(context.Context) RegistryOutput
+}
+
+type RegistryArgs struct {
+	CaFile   pulumi.StringPtrInput   `pulumi:"caFile"`
+	Insecure pulumi.BoolPtrInput     `pulumi:"insecure"`
+	Mirrors  pulumi.StringArrayInput `pulumi:"mirrors"`
+}
+
+func (RegistryArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Registry)(nil)).Elem()
+}
+
+func (i RegistryArgs) ToRegistryOutput() RegistryOutput {
+	return i.ToRegistryOutputWithContext(context.Background())
+}
+
+func (i RegistryArgs) ToRegistryOutputWithContext(ctx context.Context) RegistryOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryOutput)
+}
+
+// RegistryMapInput is an input type that accepts RegistryMap and RegistryMapOutput values.
+// You can construct a concrete instance of `RegistryMapInput` via:
+//
+//	RegistryMap{ "key": RegistryArgs{...} }
+type RegistryMapInput interface {
+	pulumi.Input
+
+	ToRegistryMapOutput() RegistryMapOutput
+	ToRegistryMapOutputWithContext(context.Context) RegistryMapOutput
+}
+
+type RegistryMap map[string]RegistryInput
+
+func (RegistryMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]Registry)(nil)).Elem()
+}
+
+func (i RegistryMap) ToRegistryMapOutput() RegistryMapOutput {
+	return i.ToRegistryMapOutputWithContext(context.Background())
+}
+
+func (i RegistryMap) ToRegistryMapOutputWithContext(ctx context.Context) RegistryMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryMapOutput)
+}
+
+type RegistryOutput struct{ *pulumi.OutputState }
+
+func (RegistryOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Registry)(nil)).Elem()
+}
+
+func (o RegistryOutput) ToRegistryOutput() RegistryOutput {
+	return o
+}
+
+func (o RegistryOutput) ToRegistryOutputWithContext(ctx context.Context) RegistryOutput {
+	return o
+}
+
+func (o RegistryOutput) CaFile() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Registry) *string { return v.CaFile }).(pulumi.StringPtrOutput)
+}
+
+func (o RegistryOutput) Insecure() pulumi.BoolPtrOutput {
+	return o.ApplyT(func(v Registry) *bool { return v.Insecure }).(pulumi.BoolPtrOutput)
+}
+
+func (o RegistryOutput) Mirrors() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Registry) []string { return v.Mirrors }).(pulumi.StringArrayOutput)
+}
+
+type RegistryMapOutput struct{ *pulumi.OutputState }
+
+func (RegistryMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]Registry)(nil)).Elem()
+}
+
+func (o RegistryMapOutput) ToRegistryMapOutput() RegistryMapOutput {
+	return o
+}
+
+func (o RegistryMapOutput) ToRegistryMapOutputWithContext(ctx context.Context) RegistryMapOutput {
+	return o
+}
+
+func (o RegistryMapOutput) MapIndex(k pulumi.StringInput) RegistryOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) Registry {
+		return vs[0].(map[string]Registry)[vs[1].(string)]
+	}).(RegistryOutput)
+}
+
+type RegistryCredentials struct {
+	Password *string `pulumi:"password"`
+	Username *string `pulumi:"username"`
+}
+
+// RegistryCredentialsInput is an input type that accepts RegistryCredentialsArgs and RegistryCredentialsOutput values.
+// You can construct a concrete instance of `RegistryCredentialsInput` via:
+//
+//	RegistryCredentialsArgs{...}
+type RegistryCredentialsInput interface {
+	pulumi.Input
+
+	ToRegistryCredentialsOutput() RegistryCredentialsOutput
+	ToRegistryCredentialsOutputWithContext(context.Context) RegistryCredentialsOutput
+}
+
+type RegistryCredentialsArgs struct {
+	Password pulumi.StringPtrInput `pulumi:"password"`
+	Username pulumi.StringPtrInput `pulumi:"username"`
+}
+
+func (RegistryCredentialsArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*RegistryCredentials)(nil)).Elem()
+}
+
+func (i RegistryCredentialsArgs) ToRegistryCredentialsOutput() RegistryCredentialsOutput {
+	return i.ToRegistryCredentialsOutputWithContext(context.Background())
+}
+
+func (i RegistryCredentialsArgs) ToRegistryCredentialsOutputWithContext(ctx context.Context) RegistryCredentialsOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryCredentialsOutput)
+}
+
+// RegistryCredentialsMapInput is an input type that accepts RegistryCredentialsMap and RegistryCredentialsMapOutput values.
+// You can construct a concrete instance of `RegistryCredentialsMapInput` via:
+//
+//	RegistryCredentialsMap{ "key": RegistryCredentialsArgs{...} }
+type RegistryCredentialsMapInput interface {
+	pulumi.Input
+
+	ToRegistryCredentialsMapOutput() RegistryCredentialsMapOutput
+	ToRegistryCredentialsMapOutputWithContext(context.Context) RegistryCredentialsMapOutput
+}
+
+type RegistryCredentialsMap map[string]RegistryCredentialsInput
+
+func (RegistryCredentialsMap) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]RegistryCredentials)(nil)).Elem()
+}
+
+func (i RegistryCredentialsMap) ToRegistryCredentialsMapOutput() RegistryCredentialsMapOutput {
+	return i.ToRegistryCredentialsMapOutputWithContext(context.Background())
+}
+
+func (i RegistryCredentialsMap) ToRegistryCredentialsMapOutputWithContext(ctx context.Context) RegistryCredentialsMapOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(RegistryCredentialsMapOutput)
+}
+
+type RegistryCredentialsOutput struct{ *pulumi.OutputState }
+
+func (RegistryCredentialsOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*RegistryCredentials)(nil)).Elem()
+}
+
+func (o RegistryCredentialsOutput) ToRegistryCredentialsOutput() RegistryCredentialsOutput {
+	return o
+}
+
+func (o RegistryCredentialsOutput) ToRegistryCredentialsOutputWithContext(ctx context.Context) RegistryCredentialsOutput {
+	return o
+}
+
+func (o RegistryCredentialsOutput) Password() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v RegistryCredentials) *string { return v.Password }).(pulumi.StringPtrOutput)
+}
+
+func (o RegistryCredentialsOutput) Username() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v RegistryCredentials) *string { return v.Username }).(pulumi.StringPtrOutput)
+}
+
+type RegistryCredentialsMapOutput struct{ *pulumi.OutputState }
+
+func (RegistryCredentialsMapOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*map[string]RegistryCredentials)(nil)).Elem()
+}
+
+func (o RegistryCredentialsMapOutput) ToRegistryCredentialsMapOutput() RegistryCredentialsMapOutput {
+	return o
+}
+
+func (o RegistryCredentialsMapOutput) ToRegistryCredentialsMapOutputWithContext(ctx context.Context) RegistryCredentialsMapOutput {
+	return o
+}
+
+func (o RegistryCredentialsMapOutput) MapIndex(k pulumi.StringInput) RegistryCredentialsOutput {
+	return pulumi.All(o, k).ApplyT(func(vs []interface{}) RegistryCredentials {
+		return vs[0].(map[string]RegistryCredentials)[vs[1].(string)]
+	}).(RegistryCredentialsOutput)
+}
+
+type Release struct {
+	Chart       *string  `pulumi:"chart"`
+	Name        *string  `pulumi:"name"`
+	Namespace   *string  `pulumi:"namespace"`
+	Needs       []string `pulumi:"needs"`
+	Repo        *string  `pulumi:"repo"`
+	ValuesFiles []string `pulumi:"valuesFiles"`
+	Version     *string  `pulumi:"version"`
+}
+
+// ReleaseInput is an input type that accepts ReleaseArgs and ReleaseOutput values.
+// You can construct a concrete instance of `ReleaseInput` via:
+//
+//	ReleaseArgs{...}
+type ReleaseInput interface {
+	pulumi.Input
+
+	ToReleaseOutput() ReleaseOutput
+	ToReleaseOutputWithContext(context.Context) ReleaseOutput
+}
+
+type ReleaseArgs struct {
+	Chart       pulumi.StringPtrInput   `pulumi:"chart"`
+	Name        pulumi.StringPtrInput   `pulumi:"name"`
+	Namespace   pulumi.StringPtrInput   `pulumi:"namespace"`
+	Needs       pulumi.StringArrayInput `pulumi:"needs"`
+	Repo        pulumi.StringPtrInput   `pulumi:"repo"`
+	ValuesFiles pulumi.StringArrayInput `pulumi:"valuesFiles"`
+	Version     pulumi.StringPtrInput   `pulumi:"version"`
+}
+
+func (ReleaseArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Release)(nil)).Elem()
+}
+
+func (i ReleaseArgs) ToReleaseOutput() ReleaseOutput {
+	return i.ToReleaseOutputWithContext(context.Background())
+}
+
+func (i ReleaseArgs) ToReleaseOutputWithContext(ctx context.Context) ReleaseOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ReleaseOutput)
+}
+
+// ReleaseArrayInput is an input type that accepts ReleaseArray and ReleaseArrayOutput values.
+// You can construct a concrete instance of `ReleaseArrayInput` via:
+//
+//	ReleaseArray{ ReleaseArgs{...} }
+type ReleaseArrayInput interface {
+	pulumi.Input
+
+	ToReleaseArrayOutput() ReleaseArrayOutput
+	ToReleaseArrayOutputWithContext(context.Context) ReleaseArrayOutput
+}
+
+type ReleaseArray []ReleaseInput
+
+func (ReleaseArray) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]Release)(nil)).Elem()
+}
+
+func (i ReleaseArray) ToReleaseArrayOutput() ReleaseArrayOutput {
+	return i.ToReleaseArrayOutputWithContext(context.Background())
+}
+
+func (i ReleaseArray) ToReleaseArrayOutputWithContext(ctx context.Context) ReleaseArrayOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(ReleaseArrayOutput)
+}
+
+type ReleaseOutput struct{ *pulumi.OutputState }
+
+func (ReleaseOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Release)(nil)).Elem()
+}
+
+func (o ReleaseOutput) ToReleaseOutput() ReleaseOutput {
+	return o
+}
+
+func (o ReleaseOutput) ToReleaseOutputWithContext(ctx context.Context) ReleaseOutput {
+	return o
+}
+
+func (o ReleaseOutput) Chart() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Release) *string { return v.Chart }).(pulumi.StringPtrOutput)
+}
+
+func (o ReleaseOutput) Name() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Release) *string { return v.Name }).(pulumi.StringPtrOutput)
+}
+
+func (o ReleaseOutput) Namespace() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Release) *string { return v.Namespace }).(pulumi.StringPtrOutput)
+}
+
+func (o ReleaseOutput) Needs() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Release) []string { return v.Needs }).(pulumi.StringArrayOutput)
+}
+
+func (o ReleaseOutput) Repo() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Release) *string { return v.Repo }).(pulumi.StringPtrOutput)
+}
+
+func (o ReleaseOutput) ValuesFiles() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Release) []string { return v.ValuesFiles }).(pulumi.StringArrayOutput)
+}
+
+func (o ReleaseOutput) Version() pulumi.StringPtrOutput {
+	return o.ApplyT(func(v Release) *string { return v.Version }).(pulumi.StringPtrOutput)
+}
+
+type ReleaseArrayOutput struct{ *pulumi.OutputState }
+
+func (ReleaseArrayOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*[]Release)(nil)).Elem()
+}
+
+func (o ReleaseArrayOutput) ToReleaseArrayOutput() ReleaseArrayOutput {
+	return o
+}
+
+func (o ReleaseArrayOutput) ToReleaseArrayOutputWithContext(ctx context.Context) ReleaseArrayOutput {
+	return o
+}
+
+func (o ReleaseArrayOutput) Index(i pulumi.IntInput) ReleaseOutput {
+	return pulumi.All(o, i).ApplyT(func(vs []interface{}) Release {
+		return vs[0].([]Release)[vs[1].(int)]
+	}).(ReleaseOutput)
+}
+
+type Worker struct {
+	Labels    map[string]string `pulumi:"labels"`
+	Max       *int              `pulumi:"max"`
+	Min       *int              `pulumi:"min"`
+	NodeCount *int              `pulumi:"nodeCount"`
+	Taints    []string          `pulumi:"taints"`
+}
+
+// WorkerInput is an input type that accepts WorkerArgs and WorkerOutput values.
+// You can construct a concrete instance of `WorkerInput` via:
+//
+//	WorkerArgs{...}
+type WorkerInput interface {
+	pulumi.Input
+
+	ToWorkerOutput() WorkerOutput
+	ToWorkerOutputWithContext(context.Context) WorkerOutput
+}
+
+type WorkerArgs struct {
+	Labels    pulumi.StringMapInput   `pulumi:"labels"`
+	Max       pulumi.IntPtrInput      `pulumi:"max"`
+	Min       pulumi.IntPtrInput      `pulumi:"min"`
+	NodeCount pulumi.IntPtrInput      `pulumi:"nodeCount"`
+	Taints    pulumi.StringArrayInput `pulumi:"taints"`
+}
+
+func (WorkerArgs) ElementType() reflect.Type {
+	return reflect.TypeOf((*Worker)(nil)).Elem()
+}
+
+func (i WorkerArgs) ToWorkerOutput() WorkerOutput {
+	return i.ToWorkerOutputWithContext(context.Background())
+}
+
+func (i WorkerArgs) ToWorkerOutputWithContext(ctx context.Context) WorkerOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(WorkerOutput)
+}
+
+func (i WorkerArgs) ToWorkerPtrOutput() WorkerPtrOutput {
+	return i.ToWorkerPtrOutputWithContext(context.Background())
+}
+
+func (i WorkerArgs) ToWorkerPtrOutputWithContext(ctx context.Context) WorkerPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(WorkerOutput).ToWorkerPtrOutputWithContext(ctx)
+}
+
+// WorkerPtrInput is an input type that accepts WorkerArgs, WorkerPtr and WorkerPtrOutput values.
+// You can construct a concrete instance of `WorkerPtrInput` via:
+//
+//	        WorkerArgs{...}
+//
+//	or:
+//
+//	        nil
+type WorkerPtrInput interface {
+	pulumi.Input
+
+	ToWorkerPtrOutput() WorkerPtrOutput
+	ToWorkerPtrOutputWithContext(context.Context) WorkerPtrOutput
+}
+
+type workerPtrType WorkerArgs
+
+func WorkerPtr(v *WorkerArgs) WorkerPtrInput {
+	return (*workerPtrType)(v)
+}
+
+func (*workerPtrType) ElementType() reflect.Type {
+	return reflect.TypeOf((**Worker)(nil)).Elem()
+}
+
+func (i *workerPtrType) ToWorkerPtrOutput() WorkerPtrOutput {
+	return i.ToWorkerPtrOutputWithContext(context.Background())
+}
+
+func (i *workerPtrType) ToWorkerPtrOutputWithContext(ctx context.Context) WorkerPtrOutput {
+	return pulumi.ToOutputWithContext(ctx, i).(WorkerPtrOutput)
+}
+
+type WorkerOutput struct{ *pulumi.OutputState }
+
+func (WorkerOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((*Worker)(nil)).Elem()
+}
+
+func (o WorkerOutput) ToWorkerOutput() WorkerOutput {
+	return o
+}
+
+func (o WorkerOutput) ToWorkerOutputWithContext(ctx context.Context) WorkerOutput {
+	return o
+}
+
+func (o WorkerOutput) ToWorkerPtrOutput() WorkerPtrOutput {
+	return o.ToWorkerPtrOutputWithContext(context.Background())
+}
+
+func (o WorkerOutput) ToWorkerPtrOutputWithContext(ctx context.Context) WorkerPtrOutput {
+	return o.ApplyTWithContext(ctx, func(_ context.Context, v Worker) *Worker {
+		return &v
+	}).(WorkerPtrOutput)
+}
+
+func (o WorkerOutput) Labels() pulumi.StringMapOutput {
+	return o.ApplyT(func(v Worker) map[string]string { return v.Labels }).(pulumi.StringMapOutput)
+}
+
+func (o WorkerOutput) Max() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Worker) *int { return v.Max }).(pulumi.IntPtrOutput)
+}
+
+func (o WorkerOutput) Min() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Worker) *int { return v.Min }).(pulumi.IntPtrOutput)
+}
+
+func (o WorkerOutput) NodeCount() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v Worker) *int { return v.NodeCount }).(pulumi.IntPtrOutput)
+}
+
+func (o WorkerOutput) Taints() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v Worker) []string { return v.Taints }).(pulumi.StringArrayOutput)
+}
+
+type WorkerPtrOutput struct{ *pulumi.OutputState }
+
+func (WorkerPtrOutput) ElementType() reflect.Type {
+	return reflect.TypeOf((**Worker)(nil)).Elem()
+}
+
+func (o WorkerPtrOutput) ToWorkerPtrOutput() WorkerPtrOutput {
+	return o
+}
+
+func (o WorkerPtrOutput) ToWorkerPtrOutputWithContext(ctx context.Context) WorkerPtrOutput {
+	return o
+}
+
+func (o WorkerPtrOutput) Elem() WorkerOutput {
+	return o.ApplyT(func(v *Worker) Worker {
+		if v != nil {
+			return *v
+		}
+		var ret Worker
+		return ret
+	}).(WorkerOutput)
+}
+
+func (o WorkerPtrOutput) Labels() pulumi.StringMapOutput {
+	return o.ApplyT(func(v *Worker) map[string]string {
+		if v == nil {
+			return nil
+		}
+		return v.Labels
+	}).(pulumi.StringMapOutput)
+}
+
+func (o WorkerPtrOutput) Max() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Worker) *int {
+		if v == nil {
+			return nil
+		}
+		return v.Max
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o WorkerPtrOutput) Min() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Worker) *int {
+		if v == nil {
+			return nil
+		}
+		return v.Min
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o WorkerPtrOutput) NodeCount() pulumi.IntPtrOutput {
+	return o.ApplyT(func(v *Worker) *int {
+		if v == nil {
+			return nil
+		}
+		return v.NodeCount
+	}).(pulumi.IntPtrOutput)
+}
+
+func (o WorkerPtrOutput) Taints() pulumi.StringArrayOutput {
+	return o.ApplyT(func(v *Worker) []string {
+		if v == nil {
+			return nil
+		}
+		return v.Taints
+	}).(pulumi.StringArrayOutput)
+}
+
+func init() {
+	pulumi.RegisterInputType(reflect.TypeOf((*AddonInput)(nil)).Elem(), AddonArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*AddonMapInput)(nil)).Elem(), AddonMap{})
+	pulumi.RegisterInputType(reflect.TypeOf((*AuditInput)(nil)).Elem(), AuditArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*AuditPtrInput)(nil)).Elem(), AuditArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*BackupInput)(nil)).Elem(), BackupArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*BackupPtrInput)(nil)).Elem(), BackupArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*BackupCredentialsInput)(nil)).Elem(), BackupCredentialsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*BackupCredentialsPtrInput)(nil)).Elem(), BackupCredentialsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*CNIInput)(nil)).Elem(), CNIArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*CNIPtrInput)(nil)).Elem(), CNIArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*CertificatesInput)(nil)).Elem(), CertificatesArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*CertificatesPtrInput)(nil)).Elem(), CertificatesArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*CiliumInput)(nil)).Elem(), CiliumArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*CiliumPtrInput)(nil)).Elem(), CiliumArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterCredentialsInput)(nil)).Elem(), ClusterCredentialsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterCredentialsPtrInput)(nil)).Elem(), ClusterCredentialsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterMeshInput)(nil)).Elem(), ClusterMeshArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ClusterMeshPtrInput)(nil)).Elem(), ClusterMeshArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ComponentInput)(nil)).Elem(), ComponentArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ComponentPtrInput)(nil)).Elem(), ComponentArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ControlPlaneInput)(nil)).Elem(), ControlPlaneArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ControlPlanePtrInput)(nil)).Elem(), ControlPlaneArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*EncryptionInput)(nil)).Elem(), EncryptionArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*EncryptionPtrInput)(nil)).Elem(), EncryptionArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*GitOpsInput)(nil)).Elem(), GitOpsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*GitOpsPtrInput)(nil)).Elem(), GitOpsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*GitOpsCredentialsInput)(nil)).Elem(), GitOpsCredentialsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*GitOpsCredentialsPtrInput)(nil)).Elem(), GitOpsCredentialsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*HubbleInput)(nil)).Elem(), HubbleArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*HubblePtrInput)(nil)).Elem(), HubbleArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeProxyInput)(nil)).Elem(), KubeProxyArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeProxyPtrInput)(nil)).Elem(), KubeProxyArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeadmInput)(nil)).Elem(), KubeadmArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeadmPtrInput)(nil)).Elem(), KubeadmArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeletInput)(nil)).Elem(), KubeletArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*KubeletPtrInput)(nil)).Elem(), KubeletArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*LoadBalancerInput)(nil)).Elem(), LoadBalancerArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*LoadBalancerPtrInput)(nil)).Elem(), LoadBalancerArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ManifestInput)(nil)).Elem(), ManifestArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ManifestArrayInput)(nil)).Elem(), ManifestArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NtpInput)(nil)).Elem(), NtpArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*NtpPtrInput)(nil)).Elem(), NtpArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*OIDCInput)(nil)).Elem(), OIDCArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*OIDCPtrInput)(nil)).Elem(), OIDCArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*PhaseInput)(nil)).Elem(), PhaseArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*PhasePtrInput)(nil)).Elem(), PhaseArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*PortMappingInput)(nil)).Elem(), PortMappingArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*PortMappingMapInput)(nil)).Elem(), PortMappingMap{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ProvisioningInput)(nil)).Elem(), ProvisioningArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ProvisioningPtrInput)(nil)).Elem(), ProvisioningArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistriesInput)(nil)).Elem(), RegistriesArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistriesPtrInput)(nil)).Elem(), RegistriesArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistryInput)(nil)).Elem(), RegistryArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistryMapInput)(nil)).Elem(), RegistryMap{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistryCredentialsInput)(nil)).Elem(), RegistryCredentialsArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*RegistryCredentialsMapInput)(nil)).Elem(), RegistryCredentialsMap{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ReleaseInput)(nil)).Elem(), ReleaseArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*ReleaseArrayInput)(nil)).Elem(), ReleaseArray{})
+	pulumi.RegisterInputType(reflect.TypeOf((*WorkerInput)(nil)).Elem(), WorkerArgs{})
+	pulumi.RegisterInputType(reflect.TypeOf((*WorkerPtrInput)(nil)).Elem(), WorkerArgs{})
+	pulumi.RegisterOutputType(AddonOutput{})
+	pulumi.RegisterOutputType(AddonMapOutput{})
+	pulumi.RegisterOutputType(AuditOutput{})
+	pulumi.RegisterOutputType(AuditPtrOutput{})
+	pulumi.RegisterOutputType(BackupOutput{})
+	pulumi.RegisterOutputType(BackupPtrOutput{})
+	pulumi.RegisterOutputType(BackupCredentialsOutput{})
+	pulumi.RegisterOutputType(BackupCredentialsPtrOutput{})
+	pulumi.RegisterOutputType(CNIOutput{})
+	pulumi.RegisterOutputType(CNIPtrOutput{})
+	pulumi.RegisterOutputType(CertificatesOutput{})
+	pulumi.RegisterOutputType(CertificatesPtrOutput{})
+	pulumi.RegisterOutputType(CiliumOutput{})
+	pulumi.RegisterOutputType(CiliumPtrOutput{})
+	pulumi.RegisterOutputType(ClusterCredentialsOutput{})
+	pulumi.RegisterOutputType(ClusterCredentialsPtrOutput{})
+	pulumi.RegisterOutputType(ClusterMeshOutput{})
+	pulumi.RegisterOutputType(ClusterMeshPtrOutput{})
+	pulumi.RegisterOutputType(ComponentOutput{})
+	pulumi.RegisterOutputType(ComponentPtrOutput{})
+	pulumi.RegisterOutputType(ControlPlaneOutput{})
+	pulumi.RegisterOutputType(ControlPlanePtrOutput{})
+	pulumi.RegisterOutputType(EncryptionOutput{})
+	pulumi.RegisterOutputType(EncryptionPtrOutput{})
+	pulumi.RegisterOutputType(GitOpsOutput{})
+	pulumi.RegisterOutputType(GitOpsPtrOutput{})
+	pulumi.RegisterOutputType(GitOpsCredentialsOutput{})
+	pulumi.RegisterOutputType(GitOpsCredentialsPtrOutput{})
+	pulumi.RegisterOutputType(HubbleOutput{})
+	pulumi.RegisterOutputType(HubblePtrOutput{})
+	pulumi.RegisterOutputType(KubeProxyOutput{})
+	pulumi.RegisterOutputType(KubeProxyPtrOutput{})
+	pulumi.RegisterOutputType(KubeadmOutput{})
+	pulumi.RegisterOutputType(KubeadmPtrOutput{})
+	pulumi.RegisterOutputType(KubeletOutput{})
+	pulumi.RegisterOutputType(KubeletPtrOutput{})
+	pulumi.RegisterOutputType(LoadBalancerOutput{})
+	pulumi.RegisterOutputType(LoadBalancerPtrOutput{})
+	pulumi.RegisterOutputType(ManifestOutput{})
+	pulumi.RegisterOutputType(ManifestArrayOutput{})
+	pulumi.RegisterOutputType(NtpOutput{})
+	pulumi.RegisterOutputType(NtpPtrOutput{})
+	pulumi.RegisterOutputType(OIDCOutput{})
+	pulumi.RegisterOutputType(OIDCPtrOutput{})
+	pulumi.RegisterOutputType(PhaseOutput{})
+	pulumi.RegisterOutputType(PhasePtrOutput{})
+	pulumi.RegisterOutputType(PortMappingOutput{})
+	pulumi.RegisterOutputType(PortMappingMapOutput{})
+	pulumi.RegisterOutputType(ProvisioningOutput{})
+	pulumi.RegisterOutputType(ProvisioningPtrOutput{})
+	pulumi.RegisterOutputType(RegistriesOutput{})
+	pulumi.RegisterOutputType(RegistriesPtrOutput{})
+	pulumi.RegisterOutputType(RegistryOutput{})
+	pulumi.RegisterOutputType(RegistryMapOutput{})
+	pulumi.RegisterOutputType(RegistryCredentialsOutput{})
+	pulumi.RegisterOutputType(RegistryCredentialsMapOutput{})
+	pulumi.RegisterOutputType(ReleaseOutput{})
+	pulumi.RegisterOutputType(ReleaseArrayOutput{})
+	pulumi.RegisterOutputType(WorkerOutput{})
+	pulumi.RegisterOutputType(WorkerPtrOutput{})
+}
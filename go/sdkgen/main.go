@@ -0,0 +1,98 @@
+// generates the SDKs of the component provider from its schema without the pulumi CLI,
+// a module of its own so the codegen dependencies stay out of the plugin and the topology program
+//
+//	sdkgen <schema.json> <out> [go|nodejs|python...]
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	gogen "github.com/pulumi/pulumi/pkg/v3/codegen/go"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/nodejs"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/python"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// generator name written into the headers of the generated files
+const tool = "pulumi-hcloud-kubeadm-sdkgen"
+
+var generators = map[string]func(pkg *schema.Package) (map[string][]byte, error){
+	"go": func(pkg *schema.Package) (map[string][]byte, error) {
+		return gogen.GeneratePackage(tool, pkg)
+	},
+	"nodejs": func(pkg *schema.Package) (map[string][]byte, error) {
+		return nodejs.GeneratePackage(tool, pkg, nil, nil)
+	},
+	"python": func(pkg *schema.Package) (map[string][]byte, error) {
+		return python.GeneratePackage(tool, pkg, nil)
+	},
+}
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "usage: sdkgen <schema.json> <out> [go|nodejs|python...]")
+		os.Exit(2)
+	}
+	languages := os.Args[3:]
+	if len(languages) == 0 {
+		languages = []string{"go", "nodejs", "python"}
+	}
+	if err := generate(os.Args[1], os.Args[2], languages); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// writes the SDK of each language to out/<language>, replacing the previous one
+func generate(schemaFile string, out string, languages []string) error {
+	raw, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return err
+	}
+	var spec schema.PackageSpec
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return fmt.Errorf("%s: %w", schemaFile, err)
+	}
+	for _, language := range languages {
+		gen, ok := generators[language]
+		if !ok {
+			return fmt.Errorf("unknown language %s", language)
+		}
+		// the generators annotate the package, each one binds the schema again
+		pkg, err := schema.ImportSpec(spec, map[string]schema.Language{
+			"go":     gogen.Importer,
+			"nodejs": nodejs.Importer,
+			"python": python.Importer,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", schemaFile, err)
+		}
+		files, err := gen(pkg)
+		if err != nil {
+			return fmt.Errorf("%s SDK: %w", language, err)
+		}
+		dir := filepath.Join(out, language)
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		paths := make([]string, 0, len(files))
+		for path := range files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			target := filepath.Join(dir, path)
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, files[path], 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}